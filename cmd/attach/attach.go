@@ -23,9 +23,10 @@ import (
 
 // AttachOptions contains the option for attach command
 type AttachOptions struct {
-	NoTTY   bool
-	NoStdIn bool
-	PodName string
+	NoTTY         bool
+	NoStdIn       bool
+	PodName       string
+	ContainerName string
 }
 
 // DefaultAttachTimeout ..
@@ -48,7 +49,7 @@ func NewAttachCommand() *cobra.Command {
 
 			jobName := args[0]
 
-			if err := Attach(cmd, jobName, !options.NoStdIn, !options.NoTTY, options.PodName, DefaultAttachTimeout); err != nil {
+			if err := Attach(cmd, jobName, !options.NoStdIn, !options.NoTTY, options.PodName, options.ContainerName, DefaultAttachTimeout); err != nil {
 				log.Errorln(err)
 				os.Exit(1)
 			}
@@ -59,12 +60,13 @@ func NewAttachCommand() *cobra.Command {
 	cmd.Flags().BoolVarP(&(options.NoTTY), "no-tty", "", false, "Not allocated a tty")
 
 	job.AddPodNameFlag(cmd, &(options.PodName))
+	job.AddContainerNameFlag(cmd, &(options.ContainerName))
 
 	return cmd
 }
 
 // Attach attach to a running job name
-func Attach(cmd *cobra.Command, jobName string, stdin, tty bool, podName string, timeout time.Duration) (err error) {
+func Attach(cmd *cobra.Command, jobName string, stdin, tty bool, podName, containerName string, timeout time.Duration) (err error) {
 	kubeClient, err := client.GetClient()
 	if err != nil {
 		return
@@ -82,7 +84,12 @@ func Attach(cmd *cobra.Command, jobName string, stdin, tty bool, podName string,
 		fmt.Println("Connecting to pod", pod.Name)
 	}
 
-	return attachByKubectlLib(pod, stdin, tty)
+	container, err := exec.GetContainer(pod, containerName)
+	if err != nil {
+		return err
+	}
+
+	return AttachToContainer(pod, container, stdin, tty)
 }
 
 // attachByKubeCtlBin attach to a running job name
@@ -90,8 +97,8 @@ func attachByKubeCtlBin(pod *v1.Pod, stdin, tty bool) (err error) {
 	return kubectl.Attach(pod.Name, pod.Namespace, stdin, tty)
 }
 
-// attachByKubectlLib Attach to a running job name
-func attachByKubectlLib(pod *v1.Pod, stdin, tty bool) (err error) {
+// AttachToContainer attaches to a running container of the pod, which may be an ephemeral container
+func AttachToContainer(pod *v1.Pod, containerToAttach *v1.Container, stdin, tty bool) (err error) {
 
 	var sizeQueue remotecommand.TerminalSizeQueue
 	ioStream := genericclioptions.IOStreams{In: os.Stdin, Out: os.Stdout, ErrOut: os.Stderr}
@@ -108,7 +115,6 @@ func attachByKubectlLib(pod *v1.Pod, stdin, tty bool) (err error) {
 	o.Config = restConfig
 
 	t := o.SetupTTY()
-	containerToAttach := &pod.Spec.Containers[0]
 
 	if o.TTY && !containerToAttach.TTY {
 		return fmt.Errorf("Unable to use a TTY - container %s did not allocate one", containerToAttach.Name)
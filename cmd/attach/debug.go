@@ -0,0 +1,219 @@
+package attach
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/run-ai/runai-cli/cmd/exec"
+	"github.com/run-ai/runai-cli/cmd/job"
+	raUtil "github.com/run-ai/runai-cli/cmd/util"
+	"github.com/run-ai/runai-cli/pkg/authentication/assertion"
+	"github.com/run-ai/runai-cli/pkg/client"
+	commandUtil "github.com/run-ai/runai-cli/pkg/util/command"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	utilrand "k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/apimachinery/pkg/version"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	defaultDebugImage = "busybox"
+
+	// DefaultDebugTimeout is the time to wait for the debug container to start, which includes pulling its image
+	DefaultDebugTimeout = time.Minute * 5
+
+	debugExamples = `
+# Get a shell in a busybox container which shares the process namespace of a job, e.g. to run ps or top
+runai debug train1
+
+# Debug a specific container of a specific pod of a job, with another image
+runai debug train1 --pod train1-worker-0 --container main --image ubuntu
+
+# Run a command in the debug container
+runai debug train1 -- ps aux
+`
+)
+
+// imagePullFailures are the reasons of a waiting container whose image cannot be pulled
+var imagePullFailures = map[string]bool{"ErrImagePull": true, "ImagePullBackOff": true, "InvalidImageName": true}
+
+// DebugOptions contains the options of the debug command
+type DebugOptions struct {
+	Image         string
+	NoTTY         bool
+	NoStdIn       bool
+	PodName       string
+	ContainerName string
+}
+
+// NewDebugCommand creates the debug command, which adds an ephemeral container to a pod of a running job
+func NewDebugCommand() *cobra.Command {
+	options := DebugOptions{}
+
+	cmd := &cobra.Command{
+		Use:               "debug JOB_NAME [-- COMMAND [ARG ...]]",
+		Short:             "Debug a running job with an ephemeral container which shares the process namespace of the job.",
+		Long:              "Debug a running job with an ephemeral container which shares the process namespace of the job, for images that have no shell or debugging tools. The ephemeral container stays in the pod until the pod is deleted. Requires a cluster with ephemeral containers enabled.",
+		Example:           debugExamples,
+		ValidArgsFunction: job.GenJobNames,
+		PreRun:            commandUtil.NamespacedRoleAssertion(assertion.AssertExecutorRole),
+		Run: func(cmd *cobra.Command, args []string) {
+			if len(args) == 0 {
+				cmd.HelpFunc()(cmd, args)
+				os.Exit(1)
+			}
+
+			jobName := args[0]
+
+			if err := Debug(cmd, jobName, args[1:], options); err != nil {
+				log.Errorln(err)
+				os.Exit(1)
+			}
+		},
+	}
+
+	cmd.Flags().StringVar(&(options.Image), "image", defaultDebugImage, "The image of the debug container")
+	cmd.Flags().BoolVarP(&(options.NoStdIn), "no-stdin", "", false, "Not pass stdin to the container")
+	cmd.Flags().BoolVarP(&(options.NoTTY), "no-tty", "", false, "Not allocated a tty")
+
+	job.AddPodNameFlag(cmd, &(options.PodName))
+	job.AddContainerNameFlag(cmd, &(options.ContainerName))
+
+	return cmd
+}
+
+// Debug adds an ephemeral container to the pod of the job, targeting a container of the pod, and attaches to it
+func Debug(cmd *cobra.Command, jobName string, command []string, options DebugOptions) error {
+	kubeClient, err := client.GetClient()
+	if err != nil {
+		return err
+	}
+	pod, err := exec.WaitForPodToStartRunning(cmd, kubeClient, jobName, options.PodName, DefaultAttachTimeout)
+	if err != nil {
+		return err
+	}
+
+	target, err := exec.GetContainer(pod, options.ContainerName)
+	if err != nil {
+		return err
+	}
+
+	clientset := kubeClient.GetClientset()
+	serverVersion, err := clientset.Discovery().ServerVersion()
+	if err != nil {
+		return err
+	}
+
+	debugContainer := newDebugContainer(pod, target.Name, options.Image, command, !options.NoStdIn, !options.NoTTY)
+	if err = addEphemeralContainer(clientset, pod, debugContainer, isLegacyEphemeralContainersAPI(serverVersion)); err != nil {
+		return err
+	}
+
+	waitingMsg := fmt.Sprintf("Waiting for container %s to start in pod %s...", debugContainer.Name, pod.Name)
+	pod, err = raUtil.WaitForPod(pod.Name, pod.Namespace, waitingMsg, DefaultDebugTimeout, "Timeout waiting for the debug container to start", ephemeralContainerRunning(debugContainer.Name))
+	if err != nil {
+		return err
+	}
+
+	if options.NoStdIn && options.NoTTY {
+		fmt.Printf("Started container %s in pod %s, to see its output run: runai logs %s --pod %s -c %s\n", debugContainer.Name, pod.Name, jobName, pod.Name, debugContainer.Name)
+		return nil
+	}
+
+	container := v1.Container(debugContainer.EphemeralContainerCommon)
+	return AttachToContainer(pod, &container, !options.NoStdIn, !options.NoTTY)
+}
+
+// newDebugContainer returns an ephemeral container with a unique name, which shares the process namespace of the target
+// container
+func newDebugContainer(pod *v1.Pod, target, image string, command []string, stdin, tty bool) v1.EphemeralContainer {
+	names := map[string]bool{}
+	for _, container := range pod.Spec.EphemeralContainers {
+		names[container.Name] = true
+	}
+	name := ""
+	for name == "" || names[name] {
+		name = "debugger-" + utilrand.String(5)
+	}
+
+	return v1.EphemeralContainer{
+		EphemeralContainerCommon: v1.EphemeralContainerCommon{
+			Name:                     name,
+			Image:                    image,
+			Command:                  command,
+			ImagePullPolicy:          v1.PullIfNotPresent,
+			Stdin:                    stdin,
+			TTY:                      tty,
+			TerminationMessagePolicy: v1.TerminationMessageReadFile,
+		},
+		TargetContainerName: target,
+	}
+}
+
+// isLegacyEphemeralContainersAPI returns whether the ephemeralcontainers subresource of the server is of kind
+// EphemeralContainers, which Kubernetes 1.22 replaced with the pod itself
+func isLegacyEphemeralContainersAPI(serverVersion *version.Info) bool {
+	major, err := strconv.Atoi(serverVersion.Major)
+	if err != nil {
+		return false
+	}
+	minor, err := strconv.Atoi(strings.TrimSuffix(serverVersion.Minor, "+"))
+	if err != nil {
+		return false
+	}
+	return major == 1 && minor < 22
+}
+
+func addEphemeralContainer(clientset kubernetes.Interface, pod *v1.Pod, debugContainer v1.EphemeralContainer, legacy bool) error {
+	pods := clientset.CoreV1().Pods(pod.Namespace)
+	if legacy {
+		ephemeralContainers, err := pods.GetEphemeralContainers(pod.Name, metav1.GetOptions{})
+		if err != nil {
+			return fmt.Errorf("failed to get the ephemeral containers of pod %s, are ephemeral containers enabled in the cluster? %v", pod.Name, err)
+		}
+		ephemeralContainers.EphemeralContainers = append(ephemeralContainers.EphemeralContainers, debugContainer)
+		_, err = pods.UpdateEphemeralContainers(pod.Name, ephemeralContainers)
+		return err
+	}
+
+	patch, err := json.Marshal(map[string]interface{}{
+		"spec": map[string]interface{}{
+			"ephemeralContainers": []v1.EphemeralContainer{debugContainer},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	if _, err = pods.Patch(pod.Name, k8stypes.StrategicMergePatchType, patch, "ephemeralcontainers"); err != nil {
+		return fmt.Errorf("failed to add an ephemeral container to pod %s, are ephemeral containers enabled in the cluster? %v", pod.Name, err)
+	}
+	return nil
+}
+
+func ephemeralContainerRunning(name string) func(*v1.Pod) (bool, error) {
+	return func(pod *v1.Pod) (bool, error) {
+		for _, status := range pod.Status.EphemeralContainerStatuses {
+			if status.Name != name {
+				continue
+			}
+			if status.State.Running != nil {
+				return true, nil
+			}
+			if terminated := status.State.Terminated; terminated != nil {
+				return false, fmt.Errorf("The debug container %s terminated: %s %s", name, terminated.Reason, terminated.Message)
+			}
+			if waiting := status.State.Waiting; waiting != nil && imagePullFailures[waiting.Reason] {
+				return false, fmt.Errorf("The debug container %s failed to start: %s %s", name, waiting.Reason, waiting.Message)
+			}
+		}
+		return false, nil
+	}
+}
@@ -0,0 +1,54 @@
+package attach
+
+import (
+	"strings"
+	"testing"
+
+	"gotest.tools/assert"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/version"
+)
+
+func TestNewDebugContainer(t *testing.T) {
+	pod := &v1.Pod{}
+	pod.Spec.EphemeralContainers = []v1.EphemeralContainer{{EphemeralContainerCommon: v1.EphemeralContainerCommon{Name: "debugger-abcde"}}}
+
+	container := newDebugContainer(pod, "main", "busybox", []string{"ps"}, true, false)
+	assert.Assert(t, strings.HasPrefix(container.Name, "debugger-"))
+	assert.Assert(t, container.Name != "debugger-abcde")
+	assert.Equal(t, container.TargetContainerName, "main")
+	assert.Equal(t, container.Image, "busybox")
+	assert.DeepEqual(t, container.Command, []string{"ps"})
+	assert.Equal(t, container.Stdin, true)
+	assert.Equal(t, container.TTY, false)
+}
+
+func TestIsLegacyEphemeralContainersAPI(t *testing.T) {
+	assert.Equal(t, isLegacyEphemeralContainersAPI(&version.Info{Major: "1", Minor: "18"}), true)
+	assert.Equal(t, isLegacyEphemeralContainersAPI(&version.Info{Major: "1", Minor: "21+"}), true)
+	assert.Equal(t, isLegacyEphemeralContainersAPI(&version.Info{Major: "1", Minor: "22"}), false)
+	assert.Equal(t, isLegacyEphemeralContainersAPI(&version.Info{Major: "1", Minor: "25+"}), false)
+}
+
+func TestEphemeralContainerRunning(t *testing.T) {
+	pod := &v1.Pod{}
+	running := ephemeralContainerRunning("debugger-abcde")
+
+	isRunning, err := running(pod)
+	assert.NilError(t, err)
+	assert.Equal(t, isRunning, false)
+
+	pod.Status.EphemeralContainerStatuses = []v1.ContainerStatus{{Name: "debugger-abcde", State: v1.ContainerState{Waiting: &v1.ContainerStateWaiting{Reason: "ContainerCreating"}}}}
+	isRunning, err = running(pod)
+	assert.NilError(t, err)
+	assert.Equal(t, isRunning, false)
+
+	pod.Status.EphemeralContainerStatuses[0].State = v1.ContainerState{Running: &v1.ContainerStateRunning{}}
+	isRunning, err = running(pod)
+	assert.NilError(t, err)
+	assert.Equal(t, isRunning, true)
+
+	pod.Status.EphemeralContainerStatuses[0].State = v1.ContainerState{Waiting: &v1.ContainerStateWaiting{Reason: "ImagePullBackOff", Message: "not found"}}
+	_, err = running(pod)
+	assert.Error(t, err, "The debug container debugger-abcde failed to start: ImagePullBackOff not found")
+}
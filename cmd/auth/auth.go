@@ -0,0 +1,18 @@
+package auth
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func NewAuthCommand() *cobra.Command {
+	var command = &cobra.Command{
+		Use:   "auth",
+		Short: "Manage the credentials of Run:AI users.",
+		Run: func(cmd *cobra.Command, args []string) {
+			cmd.HelpFunc()(cmd, args)
+		},
+	}
+
+	command.AddCommand(NewExecCredentialCommand())
+	return command
+}
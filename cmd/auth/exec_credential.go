@@ -0,0 +1,40 @@
+package auth
+
+import (
+	"encoding/json"
+	"os"
+
+	"github.com/run-ai/runai-cli/cmd/completion"
+	"github.com/run-ai/runai-cli/pkg/authentication"
+	"github.com/run-ai/runai-cli/pkg/authentication/execcredential"
+	"github.com/run-ai/runai-cli/pkg/authentication/types"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// NewExecCredentialCommand is the exec credential plugin of kubectl, which 'runai login --exec-credential' writes in
+// kubeconfig. It prints the cached id token of the user, and refreshes it when it expires
+func NewExecCredentialCommand() *cobra.Command {
+	params := &types.AuthenticationParams{}
+	var command = &cobra.Command{
+		Use:               "exec-credential",
+		Short:             "Print the credentials of the user in the format of a kubectl exec credential plugin.",
+		ValidArgsFunction: completion.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			idToken, expiry, err := authentication.GetExecCredentialIdToken(params)
+			if err != nil {
+				log.Error(err)
+				os.Exit(1)
+			}
+
+			err = json.NewEncoder(os.Stdout).Encode(execcredential.NewExecCredential(idToken, expiry))
+			if err != nil {
+				log.Error(err)
+				os.Exit(1)
+			}
+		},
+	}
+
+	params.AddExecCredentialFlags(command.Flags())
+	return command
+}
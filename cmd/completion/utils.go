@@ -35,7 +35,7 @@ func ServiceTypeValues(_ *cobra.Command, _ []string, _ string) ([]string, cobra.
 }
 
 func OutputFormatValues(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
-	return []string { "json", "yaml", "wide" }, cobra.ShellCompDirectiveNoFileComp
+	return []string { "json", "yaml", "wide", "name" }, cobra.ShellCompDirectiveNoFileComp
 }
 
 //
@@ -3,6 +3,8 @@ package constants
 	const (
 		RunaiQueueLabel      = "runai/queue"
 		RunaiNsProjectPrefix = "runai-"
+		RunaiSweepLabel      = "runai/sweep"
+		RunaiSweepTrialLabel = "runai/sweep-trial"
 	)
 
 // Same statuses appear in the scheduler - update both if needed
@@ -14,6 +16,7 @@ var Status = struct {
 	Failed    string
 	TimedOut  string
 	Preempted string
+	Suspended string
 	Unknown   string
 }{
 	Running:   "Running",
@@ -23,6 +26,7 @@ var Status = struct {
 	Failed:    "Failed",
 	TimedOut:  "TimedOut",
 	Preempted: "Preempted",
+	Suspended: "Suspended",
 	Unknown:   "Unknown",
 }
 
@@ -47,6 +51,8 @@ const (
 	WorkloadRunningPods          = "runai-running-pods"
 	WorkloadPendingPods          = "runai-pending-pods"
 	WorkloadUsedNodes            = "runai-used-nodes"
+	// The replicas or parallelism of a suspended workload, before it was scaled down to zero
+	WorkloadSuspendedReplicas    = "runai-suspended-replicas"
 	AliyunENIAnnotation          = "k8s.aliyun.com/eni"
 )
 
@@ -0,0 +1,420 @@
+package exec
+
+import (
+	"archive/tar"
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/run-ai/runai-cli/cmd/flags"
+	"github.com/run-ai/runai-cli/cmd/job"
+	"github.com/run-ai/runai-cli/cmd/trainer"
+	raUtil "github.com/run-ai/runai-cli/cmd/util"
+	"github.com/run-ai/runai-cli/pkg/authentication/assertion"
+	"github.com/run-ai/runai-cli/pkg/client"
+	commandUtil "github.com/run-ai/runai-cli/pkg/util/command"
+	"github.com/spf13/cobra"
+	"golang.org/x/crypto/ssh/terminal"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+const (
+	cpExamples = `
+# Copy a directory from the chief pod of a job to the local machine
+runai cp train1:/workspace/checkpoints ./checkpoints
+
+# Copy a local file into a job
+runai cp ./config.yaml train1:/workspace/config.yaml
+
+# Collect the checkpoints of every worker of a distributed job, to ./checkpoints/<pod name>
+runai cp train1:/workspace/checkpoints ./checkpoints --all-pods
+`
+
+	progressInterval = 200 * time.Millisecond
+)
+
+// copySpec is a path in a job, or a local path when the job is not set
+type copySpec struct {
+	job  string
+	path string
+}
+
+func NewCopyCommand() *cobra.Command {
+	var podName string
+	var allPods bool
+
+	var command = &cobra.Command{
+		Use:               "cp SOURCE DESTINATION",
+		Short:             "Copy files and directories to and from a job.",
+		Long:              "Copy files and directories to and from a job, where the path in the job is given as JOB_NAME:PATH. Directories are copied recursively. The job must have the tar binary.",
+		Example:           cpExamples,
+		ValidArgsFunction: job.GenJobNames,
+		Args:              cobra.ExactArgs(2),
+		PreRun:            commandUtil.NamespacedRoleAssertion(assertion.AssertExecutorRole),
+		Run: commandUtil.WrapRunCommand(func(cmd *cobra.Command, args []string) error {
+			if allPods && podName != "" {
+				return fmt.Errorf("the flags --all-pods and --pod cannot be used together")
+			}
+
+			src, dest := parseCopySpec(args[0]), parseCopySpec(args[1])
+			switch {
+			case src.job != "" && dest.job != "":
+				return fmt.Errorf("copying between jobs is not supported, please copy to a local path first")
+			case src.job == "" && dest.job == "":
+				return fmt.Errorf("either the source or the destination should be a path in a job, e.g. %s:%s", "JOB_NAME", src.path)
+			}
+
+			jobName := src.job + dest.job
+			pods, err := getCopyPods(cmd, jobName, podName, allPods)
+			if err != nil {
+				return err
+			}
+
+			for _, pod := range pods {
+				if src.job != "" {
+					localPath := dest.path
+					if allPods {
+						localPath = filepath.Join(dest.path, pod.Name)
+					}
+					err = copyFromPod(pod, src.path, localPath)
+				} else {
+					err = copyToPod(pod, src.path, dest.path)
+				}
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		}),
+	}
+
+	job.AddPodNameFlag(command, &podName)
+	command.Flags().BoolVar(&allPods, "all-pods", false, "Copy to or from all the running pods of the job. When copying from the job, the files of every pod are copied to a directory named after the pod.")
+
+	return command
+}
+
+// parseCopySpec parses JOB_NAME:PATH, or a local path. As kubectl cp, a path whose part before the colon has a slash
+// is local
+func parseCopySpec(arg string) copySpec {
+	parts := strings.SplitN(arg, ":", 2)
+	if len(parts) == 2 && parts[0] != "" && !strings.ContainsAny(parts[0], `/\`) {
+		return copySpec{job: parts[0], path: parts[1]}
+	}
+	return copySpec{path: arg}
+}
+
+func getCopyPods(cmd *cobra.Command, jobName, podName string, allPods bool) ([]*v1.Pod, error) {
+	kubeClient, err := client.GetClient()
+	if err != nil {
+		return nil, err
+	}
+
+	if !allPods {
+		pod, err := GetPodFromCmd(cmd, kubeClient, jobName, podName, DefaultExecTimeout)
+		if err != nil {
+			return nil, err
+		}
+		if isRunning, err := raUtil.PodRunning(pod); err != nil {
+			return nil, err
+		} else if !isRunning {
+			return nil, fmt.Errorf("Unable to copy files of a pod that is not running")
+		}
+		return []*v1.Pod{pod}, nil
+	}
+
+	namespace, err := flags.GetNamespaceToUseFromProjectFlag(cmd, kubeClient)
+	if err != nil {
+		return nil, err
+	}
+	trainingJob, err := trainer.SearchTrainingJob(kubeClient, jobName, "", namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	pods := []*v1.Pod{}
+	for _, pod := range trainingJob.AllPods() {
+		if pod.Status.Phase == v1.PodRunning {
+			runningPod := pod
+			pods = append(pods, &runningPod)
+		}
+	}
+	if len(pods) == 0 {
+		return nil, fmt.Errorf("the job %s has no running pods", jobName)
+	}
+	return pods, nil
+}
+
+// copyFromPod streams a tar of the remote path out of the pod, and extracts it to the local path
+func copyFromPod(pod *v1.Pod, remotePath, localPath string) error {
+	remotePath = path.Clean(remotePath)
+	if remotePath == "/" {
+		return fmt.Errorf("copying the root directory of a pod is not supported")
+	}
+
+	reader, writer := io.Pipe()
+	stderr := &bytes.Buffer{}
+	execErr := make(chan error, 1)
+	go func() {
+		streams := genericclioptions.IOStreams{In: nil, Out: writer, ErrOut: stderr}
+		err := ExecByLibWithStreams(pod, "", []string{"tar", "cf", "-", "-C", path.Dir(remotePath), path.Base(remotePath)}, streams, false, false)
+		writer.CloseWithError(err)
+		execErr <- err
+	}()
+
+	progress := newCopyProgress(fmt.Sprintf("Copying %s:%s to %s", pod.Name, remotePath, localPath))
+	files, err := extractTar(io.TeeReader(reader, progress), path.Base(remotePath), localPath)
+	// Drain the stream, so that the command in the pod completes
+	_, _ = io.Copy(ioutil.Discard, reader)
+	if remoteErr := <-execErr; remoteErr != nil {
+		return fmt.Errorf("failed to copy %s from pod %s: %v %s", remotePath, pod.Name, remoteErr, strings.TrimSpace(stderr.String()))
+	}
+	if err != nil {
+		return err
+	}
+	progress.done(files)
+	return nil
+}
+
+// copyToPod streams a tar of the local path into the pod, extracting it to the remote path
+func copyToPod(pod *v1.Pod, localPath, remotePath string) error {
+	if _, err := os.Stat(localPath); err != nil {
+		return err
+	}
+	remoteDir, remoteName := path.Dir(remotePath), path.Base(remotePath)
+	if strings.HasSuffix(remotePath, "/") {
+		// Copy into the remote directory, keeping the local name
+		remoteDir, remoteName = path.Clean(remotePath), filepath.Base(localPath)
+	}
+
+	progress := newCopyProgress(fmt.Sprintf("Copying %s to %s:%s", localPath, pod.Name, path.Join(remoteDir, remoteName)))
+	reader, writer := io.Pipe()
+	files := 0
+	go func() {
+		var err error
+		files, err = writeTar(io.MultiWriter(writer, progress), localPath, remoteName)
+		writer.CloseWithError(err)
+	}()
+
+	stderr := &bytes.Buffer{}
+	streams := genericclioptions.IOStreams{In: reader, Out: ioutil.Discard, ErrOut: stderr}
+	if err := ExecByLibWithStreams(pod, "", []string{"tar", "xmf", "-", "-C", remoteDir}, streams, true, false); err != nil {
+		return fmt.Errorf("failed to copy %s to pod %s: %v %s", localPath, pod.Name, err, strings.TrimSpace(stderr.String()))
+	}
+	progress.done(files)
+	return nil
+}
+
+// writeTar writes the local file or directory to the tar stream, named as the given name, and returns the number of
+// files written
+func writeTar(w io.Writer, localPath, name string) (int, error) {
+	tarWriter := tar.NewWriter(w)
+	files := 0
+	localPath = filepath.Clean(localPath)
+	err := filepath.Walk(localPath, func(filePath string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		relativePath, err := filepath.Rel(localPath, filePath)
+		if err != nil {
+			return err
+		}
+
+		isFile, err := writeTarEntry(tarWriter, filePath, path.Join(name, filepath.ToSlash(relativePath)), info)
+		if isFile {
+			files++
+		}
+		return err
+	})
+	if err != nil {
+		return files, err
+	}
+	return files, tarWriter.Close()
+}
+
+// writeTarEntry writes the local file, directory or symbolic link to the tar stream, named as the given name, and
+// returns whether it is a regular file
+func writeTarEntry(tarWriter *tar.Writer, filePath, name string, info os.FileInfo) (bool, error) {
+	link := ""
+	if info.Mode()&os.ModeSymlink != 0 {
+		var err error
+		if link, err = os.Readlink(filePath); err != nil {
+			return false, err
+		}
+	}
+	header, err := tar.FileInfoHeader(info, link)
+	if err != nil {
+		return false, err
+	}
+	header.Name = name
+	if info.IsDir() {
+		header.Name += "/"
+	}
+	if err = tarWriter.WriteHeader(header); err != nil {
+		return false, err
+	}
+	if !info.Mode().IsRegular() {
+		return false, nil
+	}
+
+	file, err := os.Open(filePath)
+	if err != nil {
+		return false, err
+	}
+	defer file.Close()
+	if _, err = io.Copy(tarWriter, file); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// extractTar extracts the entries of the tar stream under the given name to the local path, as cp does: into the local
+// path when it is an existing directory, and as the local path otherwise. It returns the number of files extracted
+func extractTar(r io.Reader, name, localPath string) (int, error) {
+	if info, err := os.Stat(localPath); err == nil && info.IsDir() {
+		localPath = filepath.Join(localPath, name)
+	}
+
+	files := 0
+	tarReader := tar.NewReader(r)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			return files, nil
+		} else if err != nil {
+			return files, err
+		}
+
+		entryName := path.Clean(header.Name)
+		if entryName != name && !strings.HasPrefix(entryName, name+"/") {
+			continue
+		}
+		relativePath := strings.TrimPrefix(strings.TrimPrefix(entryName, name), "/")
+		if relativePath == ".." || strings.HasPrefix(relativePath, "../") {
+			return files, fmt.Errorf("refusing to extract %s outside of %s", header.Name, localPath)
+		}
+		target := filepath.Join(localPath, filepath.FromSlash(relativePath))
+		// A symbolic link of the archive, or one which already exists locally, must not redirect the entries under it
+		if err = checkNoSymlinks(localPath, path.Dir(relativePath)); err != nil {
+			return files, err
+		}
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err = checkNoSymlinks(localPath, relativePath); err != nil {
+				return files, err
+			}
+			if err = os.MkdirAll(target, os.FileMode(header.Mode)|0700); err != nil {
+				return files, err
+			}
+		case tar.TypeReg:
+			if err = extractFile(tarReader, target, os.FileMode(header.Mode)); err != nil {
+				return files, err
+			}
+			files++
+		case tar.TypeSymlink:
+			linkTarget := filepath.Join(filepath.Dir(target), filepath.FromSlash(header.Linkname))
+			if filepath.IsAbs(header.Linkname) || !withinDir(localPath, linkTarget) {
+				return files, fmt.Errorf("refusing to extract the symbolic link %s to %s, outside of %s", header.Name, header.Linkname, localPath)
+			}
+			if err = os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return files, err
+			}
+			if err = os.Symlink(header.Linkname, target); err != nil && !os.IsExist(err) {
+				return files, err
+			}
+		}
+	}
+}
+
+// checkNoSymlinks returns an error if any component of the slash-separated path under the local path is a symbolic
+// link, since extracting through it could write outside of the local path
+func checkNoSymlinks(localPath, relativePath string) error {
+	current := localPath
+	for _, component := range strings.Split(relativePath, "/") {
+		if component == "" || component == "." {
+			continue
+		}
+		current = filepath.Join(current, component)
+		info, err := os.Lstat(current)
+		if os.IsNotExist(err) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		if info.Mode()&os.ModeSymlink != 0 {
+			return fmt.Errorf("refusing to extract %s through the symbolic link %s", relativePath, current)
+		}
+	}
+	return nil
+}
+
+// withinDir returns whether the path is the directory or is under it
+func withinDir(dir, filePath string) bool {
+	relativePath, err := filepath.Rel(dir, filePath)
+	return err == nil && relativePath != ".." && !strings.HasPrefix(relativePath, ".."+string(filepath.Separator))
+}
+
+func extractFile(r io.Reader, target string, mode os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return err
+	}
+	if info, err := os.Lstat(target); err == nil && info.Mode()&os.ModeSymlink != 0 {
+		return fmt.Errorf("refusing to overwrite the symbolic link %s", target)
+	}
+	file, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	_, err = io.Copy(file, r)
+	return err
+}
+
+// copyProgress prints the bytes copied so far to stderr when it is a terminal, and a summary when the copy is done
+type copyProgress struct {
+	message     string
+	bytes       int64
+	interactive bool
+	lastPrinted time.Time
+}
+
+func newCopyProgress(message string) *copyProgress {
+	return &copyProgress{message: message, interactive: terminal.IsTerminal(int(os.Stderr.Fd()))}
+}
+
+func (p *copyProgress) Write(data []byte) (int, error) {
+	p.bytes += int64(len(data))
+	if p.interactive && time.Since(p.lastPrinted) > progressInterval {
+		fmt.Fprintf(os.Stderr, "\r%s: %s", p.message, formatBytes(p.bytes))
+		p.lastPrinted = time.Now()
+	}
+	return len(data), nil
+}
+
+func (p *copyProgress) done(files int) {
+	if p.interactive {
+		fmt.Fprint(os.Stderr, "\r")
+	}
+	fmt.Fprintf(os.Stderr, "%s: %d files, %s\n", p.message, files, formatBytes(p.bytes))
+}
+
+func formatBytes(bytes int64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+	div, exp := int64(unit), 0
+	for n := bytes / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(bytes)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,150 @@
+package exec
+
+import (
+	"archive/tar"
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+func TestParseCopySpec(t *testing.T) {
+	assert.Equal(t, parseCopySpec("train1:/workspace/out"), copySpec{job: "train1", path: "/workspace/out"})
+	assert.Equal(t, parseCopySpec("./out"), copySpec{path: "./out"})
+	assert.Equal(t, parseCopySpec("./dir:with-colon"), copySpec{path: "./dir:with-colon"})
+	assert.Equal(t, parseCopySpec(":/workspace"), copySpec{path: ":/workspace"})
+}
+
+func TestCopyTarRoundTrip(t *testing.T) {
+	src, err := ioutil.TempDir("", "runai-cp-src")
+	assert.NilError(t, err)
+	defer os.RemoveAll(src)
+	assert.NilError(t, os.MkdirAll(filepath.Join(src, "ckpt", "step-1"), 0755))
+	assert.NilError(t, ioutil.WriteFile(filepath.Join(src, "ckpt", "model.pt"), []byte("weights"), 0644))
+	assert.NilError(t, ioutil.WriteFile(filepath.Join(src, "ckpt", "step-1", "optimizer.pt"), []byte("state"), 0600))
+
+	archive := &bytes.Buffer{}
+	files, err := writeTar(archive, filepath.Join(src, "ckpt"), "ckpt")
+	assert.NilError(t, err)
+	assert.Equal(t, files, 2)
+
+	dest, err := ioutil.TempDir("", "runai-cp-dest")
+	assert.NilError(t, err)
+	defer os.RemoveAll(dest)
+
+	// An existing directory gets the copy under the source name, as cp does
+	files, err = extractTar(bytes.NewReader(archive.Bytes()), "ckpt", dest)
+	assert.NilError(t, err)
+	assert.Equal(t, files, 2)
+	content, err := ioutil.ReadFile(filepath.Join(dest, "ckpt", "step-1", "optimizer.pt"))
+	assert.NilError(t, err)
+	assert.Equal(t, string(content), "state")
+
+	// A new path is the copy itself
+	_, err = extractTar(bytes.NewReader(archive.Bytes()), "ckpt", filepath.Join(dest, "renamed"))
+	assert.NilError(t, err)
+	content, err = ioutil.ReadFile(filepath.Join(dest, "renamed", "model.pt"))
+	assert.NilError(t, err)
+	assert.Equal(t, string(content), "weights")
+}
+
+func TestExtractTarOutsideOfDestination(t *testing.T) {
+	archive := &bytes.Buffer{}
+	tarWriter := tar.NewWriter(archive)
+	assert.NilError(t, tarWriter.WriteHeader(&tar.Header{Name: "ckpt/../../evil", Mode: 0644, Size: 4, Typeflag: tar.TypeReg}))
+	_, err := tarWriter.Write([]byte("evil"))
+	assert.NilError(t, err)
+	assert.NilError(t, tarWriter.Close())
+
+	dest, err := ioutil.TempDir("", "runai-cp-dest")
+	assert.NilError(t, err)
+	defer os.RemoveAll(dest)
+
+	files, err := extractTar(archive, "ckpt", filepath.Join(dest, "out"))
+	assert.NilError(t, err)
+	assert.Equal(t, files, 0)
+	_, err = os.Stat(filepath.Join(dest, "evil"))
+	assert.Assert(t, os.IsNotExist(err))
+}
+
+type testTarEntry struct {
+	header  tar.Header
+	content string
+}
+
+func newTestTar(t *testing.T, entries ...testTarEntry) *bytes.Buffer {
+	archive := &bytes.Buffer{}
+	tarWriter := tar.NewWriter(archive)
+	for _, entry := range entries {
+		entry.header.Size = int64(len(entry.content))
+		assert.NilError(t, tarWriter.WriteHeader(&entry.header))
+		_, err := tarWriter.Write([]byte(entry.content))
+		assert.NilError(t, err)
+	}
+	assert.NilError(t, tarWriter.Close())
+	return archive
+}
+
+func TestExtractTarSymlinks(t *testing.T) {
+	dest, err := ioutil.TempDir("", "runai-cp-dest")
+	assert.NilError(t, err)
+	defer os.RemoveAll(dest)
+	localPath := filepath.Join(dest, "out")
+
+	// A link within the destination is extracted as is
+	archive := newTestTar(t,
+		testTarEntry{header: tar.Header{Name: "ckpt/model.pt", Mode: 0644, Typeflag: tar.TypeReg}, content: "weights"},
+		testTarEntry{header: tar.Header{Name: "ckpt/latest.pt", Linkname: "model.pt", Typeflag: tar.TypeSymlink}},
+	)
+	files, err := extractTar(archive, "ckpt", localPath)
+	assert.NilError(t, err)
+	assert.Equal(t, files, 1)
+	link, err := os.Readlink(filepath.Join(localPath, "latest.pt"))
+	assert.NilError(t, err)
+	assert.Equal(t, link, "model.pt")
+
+	for _, linkname := range []string{"../../outside", "/etc", "sub/../../.."} {
+		archive = newTestTar(t, testTarEntry{header: tar.Header{Name: "ckpt/escape", Linkname: linkname, Typeflag: tar.TypeSymlink}})
+		_, err = extractTar(archive, "ckpt", localPath)
+		assert.ErrorContains(t, err, "refusing to extract the symbolic link")
+	}
+}
+
+func TestExtractTarThroughSymlink(t *testing.T) {
+	dest, err := ioutil.TempDir("", "runai-cp-dest")
+	assert.NilError(t, err)
+	defer os.RemoveAll(dest)
+	outside, err := ioutil.TempDir("", "runai-cp-outside")
+	assert.NilError(t, err)
+	defer os.RemoveAll(outside)
+	localPath := filepath.Join(dest, "out")
+
+	// A link of the archive is not written through, even when it points within the destination
+	archive := newTestTar(t,
+		testTarEntry{header: tar.Header{Name: "ckpt/sub", Mode: 0755, Typeflag: tar.TypeDir}},
+		testTarEntry{header: tar.Header{Name: "ckpt/dir", Linkname: "sub", Typeflag: tar.TypeSymlink}},
+		testTarEntry{header: tar.Header{Name: "ckpt/dir/evil", Mode: 0644, Typeflag: tar.TypeReg}, content: "evil"},
+	)
+	_, err = extractTar(archive, "ckpt", localPath)
+	assert.ErrorContains(t, err, "through the symbolic link")
+
+	// Nor is a link which already exists in the destination, to a file or to a directory. The destination is an
+	// existing directory, so the copy is extracted under it, as cp does
+	assert.NilError(t, os.MkdirAll(filepath.Join(dest, "ckpt"), 0755))
+	assert.NilError(t, os.Symlink(outside, filepath.Join(dest, "ckpt", "existing")))
+	for _, entry := range []testTarEntry{
+		{header: tar.Header{Name: "ckpt/existing/evil", Mode: 0644, Typeflag: tar.TypeReg}, content: "evil"},
+		{header: tar.Header{Name: "ckpt/existing/nested/evil", Mode: 0644, Typeflag: tar.TypeReg}, content: "evil"},
+		{header: tar.Header{Name: "ckpt/existing", Mode: 0755, Typeflag: tar.TypeDir}},
+		{header: tar.Header{Name: "ckpt/existing/nested", Mode: 0755, Typeflag: tar.TypeDir}},
+	} {
+		_, err = extractTar(newTestTar(t, entry), "ckpt", dest)
+		assert.ErrorContains(t, err, "through the symbolic link")
+	}
+	entries, err := ioutil.ReadDir(outside)
+	assert.NilError(t, err)
+	assert.Equal(t, len(entries), 0)
+}
@@ -32,10 +32,14 @@ import (
 
 const (
 	DefaultExecTimeout = time.Second * 30
+
+	// DefaultContainerAnnotation is the annotation of the container which kubectl exec, attach and logs use by default
+	DefaultContainerAnnotation = "kubectl.kubernetes.io/default-container"
 )
 
 func NewBashCommand() *cobra.Command {
 	var podName string
+	var containerName string
 	var command = &cobra.Command{
 		Use:    "bash JOB_NAME",
 		Short:  "Get a bash session inside a running job.",
@@ -49,7 +53,7 @@ func NewBashCommand() *cobra.Command {
 
 			name := args[0]
 
-			if err := Exec(cmd, name, []string{"/bin/bash"}, []string{}, DefaultExecTimeout, true, true, podName, "bash"); err != nil {
+			if err := Exec(cmd, name, []string{"/bin/bash"}, []string{}, DefaultExecTimeout, true, true, podName, containerName, "bash"); err != nil {
 				log.Error(err)
 				os.Exit(1)
 			}
@@ -57,6 +61,7 @@ func NewBashCommand() *cobra.Command {
 	}
 
 	job.AddPodNameFlag(command, &podName)
+	job.AddContainerNameFlag(command, &containerName)
 
 	return command
 }
@@ -65,6 +70,7 @@ func NewExecCommand() *cobra.Command {
 	var interactive bool
 	var TTY bool
 	var podName string
+	var containerName string
 	var fileNames []string
 
 	var command = &cobra.Command{
@@ -78,7 +84,7 @@ func NewExecCommand() *cobra.Command {
 			name := args[0]
 			command := args[1:]
 
-			if err := Exec(cmd, name, command, fileNames, DefaultExecTimeout, interactive, TTY, podName, "exec"); err != nil {
+			if err := Exec(cmd, name, command, fileNames, DefaultExecTimeout, interactive, TTY, podName, containerName, "exec"); err != nil {
 				log.Error(err)
 				os.Exit(1)
 			}
@@ -86,6 +92,7 @@ func NewExecCommand() *cobra.Command {
 	}
 
 	job.AddPodNameFlag(command, &podName)
+	job.AddContainerNameFlag(command, &containerName)
 
 	command.Flags().BoolVarP(&interactive, "stdin", "i", false, "Pass stdin to the container")
 	command.Flags().BoolVarP(&TTY, "tty", "t", false, "Stdin is a TTY")
@@ -179,7 +186,7 @@ func WaitForPodCreation(podName, jobName string, namespace types.NamespaceInfo,
 	return
 }
 
-func Exec(cmd *cobra.Command, jobName string, command, fileNames []string, timeout time.Duration, interactive bool, TTY bool, podName string, runaiCommandName string) (err error) {
+func Exec(cmd *cobra.Command, jobName string, command, fileNames []string, timeout time.Duration, interactive bool, TTY bool, podName, containerName string, runaiCommandName string) (err error) {
 
 	kubeClient, err := client.GetClient()
 	if err != nil {
@@ -201,12 +208,42 @@ func Exec(cmd *cobra.Command, jobName string, command, fileNames []string, timeo
 		return
 	}
 
-	return ExecByLib(pod, command, interactive, TTY)
+	return ExecByLib(pod, containerName, command, interactive, TTY)
 
 }
 
-func ExecByLib(pod *v1.Pod, command []string, stdin, tty bool) error {
+// GetContainer returns the container of the pod with the given name, or the default container of the pod when the
+// name is empty
+func GetContainer(pod *v1.Pod, containerName string) (*v1.Container, error) {
+	if containerName == "" {
+		containerName = pod.Annotations[DefaultContainerAnnotation]
+	}
+	if containerName == "" {
+		return &pod.Spec.Containers[0], nil
+	}
+
+	names := []string{}
+	for i, container := range pod.Spec.Containers {
+		if container.Name == containerName {
+			return &pod.Spec.Containers[i], nil
+		}
+		names = append(names, container.Name)
+	}
+	return nil, fmt.Errorf("The pod %s has no container named %s, choose one of: %s", pod.Name, containerName, strings.Join(names, ", "))
+}
+
+func ExecByLib(pod *v1.Pod, containerName string, command []string, stdin, tty bool) error {
 	ioStream := genericclioptions.IOStreams{In: os.Stdin, Out: os.Stdout, ErrOut: os.Stderr}
+	return ExecByLibWithStreams(pod, containerName, command, ioStream, stdin, tty)
+}
+
+// ExecByLibWithStreams executes a command in the pod like ExecByLib, with the given streams rather than the terminal
+func ExecByLibWithStreams(pod *v1.Pod, containerName string, command []string, ioStream genericclioptions.IOStreams, stdin, tty bool) error {
+	containerToAttach, err := GetContainer(pod, containerName)
+	if err != nil {
+		return err
+	}
+
 	kubeConfigFlags := genericclioptions.NewConfigFlags(true).WithDeprecatedPasswordFlag()
 	matchVersionKubeConfigFlags := cmdutil.NewMatchVersionFlags(kubeConfigFlags)
 	restConfig, _ := matchVersionKubeConfigFlags.ToRESTConfig()
@@ -226,7 +263,6 @@ func ExecByLib(pod *v1.Pod, command []string, stdin, tty bool) error {
 		Executor: &kubeExec.DefaultRemoteExecutor{},
 	}
 
-	containerToAttach := &pod.Spec.Containers[0]
 	t := o.SetupTTY()
 
 	var sizeQueue remotecommand.TerminalSizeQueue
@@ -262,7 +298,7 @@ func ExecByLib(pod *v1.Pod, command []string, stdin, tty bool) error {
 
 		return o.Executor.Execute("POST", req.URL(), o.Config, o.In, o.Out, o.ErrOut, t.Raw, sizeQueue)
 	}
-	err := t.Safe(fn)
+	err = t.Safe(fn)
 	// check if the user exit with exit command
 	// todo: use a better error handler like cmdutil.CheckErr
 	if err != nil && strings.Contains(err.Error(), "terminated with exit code 130") {
@@ -0,0 +1,44 @@
+package exec
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newTestPod(annotations map[string]string, containerNames ...string) *v1.Pod {
+	pod := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "train1-0", Annotations: annotations}}
+	for _, name := range containerNames {
+		pod.Spec.Containers = append(pod.Spec.Containers, v1.Container{Name: name})
+	}
+	return pod
+}
+
+func TestGetContainer(t *testing.T) {
+	pod := newTestPod(nil, "git-sync", "main")
+
+	container, err := GetContainer(pod, "")
+	assert.NilError(t, err)
+	assert.Equal(t, container.Name, "git-sync")
+
+	container, err = GetContainer(pod, "main")
+	assert.NilError(t, err)
+	assert.Equal(t, container.Name, "main")
+
+	_, err = GetContainer(pod, "sidecar")
+	assert.Error(t, err, "The pod train1-0 has no container named sidecar, choose one of: git-sync, main")
+}
+
+func TestGetContainerWithDefaultContainerAnnotation(t *testing.T) {
+	pod := newTestPod(map[string]string{DefaultContainerAnnotation: "main"}, "git-sync", "main")
+
+	container, err := GetContainer(pod, "")
+	assert.NilError(t, err)
+	assert.Equal(t, container.Name, "main")
+
+	container, err = GetContainer(pod, "git-sync")
+	assert.NilError(t, err)
+	assert.Equal(t, container.Name, "git-sync")
+}
@@ -0,0 +1,317 @@
+package exec
+
+import (
+	"archive/tar"
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/run-ai/runai-cli/cmd/job"
+	raUtil "github.com/run-ai/runai-cli/cmd/util"
+	"github.com/run-ai/runai-cli/pkg/authentication/assertion"
+	"github.com/run-ai/runai-cli/pkg/client"
+	commandUtil "github.com/run-ai/runai-cli/pkg/util/command"
+	"github.com/run-ai/runai-cli/pkg/util/gitignore"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+const (
+	syncExamples = `
+# Push the local ./src directory to /workspace in a job, and keep pushing its changes until interrupted
+runai sync build1 ./src:/workspace
+
+# Push the directory once, e.g. before running a command with 'runai exec'
+runai sync build1 ./src:/workspace --once
+
+# Also delete the files from the job when they are deleted from the local directory
+runai sync build1 ./src:/workspace --delete
+`
+
+	defaultSyncInterval = time.Second
+)
+
+// fileState is the state of a local file which decides whether it changed since the last sync
+type fileState struct {
+	isDir   bool
+	size    int64
+	mode    os.FileMode
+	modTime time.Time
+}
+
+// syncChanges are the paths, relative to the synced directory, which changed since the last sync
+type syncChanges struct {
+	updated []string
+	deleted []string
+}
+
+func (sc syncChanges) empty() bool {
+	return len(sc.updated) == 0 && len(sc.deleted) == 0
+}
+
+func NewSyncCommand() *cobra.Command {
+	var podName string
+	var containerName string
+	var once bool
+	var deleteFiles bool
+	var interval time.Duration
+
+	var command = &cobra.Command{
+		Use:               "sync JOB_NAME LOCAL_DIR:REMOTE_DIR",
+		Short:             "Push a local directory to a running job, and keep pushing its changes.",
+		Long:              "Push a local directory to a running job, and keep pushing its changes until interrupted. The paths which the .gitignore files of the directory ignore, and .git, are not pushed. When the pod of the job is recreated, the directory is pushed to the new pod. The job must have the tar binary.",
+		Example:           syncExamples,
+		ValidArgsFunction: job.GenJobNames,
+		Args:              cobra.ExactArgs(2),
+		PreRun:            commandUtil.NamespacedRoleAssertion(assertion.AssertExecutorRole),
+		Run: commandUtil.WrapRunCommand(func(cmd *cobra.Command, args []string) error {
+			localDir, remoteDir, err := parseSyncDirs(args[1])
+			if err != nil {
+				return err
+			}
+			if info, err := os.Stat(localDir); err != nil {
+				return err
+			} else if !info.IsDir() {
+				return fmt.Errorf("%s is not a directory", localDir)
+			}
+
+			kubeClient, err := client.GetClient()
+			if err != nil {
+				return err
+			}
+
+			syncer := codeSyncer{localDir: localDir, remoteDir: remoteDir, containerName: containerName, deleteFiles: deleteFiles}
+			for {
+				pod, err := GetPodFromCmd(cmd, kubeClient, args[0], podName, DefaultExecTimeout)
+				if err == nil {
+					if isRunning, _ := raUtil.PodRunning(pod); !isRunning {
+						err = fmt.Errorf("the pod %s is not running", pod.Name)
+					}
+				}
+				if err == nil {
+					err = syncer.run(pod.Name, func(command []string, in io.Reader) error {
+						return execSyncCommand(pod, containerName, command, in)
+					}, once, interval)
+				}
+				if once || err == nil {
+					return err
+				}
+
+				// The pod may have been recreated, e.g. after it was preempted, so the directory is pushed again
+				log.Warnf("Failed to sync, retrying in %s: %v", interval*5, err)
+				syncer.synced = nil
+				time.Sleep(interval * 5)
+			}
+		}),
+	}
+
+	job.AddPodNameFlag(command, &podName)
+	job.AddContainerNameFlag(command, &containerName)
+	command.Flags().BoolVar(&once, "once", false, "Push the directory once and exit, rather than pushing its changes until interrupted.")
+	command.Flags().BoolVar(&deleteFiles, "delete", false, "Delete the files from the job when they are deleted from the local directory.")
+	command.Flags().DurationVar(&interval, "interval", defaultSyncInterval, "The interval between checks of the local directory for changes.")
+
+	return command
+}
+
+// parseSyncDirs parses LOCAL_DIR:REMOTE_DIR. The remote directory is after the last colon, so that the local
+// directory may have a drive letter
+func parseSyncDirs(arg string) (string, string, error) {
+	index := strings.LastIndex(arg, ":")
+	if index <= 0 || index == len(arg)-1 {
+		return "", "", fmt.Errorf("expected LOCAL_DIR:REMOTE_DIR, got '%s'", arg)
+	}
+	return arg[:index], path.Clean(arg[index+1:]), nil
+}
+
+// syncExecutor runs a command in the pod, with the given input
+type syncExecutor func(command []string, in io.Reader) error
+
+type codeSyncer struct {
+	localDir      string
+	remoteDir     string
+	containerName string
+	deleteFiles   bool
+
+	// synced is the state of the files when they were last pushed, or nil before the first push to the pod
+	synced map[string]fileState
+}
+
+// run pushes the whole directory to the pod, and then its changes every interval unless once is set
+func (cs *codeSyncer) run(podName string, execute syncExecutor, once bool, interval time.Duration) error {
+	if cs.synced == nil {
+		fmt.Printf("Syncing %s to %s:%s\n", cs.localDir, podName, cs.remoteDir)
+	}
+	for {
+		if err := cs.syncOnce(execute); err != nil {
+			return err
+		}
+		if once {
+			return nil
+		}
+		time.Sleep(interval)
+	}
+}
+
+// syncOnce pushes the changes of the local directory since the last sync
+func (cs *codeSyncer) syncOnce(execute syncExecutor) error {
+	current, err := snapshotDir(cs.localDir)
+	if err != nil {
+		return err
+	}
+	changes := diffSnapshots(cs.synced, current)
+	if cs.deleteFiles {
+		changes.deleted = removedPaths(cs.localDir, changes.deleted)
+	} else {
+		changes.deleted = nil
+	}
+	if cs.synced != nil && changes.empty() {
+		return nil
+	}
+
+	progress := &copyProgress{}
+	files := 0
+	if len(changes.updated) > 0 || cs.synced == nil {
+		reader, writer := io.Pipe()
+		go func() {
+			var err error
+			files, err = writeTarEntries(io.MultiWriter(writer, progress), cs.localDir, changes.updated)
+			writer.CloseWithError(err)
+		}()
+		// The remote directory is passed as $0, so that it is not parsed by the shell
+		if err = execute([]string{"sh", "-c", `mkdir -p "$0" && tar xf - -C "$0"`, cs.remoteDir}, reader); err != nil {
+			return err
+		}
+	}
+	if len(changes.deleted) > 0 {
+		command := append([]string{"sh", "-c", `cd "$0" && rm -rf -- "$@"`, cs.remoteDir}, changes.deleted...)
+		if err = execute(command, nil); err != nil {
+			return err
+		}
+	}
+
+	cs.synced = current
+	summary := fmt.Sprintf("[%s] Synced %d files, %s", time.Now().Format("15:04:05"), files, formatBytes(progress.bytes))
+	if len(changes.deleted) > 0 {
+		summary += fmt.Sprintf(", deleted %d paths", len(changes.deleted))
+	}
+	fmt.Println(summary)
+	return nil
+}
+
+// removedPaths returns the paths which no longer exist in the local directory, and not those which are only missing
+// from its snapshot since they became ignored
+func removedPaths(dir string, paths []string) []string {
+	removed := []string{}
+	for _, filePath := range paths {
+		if _, err := os.Lstat(filepath.Join(dir, filepath.FromSlash(filePath))); os.IsNotExist(err) {
+			removed = append(removed, filePath)
+		}
+	}
+	return removed
+}
+
+func execSyncCommand(pod *v1.Pod, containerName string, command []string, in io.Reader) error {
+	stderr := &bytes.Buffer{}
+	streams := genericclioptions.IOStreams{In: in, Out: ioutil.Discard, ErrOut: stderr}
+	if err := ExecByLibWithStreams(pod, containerName, command, streams, in != nil, false); err != nil {
+		return fmt.Errorf("%v %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+// snapshotDir returns the state of the files of the directory which are not ignored, by their slash-separated path
+// relative to the directory
+func snapshotDir(dir string) (map[string]fileState, error) {
+	dir = filepath.Clean(dir)
+	matcher := gitignore.New()
+	snapshot := map[string]fileState{}
+	err := filepath.Walk(dir, func(filePath string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		relativePath, err := filepath.Rel(dir, filePath)
+		if err != nil {
+			return err
+		}
+		relativePath = filepath.ToSlash(relativePath)
+
+		if relativePath != "." {
+			if info.Name() == ".git" || matcher.Match(relativePath, info.IsDir()) {
+				if info.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			snapshot[relativePath] = fileState{isDir: info.IsDir(), size: info.Size(), mode: info.Mode(), modTime: info.ModTime()}
+		}
+
+		if info.IsDir() {
+			base := relativePath
+			if base == "." {
+				base = ""
+			}
+			return matcher.ReadFile(base, filepath.Join(filePath, gitignore.FileName))
+		}
+		return nil
+	})
+	return snapshot, err
+}
+
+// diffSnapshots returns the paths which were added or modified since the previous snapshot, and the paths which were
+// deleted, without the paths under deleted directories
+func diffSnapshots(previous, current map[string]fileState) syncChanges {
+	changes := syncChanges{updated: []string{}, deleted: []string{}}
+	for filePath, state := range current {
+		previousState, found := previous[filePath]
+		if !found || previousState.isDir != state.isDir || previousState.mode != state.mode ||
+			(!state.isDir && (previousState.size != state.size || !previousState.modTime.Equal(state.modTime))) {
+			changes.updated = append(changes.updated, filePath)
+		}
+	}
+	for filePath := range previous {
+		if _, found := current[filePath]; !found {
+			if _, parentFound := current[path.Dir(filePath)]; parentFound || path.Dir(filePath) == "." {
+				changes.deleted = append(changes.deleted, filePath)
+			}
+		}
+	}
+	// The directories are created before their files
+	sort.Strings(changes.updated)
+	sort.Strings(changes.deleted)
+	return changes
+}
+
+// writeTarEntries writes the paths of the directory to the tar stream, without the contents of directories, and
+// returns the number of files written
+func writeTarEntries(w io.Writer, dir string, paths []string) (int, error) {
+	tarWriter := tar.NewWriter(w)
+	files := 0
+	for _, relativePath := range paths {
+		filePath := filepath.Join(dir, filepath.FromSlash(relativePath))
+		info, err := os.Lstat(filePath)
+		if os.IsNotExist(err) {
+			// Deleted since the snapshot, it is deleted from the pod on the next sync
+			continue
+		} else if err != nil {
+			return files, err
+		}
+		isFile, err := writeTarEntry(tarWriter, filePath, relativePath, info)
+		if err != nil {
+			return files, err
+		}
+		if isFile {
+			files++
+		}
+	}
+	return files, tarWriter.Close()
+}
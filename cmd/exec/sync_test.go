@@ -0,0 +1,185 @@
+package exec
+
+import (
+	"archive/tar"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+)
+
+func TestParseSyncDirs(t *testing.T) {
+	localDir, remoteDir, err := parseSyncDirs("./src:/workspace/")
+	assert.NilError(t, err)
+	assert.Equal(t, localDir, "./src")
+	assert.Equal(t, remoteDir, "/workspace")
+
+	localDir, remoteDir, err = parseSyncDirs(`C:\src:/workspace`)
+	assert.NilError(t, err)
+	assert.Equal(t, localDir, `C:\src`)
+	assert.Equal(t, remoteDir, "/workspace")
+
+	for _, arg := range []string{"./src", "./src:", ":/workspace"} {
+		_, _, err = parseSyncDirs(arg)
+		assert.ErrorContains(t, err, "expected LOCAL_DIR:REMOTE_DIR")
+	}
+}
+
+func writeSyncTestFiles(t *testing.T, dir string, files map[string]string) {
+	for name, content := range files {
+		filePath := filepath.Join(dir, filepath.FromSlash(name))
+		assert.NilError(t, os.MkdirAll(filepath.Dir(filePath), 0755))
+		assert.NilError(t, ioutil.WriteFile(filePath, []byte(content), 0644))
+	}
+}
+
+func snapshotPaths(snapshot map[string]fileState) []string {
+	paths := []string{}
+	for filePath := range snapshot {
+		paths = append(paths, filePath)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+func TestSnapshotDirHonorsGitignore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "runai-sync")
+	assert.NilError(t, err)
+	defer os.RemoveAll(dir)
+	writeSyncTestFiles(t, dir, map[string]string{
+		".gitignore":            "*.pyc\ndata/\n",
+		".git/HEAD":             "ref: refs/heads/main",
+		"train.py":              "print()",
+		"train.pyc":             "",
+		"data/images.tar":       "",
+		"models/.gitignore":     "*.pt\n!base.pt\n",
+		"models/net.py":         "",
+		"models/checkpoint.pt":  "",
+		"models/base.pt":        "",
+		"models/cache/util.pyc": "",
+	})
+
+	snapshot, err := snapshotDir(dir)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, snapshotPaths(snapshot), []string{
+		".gitignore", "models", "models/.gitignore", "models/base.pt", "models/cache", "models/net.py", "train.py",
+	})
+}
+
+func TestDiffSnapshots(t *testing.T) {
+	now := time.Now()
+	previous := map[string]fileState{
+		"train.py":         {size: 10, modTime: now},
+		"util.py":          {size: 10, modTime: now},
+		"models":           {isDir: true, modTime: now},
+		"models/net.py":    {size: 10, modTime: now},
+		"old":              {isDir: true, modTime: now},
+		"old/legacy.py":    {size: 10, modTime: now},
+		"models/unused.py": {size: 10, modTime: now},
+	}
+	current := map[string]fileState{
+		"train.py":      {size: 10, modTime: now},
+		"util.py":       {size: 12, modTime: now.Add(time.Second)},
+		"models":        {isDir: true, modTime: now.Add(time.Second)},
+		"models/net.py": {size: 10, modTime: now},
+		"eval.py":       {size: 5, modTime: now},
+	}
+
+	changes := diffSnapshots(previous, current)
+	assert.DeepEqual(t, changes.updated, []string{"eval.py", "util.py"})
+	// The files under a deleted directory are deleted with it
+	assert.DeepEqual(t, changes.deleted, []string{"models/unused.py", "old"})
+
+	changes = diffSnapshots(nil, current)
+	assert.DeepEqual(t, changes.updated, []string{"eval.py", "models", "models/net.py", "train.py", "util.py"})
+	assert.DeepEqual(t, changes.deleted, []string{})
+}
+
+type syncedCommand struct {
+	command []string
+	entries []string
+}
+
+func recordSyncCommands(commands *[]syncedCommand) syncExecutor {
+	return func(command []string, in io.Reader) error {
+		recorded := syncedCommand{command: command}
+		if in != nil {
+			tarReader := tar.NewReader(in)
+			for {
+				header, err := tarReader.Next()
+				if err == io.EOF {
+					break
+				} else if err != nil {
+					return err
+				}
+				recorded.entries = append(recorded.entries, header.Name)
+			}
+		}
+		*commands = append(*commands, recorded)
+		return nil
+	}
+}
+
+func TestCodeSyncerPushesChanges(t *testing.T) {
+	dir, err := ioutil.TempDir("", "runai-sync")
+	assert.NilError(t, err)
+	defer os.RemoveAll(dir)
+	writeSyncTestFiles(t, dir, map[string]string{
+		"train.py":      "print()",
+		"models/net.py": "",
+	})
+
+	commands := []syncedCommand{}
+	syncer := codeSyncer{localDir: dir, remoteDir: "/workspace", deleteFiles: true}
+	assert.NilError(t, syncer.syncOnce(recordSyncCommands(&commands)))
+	assert.Equal(t, len(commands), 1)
+	assert.DeepEqual(t, commands[0].command[3:], []string{"/workspace"})
+	assert.DeepEqual(t, commands[0].entries, []string{"models/", "models/net.py", "train.py"})
+
+	// Nothing is pushed when nothing changed
+	assert.NilError(t, syncer.syncOnce(recordSyncCommands(&commands)))
+	assert.Equal(t, len(commands), 1)
+
+	assert.NilError(t, os.RemoveAll(filepath.Join(dir, "models")))
+	writeSyncTestFiles(t, dir, map[string]string{"train.py": "print('changed')"})
+	assert.NilError(t, syncer.syncOnce(recordSyncCommands(&commands)))
+	assert.Equal(t, len(commands), 3)
+	assert.DeepEqual(t, commands[1].entries, []string{"train.py"})
+	assert.DeepEqual(t, commands[2].command[3:], []string{"/workspace", "models"})
+}
+
+func TestCodeSyncerDeletesOnlyRemovedPaths(t *testing.T) {
+	dir, err := ioutil.TempDir("", "runai-sync")
+	assert.NilError(t, err)
+	defer os.RemoveAll(dir)
+	writeSyncTestFiles(t, dir, map[string]string{
+		"train.py":       "print()",
+		"eval.py":        "print()",
+		"data/images.py": "",
+	})
+
+	commands := []syncedCommand{}
+	syncer := codeSyncer{localDir: dir, remoteDir: "/workspace", deleteFiles: true}
+	assert.NilError(t, syncer.syncOnce(recordSyncCommands(&commands)))
+	assert.Equal(t, len(commands), 1)
+
+	// The paths which became ignored are kept in the job
+	writeSyncTestFiles(t, dir, map[string]string{".gitignore": "data/\n"})
+	assert.NilError(t, os.Remove(filepath.Join(dir, "eval.py")))
+	assert.NilError(t, syncer.syncOnce(recordSyncCommands(&commands)))
+	assert.Equal(t, len(commands), 3)
+	assert.DeepEqual(t, commands[1].entries, []string{".gitignore"})
+	assert.DeepEqual(t, commands[2].command[3:], []string{"/workspace", "eval.py"})
+
+	// Nothing is deleted unless asked to
+	syncer = codeSyncer{localDir: dir, remoteDir: "/workspace"}
+	assert.NilError(t, syncer.syncOnce(recordSyncCommands(&commands)))
+	assert.NilError(t, os.Remove(filepath.Join(dir, "train.py")))
+	assert.NilError(t, syncer.syncOnce(recordSyncCommands(&commands)))
+	assert.Equal(t, len(commands), 4)
+}
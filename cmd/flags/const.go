@@ -3,4 +3,5 @@ package flags
 const (
 	ProjectFlag                 = "project"
 	LogLevelFlag				= "loglevel"
+	OutputFlag                  = "output"
 )
@@ -0,0 +1,23 @@
+package flags
+
+import (
+	"strings"
+
+	"github.com/run-ai/runai-cli/cmd/completion"
+	"github.com/run-ai/runai-cli/pkg/ui"
+	"github.com/spf13/cobra"
+)
+
+// AddOutputFlag adds the shared --output (-o) flag to a read command
+func AddOutputFlag(cmd *cobra.Command, output *string) {
+	cmd.Flags().StringVarP(output, OutputFlag, "o", ui.DefaultOutput, "Output format. One of: "+strings.Join(ui.OutputFormats, "|"))
+	cmd.RegisterFlagCompletionFunc(OutputFlag, completion.OutputFormatValues)
+}
+
+// AddOutputFlagWithFormats adds the --output (-o) flag to a read command which supports only some of the formats
+func AddOutputFlagWithFormats(cmd *cobra.Command, output *string, formats []string) {
+	cmd.Flags().StringVarP(output, OutputFlag, "o", ui.DefaultOutput, "Output format. One of: "+strings.Join(formats, "|"))
+	cmd.RegisterFlagCompletionFunc(OutputFlag, func(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+		return formats, cobra.ShellCompDirectiveNoFileComp
+	})
+}
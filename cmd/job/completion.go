@@ -108,6 +108,7 @@ func AddSubmitFlagsCompletion(command *cobra.Command) {
 	completion.AddFlagDescrpition(command, "create-home-dir", "Specify a temporary home directory to be created")
 	completion.AddFlagDescrpition(command, "environment", "Specify values for environment variable, formatted as 'variable=value'")
 	completion.AddFlagDescrpition(command, "git-sync", "Specify sync string var1=value1;var2=value2;...")
+	completion.AddFlagDescrpition(command, "ssh", "Specify the secret of the authorized keys as SECRET/KEY")
 	completion.AddFlagDescrpition(command, "gpu", "Specify GPU units to allocate (e.g. 0.5, 1)")
 	completion.AddFlagDescrpition(command, "gpu-memory", "Specify GPU memory to allocate (e.g. 1G, 500M)")
 	completion.AddFlagDescrpition(command, "image", "Specify image to use when creating the job")
@@ -129,4 +130,46 @@ func AddSubmitFlagsCompletion(command *cobra.Command) {
 	command.RegisterFlagCompletionFunc("service-type", completion.ServiceTypeValues)
 
 	command.RegisterFlagCompletionFunc(flags.ProjectFlag, project.GenProjectNamesForFlag)
-}
\ No newline at end of file
+}
+//
+//   generate completion list of container names for a given job, of the pod given by --pod or of the chief pod.
+//   Assumption: in all the commands that has --container parameter, the first argument is the job name
+//
+func GenContainerNames(cmd *cobra.Command, args []string, _ string) ([]string, cobra.ShellCompDirective) {
+
+	if len(args) == 0 {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	jobInfo, _, err := PrepareJobInfo(cmd, args[0])
+	if err != nil || jobInfo == nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	podName, _ := cmd.Flags().GetString("pod")
+	pod := jobInfo.ChiefPod()
+	for _, curPod := range jobInfo.AllPods() {
+		if curPod.Name == podName {
+			pod = &curPod
+			break
+		}
+	}
+	if pod == nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	result := make([]string, 0, len(pod.Spec.Containers))
+	for _, container := range pod.Spec.Containers {
+		result = append(result, container.Name)
+	}
+
+	return result, cobra.ShellCompDirectiveNoFileComp
+}
+
+//
+//   add container flag to the command, and register compleiton function for it
+//
+func AddContainerNameFlag(cmd *cobra.Command, retValue *string) {
+	cmd.Flags().StringVarP(retValue, "container", "c", "", "Specify a container of the pod. By default the default container of the pod, which is the first container unless the pod has the kubectl.kubernetes.io/default-container annotation")
+	cmd.RegisterFlagCompletionFunc("container", GenContainerNames)
+}
@@ -18,29 +18,52 @@ import (
 	"fmt"
 	"github.com/run-ai/runai-cli/cmd/flags"
 	"github.com/run-ai/runai-cli/cmd/job"
+	"github.com/run-ai/runai-cli/cmd/util"
 	"github.com/run-ai/runai-cli/pkg/authentication/assertion"
 	"github.com/run-ai/runai-cli/pkg/client"
+	"github.com/run-ai/runai-cli/pkg/types"
 	commandUtil "github.com/run-ai/runai-cli/pkg/util/command"
 	"github.com/run-ai/runai-cli/pkg/workflow"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"os"
+	"strings"
 )
 
+const deleteExamples = `
+# Delete a job
+runai delete train1
+
+# Delete all the failed jobs of the project which are older than a week
+runai delete --status Failed --older-than 7d
+
+# Delete all your jobs with the label team=vision, without a confirmation
+runai delete -l team=vision --user me --yes
+
+# Delete jobs of other projects by their PROJECT/JOB_NAME, as listed with 'runai list jobs -A -o name'
+runai delete team-a/train1 team-b/build1
+`
+
 // NewDeleteCommand
 func NewDeleteCommand() *cobra.Command {
-	var isAll bool
+	var selector job.JobSelector
+	var isPipeline bool
 
 	var command = &cobra.Command{
 		Use:    "delete JOB_NAME",
 		Short:  "Delete a job and its associated pods.",
+		Example: deleteExamples,
 		ValidArgsFunction: job.GenJobNames,
 		PreRun: commandUtil.NamespacedRoleAssertion(assertion.AssertExecutorRole),
 		Run: func(cmd *cobra.Command, args []string) {
-			if !isAll && len(args) == 0 {
+			if !selector.IsSet() && len(args) == 0 {
 				cmd.HelpFunc()(cmd, args)
 				os.Exit(1)
 			}
+			if err := selector.CheckArgs(args); err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
 
 			kubeClient, err := client.GetClient()
 			if err != nil {
@@ -56,18 +79,53 @@ func NewDeleteCommand() *cobra.Command {
 				os.Exit(1)
 			}
 
+			if isPipeline {
+				if selector.IsSet() {
+					fmt.Println("The flag --pipeline cannot be used together with a selector")
+					os.Exit(1)
+				}
+
+				for _, pipelineName := range args {
+					err = workflow.DeletePipeline(pipelineName, namespaceInfo, kubeClient.GetClientset())
+					if err != nil {
+						log.Error(err)
+					}
+				}
+				return
+			}
+
 			jobNamesToDelete := args
 
-			if isAll {
+			// --all deletes all the jobs without a confirmation, as it did before the other selectors were added
+			if selector.IsAllOnly() {
 				jobNamesToDelete, err = job.ListJobNamesByNamespace(kubeClient, namespaceInfo)
 				if err != nil {
 					log.Error(err)
 					os.Exit(1)
 				}
+			} else if selector.IsSet() {
+				jobs, err := selector.SelectJobs(kubeClient, namespaceInfo)
+				if err != nil {
+					log.Error(err)
+					os.Exit(1)
+				}
+				if len(jobs) == 0 {
+					fmt.Println("No jobs matched the given selector")
+					return
+				}
+				confirmed, err := selector.Confirm("delete", jobs)
+				if err != nil {
+					fmt.Println(err)
+					os.Exit(1)
+				}
+				if !confirmed {
+					return
+				}
+				jobNamesToDelete = job.JobNames(jobs)
 			}
 
 			for _, jobName := range jobNamesToDelete {
-				err = workflow.DeleteJob(jobName, namespaceInfo, kubeClient.GetClientset())
+				err = deleteJob(jobName, namespaceInfo, kubeClient)
 				if err != nil {
 					log.Error(err)
 				}
@@ -75,7 +133,25 @@ func NewDeleteCommand() *cobra.Command {
 		},
 	}
 
-	command.Flags().BoolVarP(&isAll, "all", "A", false, "Delete all jobs")
+	selector.AddFlags(command, true)
+	command.Flags().BoolVar(&isPipeline, "pipeline", false, "Delete pipelines by name, along with the jobs of all their stages")
 
 	return command
 }
+
+// deleteJob deletes a job of the project, or of the project which qualifies its name as PROJECT/JOB_NAME
+func deleteJob(jobName string, namespaceInfo types.NamespaceInfo, kubeClient *client.Client) error {
+	if separator := strings.Index(jobName, "/"); separator >= 0 {
+		project := jobName[:separator]
+		namespace, err := util.GetNamespaceFromProjectName(project, kubeClient)
+		if err != nil {
+			return err
+		}
+		if err = assertion.AssertExecutorRole(namespace); err != nil {
+			return err
+		}
+		namespaceInfo = types.NamespaceInfo{Namespace: namespace, ProjectName: project}
+		jobName = jobName[separator+1:]
+	}
+	return workflow.DeleteJob(jobName, namespaceInfo, kubeClient.GetClientset())
+}
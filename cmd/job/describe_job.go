@@ -1,7 +1,6 @@
 package job
 
 import (
-	"encoding/json"
 	"fmt"
 	"io"
 	"os"
@@ -11,7 +10,6 @@ import (
 	"text/tabwriter"
 	"time"
 
-	"github.com/run-ai/runai-cli/cmd/completion"
 	"github.com/run-ai/runai-cli/pkg/authentication/assertion"
 	commandUtil "github.com/run-ai/runai-cli/pkg/util/command"
 
@@ -20,10 +18,10 @@ import (
 	"github.com/run-ai/runai-cli/cmd/trainer"
 	"github.com/run-ai/runai-cli/pkg/client"
 	"github.com/run-ai/runai-cli/pkg/types"
+	"github.com/run-ai/runai-cli/pkg/ui"
 	"github.com/run-ai/runai-cli/pkg/util"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
-	"gopkg.in/yaml.v2"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
@@ -66,6 +64,7 @@ func RunDescribeJobDEPRECATED(cmd *cobra.Command, printArgs PrintArgs, name stri
 
 func DescribeCommand() *cobra.Command {
 	printArgs := PrintArgs{}
+	var selector JobSelector
 	var command = &cobra.Command{
 		Use:               "job JOB_NAME",
 		Aliases:           []string{"jobs"},
@@ -74,10 +73,18 @@ func DescribeCommand() *cobra.Command {
 		PreRun:            commandUtil.RoleAssertion(assertion.AssertViewerRole),
 		Run: func(cmd *cobra.Command, args []string) {
 
-			if len(args) == 0 {
+			if !selector.IsSet() && len(args) == 0 {
 				cmd.HelpFunc()(cmd, args)
 				os.Exit(1)
 			}
+			if err := selector.CheckArgs(args); err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+			if selector.IsSet() {
+				describeSelectedJobs(cmd, &selector, printArgs)
+				return
+			}
 			name := args[0]
 			job, clientSet, err := PrepareJobInfo(cmd, name)
 			if err != nil {
@@ -91,13 +98,35 @@ func DescribeCommand() *cobra.Command {
 
 	command.Flags().BoolVarP(&printArgs.ShowEvents, "events", "e", true, "Show events relating to job lifecycle.")
 
-	command.Flags().StringVarP(&printArgs.Output, "output", "o", "", "Output format. One of: json|yaml|wide")
-	command.RegisterFlagCompletionFunc("output", completion.OutputFormatValues)
+	flags.AddOutputFlag(command, &printArgs.Output)
+	selector.AddFlags(command, false)
 
 	command.Flags().MarkDeprecated("events", "default is true")
 	return command
 }
 
+func describeSelectedJobs(cmd *cobra.Command, selector *JobSelector, printArgs PrintArgs) {
+	kubeClient, err := client.GetClient()
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	namespace, err := flags.GetNamespaceToUseFromProjectFlag(cmd, kubeClient)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	jobs, err := selector.SelectJobs(kubeClient, namespace)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	printTrainingJobs(kubeClient.GetClientset(), jobs, printArgs)
+}
+
 func PrepareJobInfo(cmd *cobra.Command, name string) (trainer.TrainingJob, kubernetes.Interface, error) {
 	kubeClient, err := client.GetClient()
 	if err != nil {
@@ -118,25 +147,41 @@ func PrepareJobInfo(cmd *cobra.Command, name string) (trainer.TrainingJob, kuber
 
 func printTrainingJob(client kubernetes.Interface, job trainer.TrainingJob, printArgs PrintArgs) {
 	switch printArgs.Output {
-	case "name":
-		fmt.Println(job.Name())
-		// for future CRD support
-	case "json":
-		outBytes, err := json.MarshalIndent(BuildJobInfo(job, client), "", "    ")
-		if err != nil {
+	case ui.NameOutput:
+		ui.PrintNames(os.Stdout, []string{job.Name()})
+	case ui.JsonOutput, ui.YamlOutput:
+		if err := ui.PrintStructuredOutput(os.Stdout, printArgs.Output, BuildJobInfo(job, client)); err != nil {
 			fmt.Printf("Failed due to %v", err)
-		} else {
-			fmt.Println(string(outBytes))
 		}
-	case "yaml":
-		outBytes, err := yaml.Marshal(BuildJobInfo(job, client))
-		if err != nil {
+	case ui.WideOutput, ui.DefaultOutput:
+		printSingleJobHelper(client, job, printArgs)
+	default:
+		log.Fatalf("Unknown output format: %s", printArgs.Output)
+	}
+}
+
+func printTrainingJobs(client kubernetes.Interface, jobs []trainer.TrainingJob, printArgs PrintArgs) {
+	switch printArgs.Output {
+	case ui.NameOutput:
+		ui.PrintNames(os.Stdout, JobNames(jobs))
+	case ui.JsonOutput, ui.YamlOutput:
+		jobInfos := []*types.JobInfo{}
+		for _, job := range jobs {
+			jobInfos = append(jobInfos, BuildJobInfo(job, client))
+		}
+		if err := ui.PrintStructuredOutput(os.Stdout, printArgs.Output, jobInfos); err != nil {
 			fmt.Printf("Failed due to %v", err)
-		} else {
-			fmt.Println(string(outBytes))
 		}
-	case "wide", "":
-		printSingleJobHelper(client, job, printArgs)
+	case ui.WideOutput, ui.DefaultOutput:
+		if len(jobs) == 0 {
+			fmt.Println("No jobs matched the given selector")
+		}
+		for i, job := range jobs {
+			if i > 0 {
+				fmt.Println()
+			}
+			printSingleJobHelper(client, job, printArgs)
+		}
 	default:
 		log.Fatalf("Unknown output format: %s", printArgs.Output)
 	}
@@ -259,6 +304,10 @@ func GetJobRealStatus(job trainer.TrainingJob) string {
 			jobStatus = "PENDING"
 		}
 	}
+	// A suspended job has no pods, unless it finished before it was suspended
+	if _, isSuspended := job.Annotations()[constants.WorkloadSuspendedReplicas]; isSuspended && !trainer.IsFinishedStatus(jobStatus) {
+		jobStatus = constants.Status.Suspended
+	}
 	return jobStatus
 }
 
@@ -317,7 +366,7 @@ func BuildJobInfo(job trainer.TrainingJob, clientset kubernetes.Interface) *type
 	instances := []types.Instance{}
 	for _, pod := range job.AllPods() {
 		isChief := false
-		if pod.Name == job.ChiefPod().Name {
+		if job.ChiefPod() != nil && pod.Name == job.ChiefPod().Name {
 			isChief = true
 		}
 
@@ -330,17 +379,41 @@ func BuildJobInfo(job trainer.TrainingJob, clientset kubernetes.Interface) *type
 		})
 	}
 
+	chiefName := ""
+	if job.ChiefPod() != nil {
+		chiefName = job.ChiefPod().Name
+	}
+
 	return &types.JobInfo{
-		Name:        job.Name(),
-		Namespace:   job.Namespace(),
-		Status:      types.JobStatus(GetJobRealStatus(job)),
-		Duration:    util.ShortHumanDuration(job.Duration()),
-		Trainer:     job.Trainer(),
-		Priority:    getPriorityClass(job),
-		ChiefName:   job.ChiefPod().Name,
-		Instances:   instances,
-		CommandLine: getCliCommand(job),
+		Name:          job.Name(),
+		Namespace:     job.Namespace(),
+		Project:       job.Project(),
+		User:          job.User(),
+		Image:         job.Image(),
+		Node:          getJobNodeName(job),
+		Age:           util.ShortHumanDuration(job.Age()),
+		Status:        types.JobStatus(GetJobRealStatus(job)),
+		Duration:      util.ShortHumanDuration(job.Duration()),
+		Trainer:       job.Trainer(),
+		Priority:      getPriorityClass(job),
+		ChiefName:     chiefName,
+		Instances:     instances,
+		CommandLine:   getCliCommand(job),
+		RequestedGPUs: job.RequestedGPUString(),
+		AllocatedGPUs: job.CurrentAllocatedGPUs(),
+		RunningPods:   job.RunningPods(),
+		PendingPods:   job.PendingPods(),
+		ServiceURLs:   job.ServiceURLs(),
+	}
+}
+
+// getJobNodeName returns the node of the chief pod, or <multiple> when the job spans several nodes
+func getJobNodeName(job trainer.TrainingJob) string {
+	nodeName := job.HostIPOfChief()
+	if strings.Contains(nodeName, ", ") {
+		nodeName = "<multiple>"
 	}
+	return nodeName
 }
 
 /**
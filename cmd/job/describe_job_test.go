@@ -0,0 +1,28 @@
+package job
+
+import (
+	"testing"
+
+	"github.com/magiconair/properties/assert"
+	"github.com/run-ai/runai-cli/cmd/constants"
+	"github.com/run-ai/runai-cli/cmd/trainer"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newTestWorkload(status string, annotations map[string]string) trainer.TrainingJob {
+	jobMetadata := metav1.ObjectMeta{Name: "my-job", Annotations: annotations, CreationTimestamp: metav1.Now()}
+	return newTestRunaiWorkload(jobMetadata, metav1.ObjectMeta{}, v1.PodSpec{}, trainer.RunaiTrainType, status)
+}
+
+func TestGetJobRealStatusSuspended(t *testing.T) {
+	job := newTestWorkload(constants.Status.Pending, map[string]string{constants.WorkloadSuspendedReplicas: "1"})
+
+	assert.Equal(t, GetJobRealStatus(job), constants.Status.Suspended)
+}
+
+func TestGetJobRealStatusFinishedBeforeSuspended(t *testing.T) {
+	job := newTestWorkload(constants.Status.Succeeded, map[string]string{constants.WorkloadSuspendedReplicas: "1"})
+
+	assert.Equal(t, GetJobRealStatus(job), constants.Status.Succeeded)
+}
@@ -0,0 +1,171 @@
+package job
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/run-ai/runai-cli/cmd/constants"
+	"github.com/run-ai/runai-cli/cmd/flags"
+	"github.com/run-ai/runai-cli/pkg/authentication/assertion"
+	"github.com/run-ai/runai-cli/pkg/client"
+	"github.com/run-ai/runai-cli/pkg/types"
+	"github.com/run-ai/runai-cli/pkg/ui"
+	commandUtil "github.com/run-ai/runai-cli/pkg/util/command"
+	"github.com/run-ai/runai-cli/pkg/workflow"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+type PipelineInfo struct {
+	Name    string              `json:"name" yaml:"name"`
+	Project string              `json:"project" yaml:"project"`
+	Stages  []PipelineStageInfo `json:"stages" yaml:"stages"`
+}
+
+type PipelineStageInfo struct {
+	Name      string   `json:"name" yaml:"name"`
+	JobName   string   `json:"jobName" yaml:"jobName"`
+	DependsOn []string `json:"dependsOn" yaml:"dependsOn"`
+	Status    string   `json:"status" yaml:"status"`
+}
+
+func DescribePipelineCommand() *cobra.Command {
+	var output string
+	var command = &cobra.Command{
+		Use:               "pipeline PIPELINE_NAME",
+		Aliases:           []string{"pipelines"},
+		Short:             "Display details of a pipeline and the status of its stages.",
+		ValidArgsFunction: GenPipelineNames,
+		PreRun:            commandUtil.RoleAssertion(assertion.AssertViewerRole),
+		Run: func(cmd *cobra.Command, args []string) {
+			if len(args) == 0 {
+				cmd.HelpFunc()(cmd, args)
+				os.Exit(1)
+			}
+
+			if err := ui.ValidateOutputFormat(output); err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+
+			kubeClient, err := client.GetClient()
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+
+			namespaceInfo, err := flags.GetNamespaceToUseFromProjectFlag(cmd, kubeClient)
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+
+			pipeline, err := workflow.GetPipeline(args[0], namespaceInfo, kubeClient.GetClientset())
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+
+			printPipeline(os.Stdout, BuildPipelineInfo(kubeClient, namespaceInfo, pipeline), output)
+		},
+	}
+
+	flags.AddOutputFlag(command, &output)
+
+	return command
+}
+
+// BuildPipelineInfo resolves the status of every stage, which is the status of its job once the stage was submitted
+func BuildPipelineInfo(kubeClient *client.Client, namespaceInfo types.NamespaceInfo, pipeline *workflow.Pipeline) PipelineInfo {
+	pipelineInfo := PipelineInfo{
+		Name:    pipeline.Name,
+		Project: namespaceInfo.ProjectName,
+		Stages:  []PipelineStageInfo{},
+	}
+
+	for _, stage := range pipeline.Stages {
+		status := stage.State
+		if stage.State == workflow.PipelineStageSubmitted {
+			jobStatus, err := GetJobStatusByName(kubeClient, namespaceInfo, stage.JobName)
+			if err != nil {
+				log.Debugf("Failed to get the status of stage %s due to %v", stage.Name, err)
+				jobStatus = constants.Status.Unknown
+			}
+			status = jobStatus
+		}
+
+		dependsOn := stage.DependsOn
+		if dependsOn == nil {
+			dependsOn = []string{}
+		}
+
+		pipelineInfo.Stages = append(pipelineInfo.Stages, PipelineStageInfo{
+			Name:      stage.Name,
+			JobName:   stage.JobName,
+			DependsOn: dependsOn,
+			Status:    status,
+		})
+	}
+	return pipelineInfo
+}
+
+func printPipeline(out io.Writer, pipelineInfo PipelineInfo, output string) {
+	switch output {
+	case ui.NameOutput:
+		ui.PrintNames(out, []string{pipelineInfo.Name})
+	case ui.JsonOutput, ui.YamlOutput:
+		if err := ui.PrintStructuredOutput(out, output, pipelineInfo); err != nil {
+			fmt.Printf("Failed due to %v", err)
+		}
+	default:
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		fmt.Fprintf(w, "NAME: %s\n", pipelineInfo.Name)
+		fmt.Fprintf(w, "PROJECT: %s\n", pipelineInfo.Project)
+		fmt.Fprintf(w, "\nStages:\n")
+		fmt.Fprintf(w, "STAGE\tJOB\tDEPENDS ON\tSTATUS\n")
+		for _, stage := range pipelineInfo.Stages {
+			dependsOn := "-"
+			if len(stage.DependsOn) > 0 {
+				dependsOn = strings.Join(stage.DependsOn, ",")
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", stage.Name, stage.JobName, dependsOn, stage.Status)
+		}
+		w.Flush()
+	}
+}
+
+//
+//   generate pipeline names for commands which require pipeline name as parameter
+//
+func GenPipelineNames(cmd *cobra.Command, args []string, _ string) ([]string, cobra.ShellCompDirective) {
+	if len(args) != 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	kubeClient, err := client.GetClient()
+	if err != nil {
+		log.Errorf("Failed due to %v", err)
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	namespaceInfo, err := flags.GetNamespaceInfoToUse(cmd, kubeClient)
+	if err != nil {
+		log.Error(err)
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	pipelines, err := workflow.ListPipelines(namespaceInfo.Namespace, kubeClient.GetClientset())
+	if err != nil {
+		log.Error(err)
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	names := []string{}
+	for _, pipeline := range pipelines {
+		names = append(names, pipeline.Name)
+	}
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
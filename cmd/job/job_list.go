@@ -22,6 +22,7 @@ import (
 	"time"
 
 	"github.com/run-ai/runai-cli/cmd/completion"
+	"github.com/run-ai/runai-cli/cmd/constants"
 	"github.com/run-ai/runai-cli/pkg/types"
 
 	"github.com/run-ai/runai-cli/pkg/authentication/assertion"
@@ -46,6 +47,7 @@ const jobInvalidStateOnCreationTimeInSeconds = 30
 
 func ListCommand() *cobra.Command {
 	var allNamespaces bool
+	var output string
 	var command = &cobra.Command{
 		Use:               "jobs",
 		Aliases:           []string{"job"},
@@ -53,16 +55,22 @@ func ListCommand() *cobra.Command {
 		PreRun:            commandUtil.RoleAssertion(assertion.AssertViewerRole),
 		ValidArgsFunction: completion.NoArgs,
 		Run: func(cmd *cobra.Command, args []string) {
-			RunJobList(cmd, args, allNamespaces)
+			RunJobList(cmd, args, allNamespaces, output)
 		},
 	}
 
 	command.Flags().BoolVarP(&allNamespaces, "all-projects", "A", false, "list from all projects")
+	flags.AddOutputFlag(command, &output)
 
 	return command
 }
 
-func RunJobList(cmd *cobra.Command, args []string, allNamespaces bool) {
+func RunJobList(cmd *cobra.Command, args []string, allNamespaces bool, output string) {
+
+	if err := ui.ValidateOutputFormat(output); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
 
 	kubeClient, err := client.GetClient()
 	if err != nil {
@@ -77,27 +85,125 @@ func RunJobList(cmd *cobra.Command, args []string, allNamespaces bool) {
 		os.Exit(1)
 	}
 
-	cmdUtil.PrintShowingJobsInNamespaceMessageByStatuses(namespaceInfo, cmdUtil.AllStatuses)
+	// keep the output parsable when it is piped into other tools
+	if output == ui.DefaultOutput || output == ui.WideOutput {
+		cmdUtil.PrintShowingJobsInNamespaceMessageByStatuses(namespaceInfo, cmdUtil.AllStatuses)
+	}
 
-	jobs, invalidJobs, err := PrepareTrainerJobList(kubeClient, namespaceInfo)
+	jobs, invalidJobConfigMaps, err := prepareTrainerJobList(kubeClient, namespaceInfo)
 	if err != nil {
 		log.Error(err)
 		os.Exit(1)
 	}
+	invalidJobs := getConfigMapNames(invalidJobConfigMaps)
 
 	jobs = trainer.MakeTrainingJobOrderdByProject(trainer.MakeTrainingJobOrderdByName(jobs))
 
-	displayTrainingJobList(jobs, invalidJobs)
+	switch output {
+	case ui.NameOutput:
+		names := getJobNames(jobs, invalidJobs)
+		// The jobs of all the projects are printed as PROJECT/JOB_NAME, which 'runai delete' accepts
+		if allNamespaces {
+			projects, err := getProjectsByNamespace(kubeClient)
+			if err != nil {
+				log.Error(err)
+				os.Exit(1)
+			}
+			names = getQualifiedJobNames(jobs, invalidJobConfigMaps, projects)
+		}
+		ui.PrintNames(os.Stdout, names)
+	case ui.JsonOutput, ui.YamlOutput:
+		jobInfos := buildJobInfoList(kubeClient, jobs, invalidJobs)
+		if err := ui.PrintStructuredOutput(os.Stdout, output, jobInfos); err != nil {
+			log.Error(err)
+			os.Exit(1)
+		}
+	default:
+		displayTrainingJobList(jobs, invalidJobs, output == ui.WideOutput)
+	}
+}
+
+func getJobNames(jobs []trainer.TrainingJob, invalidJobs []string) []string {
+	names := []string{}
+	for _, job := range jobs {
+		names = append(names, job.Name())
+	}
+	return append(names, invalidJobs...)
+}
+
+// getQualifiedJobNames returns the names of the jobs prefixed by the project of their namespace. The jobs in a
+// namespace of no project, e.g. the default namespace of backward compatibility, are not prefixed
+func getQualifiedJobNames(jobs []trainer.TrainingJob, invalidJobs []v1.ConfigMap, projects map[string]string) []string {
+	qualify := func(namespace, name string) string {
+		if project := projects[namespace]; project != "" {
+			return project + "/" + name
+		}
+		return name
+	}
+
+	names := []string{}
+	for _, job := range jobs {
+		names = append(names, qualify(job.Namespace(), job.Name()))
+	}
+	for _, configMap := range invalidJobs {
+		names = append(names, qualify(configMap.Namespace, configMap.Name))
+	}
+	return names
+}
+
+// getProjectsByNamespace returns the projects by the name of their namespace
+func getProjectsByNamespace(kubeClient *client.Client) (map[string]string, error) {
+	namespaces, err := kubeClient.GetClientset().CoreV1().Namespaces().List(metav1.ListOptions{LabelSelector: constants.RunaiQueueLabel})
+	if err != nil {
+		return nil, err
+	}
+	projects := map[string]string{}
+	for _, namespace := range namespaces.Items {
+		projects[namespace.Name] = namespace.Labels[constants.RunaiQueueLabel]
+	}
+	return projects, nil
+}
 
+func buildJobInfoList(kubeClient *client.Client, jobs []trainer.TrainingJob, invalidJobs []string) []types.JobInfo {
+	jobInfos := []types.JobInfo{}
+	for _, job := range jobs {
+		jobInfos = append(jobInfos, *BuildJobInfo(job, kubeClient.GetClientset()))
+	}
+	for _, invalidJob := range invalidJobs {
+		jobInfos = append(jobInfos, types.JobInfo{
+			Name:      invalidJob,
+			Status:    types.JobInvalid,
+			Instances: []types.Instance{},
+		})
+	}
+	return jobInfos
 }
 
 func PrepareTrainerJobList(kubeClient *client.Client, namespaceInfo types.NamespaceInfo) ([]trainer.TrainingJob, []string, error) {
+	jobs, invalidJobConfigMaps, err := prepareTrainerJobList(kubeClient, namespaceInfo)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return jobs, getConfigMapNames(invalidJobConfigMaps), nil
+}
+
+func getConfigMapNames(configMaps []v1.ConfigMap) []string {
+	names := []string{}
+	for _, configMap := range configMaps {
+		names = append(names, configMap.Name)
+	}
+	return names
+}
+
+// prepareTrainerJobList returns the jobs, and the configmaps of the jobs which were submitted but have no workload
+func prepareTrainerJobList(kubeClient *client.Client, namespaceInfo types.NamespaceInfo) ([]trainer.TrainingJob, []v1.ConfigMap, error) {
 	jobs, err := trainer.GetAllJobs(kubeClient, namespaceInfo, nil)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	invalidJobs := []string{}
+	invalidJobs := []v1.ConfigMap{}
 	jobsMap := make(map[string]bool)
 	for _, job := range jobs {
 		jobsMap[job.Name()] = true
@@ -109,9 +215,10 @@ func PrepareTrainerJobList(kubeClient *client.Client, namespaceInfo types.Namesp
 		os.Exit(1)
 	} else {
 		for _, item := range configMaps.Items {
-			if item.Labels[workflow.BaseNameLabelSelectorName] != "" {
+			// pipelines are recorded in configmaps with the same label, but they are not jobs
+			if item.Labels[workflow.BaseNameLabelSelectorName] != "" && item.Labels[workflow.PipelineLabelSelectorName] == "" {
 				if jobsMap[item.Name] == false && isJobCreationTimePass(&item) {
-					invalidJobs = append(invalidJobs, item.Name)
+					invalidJobs = append(invalidJobs, item)
 				}
 			}
 		}
@@ -142,19 +249,21 @@ func isJobCreationTimePass(configMap *v1.ConfigMap) bool {
 	return time.Now().Sub(configMap.CreationTimestamp.Time).Seconds() > jobInvalidStateOnCreationTimeInSeconds
 }
 
-func displayTrainingJobList(jobInfoList []trainer.TrainingJob, invalidJobs []string) {
+// displayTrainingJobList prints the table of the jobs. The wide table adds the namespace, the duration and the chief
+// pod of the jobs
+func displayTrainingJobList(jobInfoList []trainer.TrainingJob, invalidJobs []string, wide bool) {
 	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
 	labelField := []string{"NAME", "STATUS", "AGE", "NODE", "IMAGE", "TYPE", "PROJECT", "USER", "GPUs Allocated (Requested)", "PODs Running (Pending)", "SERVICE URL(S)"}
+	if wide {
+		labelField = append(labelField, "NAMESPACE", "DURATION", "CHIEF POD")
+	}
 
 	ui.Line(w, labelField...)
 
 	for _, jobInfo := range jobInfoList {
 
 		status := GetJobRealStatus(jobInfo)
-		nodeName := jobInfo.HostIPOfChief()
-		if strings.Contains(nodeName, ", ") {
-			nodeName = "<multiple>"
-		}
+		nodeName := getJobNodeName(jobInfo)
 
 		// For backward compatability. Indicat jobs on default namespace
 		var projectName string
@@ -172,17 +281,29 @@ func displayTrainingJobList(jobInfoList []trainer.TrainingJob, invalidJobs []str
 		allocatedFromRequestedGPUs := fmt.Sprintf("%s (%v)", currentAllocatedGPUsAsString, jobInfo.RequestedGPUString())
 		runningOfActivePods := fmt.Sprintf("%d (%d)", int(jobInfo.RunningPods()), int(jobInfo.PendingPods()))
 
-		ui.Line(w, jobInfo.Name(),
+		fields := []string{jobInfo.Name(),
 			status,
 			util.ShortHumanDuration(jobInfo.Age()),
 			nodeName, jobInfo.Image(), jobInfo.Trainer(), projectName, jobInfo.User(),
 			allocatedFromRequestedGPUs,
 			runningOfActivePods,
-			strings.Join(jobInfo.ServiceURLs(), ", "))
+			strings.Join(jobInfo.ServiceURLs(), ", ")}
+		if wide {
+			chiefPodName := ""
+			if chiefPod := jobInfo.ChiefPod(); chiefPod != nil {
+				chiefPodName = chiefPod.Name
+			}
+			fields = append(fields, jobInfo.Namespace(), util.ShortHumanDuration(jobInfo.Duration()), chiefPodName)
+		}
+		ui.Line(w, fields...)
 	}
 
 	for _, invalidJob := range invalidJobs {
-		ui.Line(w, invalidJob, "Invalid job", "", "", "", "", "", "", "", "", "")
+		fields := []string{invalidJob, "Invalid job", "", "", "", "", "", "", "", "", ""}
+		if wide {
+			fields = append(fields, "", "", "")
+		}
+		ui.Line(w, fields...)
 	}
 	_ = w.Flush()
 }
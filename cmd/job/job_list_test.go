@@ -0,0 +1,24 @@
+package job
+
+import (
+	"testing"
+
+	"github.com/magiconair/properties/assert"
+	"github.com/run-ai/runai-cli/cmd/constants"
+	"github.com/run-ai/runai-cli/cmd/trainer"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestGetQualifiedJobNames(t *testing.T) {
+	jobs := []trainer.TrainingJob{
+		newTestRunaiWorkload(metav1.ObjectMeta{Name: "train1"}, metav1.ObjectMeta{}, v1.PodSpec{}, trainer.RunaiTrainType, constants.Status.Running),
+	}
+	invalidJobs := []v1.ConfigMap{
+		{ObjectMeta: metav1.ObjectMeta{Name: "train2", Namespace: "runai-team"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "old-job", Namespace: "default"}},
+	}
+
+	names := getQualifiedJobNames(jobs, invalidJobs, map[string]string{"runai-team": "team"})
+	assert.Equal(t, names, []string{"team/train1", "team/train2", "old-job"})
+}
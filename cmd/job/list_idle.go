@@ -0,0 +1,311 @@
+package job
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/run-ai/runai-cli/cmd/completion"
+	"github.com/run-ai/runai-cli/cmd/flags"
+	runaiClient "github.com/run-ai/runai-cli/cmd/mpi/client/clientset/versioned"
+	"github.com/run-ai/runai-cli/cmd/trainer"
+	cmdUtil "github.com/run-ai/runai-cli/cmd/util"
+	"github.com/run-ai/runai-cli/pkg/authentication/assertion"
+	"github.com/run-ai/runai-cli/pkg/client"
+	"github.com/run-ai/runai-cli/pkg/nodes"
+	"github.com/run-ai/runai-cli/pkg/types"
+	"github.com/run-ai/runai-cli/pkg/ui"
+	"github.com/run-ai/runai-cli/pkg/util"
+	commandUtil "github.com/run-ai/runai-cli/pkg/util/command"
+	"github.com/run-ai/runai-cli/pkg/workflow"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	idleGPUsEventReason = "IdleGPUs"
+
+	listIdleExamples = `
+# List the jobs of the project whose GPUs have been idle for more than an hour
+runai list idle
+
+# List the interactive jobs of all the projects whose GPUs have been idle for more than 4 hours
+runai list idle -A --idle-time 4h --interactive
+
+# Warn the owners of the idle jobs with an event, shown by 'runai describe job'
+runai list idle --idle-time 4h --notify
+
+# Release the GPUs of the idle interactive jobs, keeping the jobs to resume them later
+runai list idle --idle-time 8h --interactive --suspend
+`
+)
+
+// IdleJobInfo is a job whose GPUs have been idle for longer than the threshold
+type IdleJobInfo struct {
+	Name    string  `json:"name" yaml:"name"`
+	Project string  `json:"project" yaml:"project"`
+	User    string  `json:"user" yaml:"user"`
+	Type    string  `json:"type" yaml:"type"`
+	GPUs    float64 `json:"gpus" yaml:"gpus"`
+	// IdleTime is in seconds, as the idle time of the GPUs of a node
+	IdleTime float64 `json:"idleTime" yaml:"idleTime"`
+
+	idleTime time.Duration
+	job      trainer.TrainingJob
+}
+
+func ListIdleCommand() *cobra.Command {
+	var allNamespaces bool
+	var output string
+	var idleTime time.Duration
+	var interactiveOnly bool
+	var notify bool
+	var suspend bool
+	var yes bool
+
+	var command = &cobra.Command{
+		Use:               "idle",
+		Short:             "List the jobs whose allocated GPUs have been idle for longer than a time.",
+		Example:           listIdleExamples,
+		PreRun:            idleRoleAssertion(&notify, &suspend, &allNamespaces),
+		ValidArgsFunction: completion.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := ui.ValidateOutputFormat(output); err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+
+			kubeClient, err := client.GetClient()
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+
+			namespaceInfo, err := flags.GetNamespaceToUseFromProjectFlagIncludingAll(cmd, kubeClient, allNamespaces)
+			if err != nil {
+				log.Error(err)
+				os.Exit(1)
+			}
+
+			nodeInfos, warning, err := nodes.GetAllNodeInfos(kubeClient, true)
+			if err != nil {
+				log.Error(err)
+				os.Exit(1)
+			}
+			if warning != "" {
+				// The idle time of the GPUs is only known to Prometheus
+				fmt.Println(warning)
+				os.Exit(1)
+			}
+
+			jobs, err := trainer.GetAllJobs(kubeClient, namespaceInfo, nil)
+			if err != nil {
+				log.Error(err)
+				os.Exit(1)
+			}
+
+			idleJobs := findIdleJobs(jobs, getGPUsIdleTimeByPod(nodeInfos), idleTime, interactiveOnly)
+			printIdleJobs(os.Stdout, idleJobs, output)
+
+			// keep the output parsable when it is piped into other tools
+			messages := io.Writer(os.Stdout)
+			if output != ui.DefaultOutput && output != ui.WideOutput {
+				messages = os.Stderr
+			}
+			if notify {
+				notifyIdleJobs(kubeClient.GetClientset(), idleJobs, messages)
+			}
+			if suspend {
+				suspendIdleJobs(kubeClient, idleJobs, yes, messages)
+			}
+		},
+	}
+
+	command.Flags().BoolVarP(&allNamespaces, "all-projects", "A", false, "list from all projects")
+	command.Flags().DurationVar(&idleTime, "idle-time", time.Hour, "List the jobs whose GPUs have been idle for longer than this time, e.g. 30m or 4h.")
+	command.Flags().BoolVar(&interactiveOnly, "interactive", false, "List only interactive jobs.")
+	command.Flags().BoolVar(&notify, "notify", false, "Warn the owners of the idle jobs by adding an event to the jobs.")
+	command.Flags().BoolVar(&suspend, "suspend", false, "Suspend the idle jobs to release their GPUs (see 'runai suspend').")
+	command.Flags().BoolVarP(&yes, yesFlag, "y", false, "Do not ask for a confirmation before suspending the idle jobs.")
+	flags.AddOutputFlag(command, &output)
+
+	return command
+}
+
+func getGPUsIdleTimeByPod(nodeInfos []nodes.NodeInfo) map[k8stypes.UID]time.Duration {
+	idleTimeByPod := map[k8stypes.UID]time.Duration{}
+	for _, nodeInfo := range nodeInfos {
+		for uid, idleTime := range nodeInfo.GetGPUsIdleTimeByPod() {
+			idleTimeByPod[uid] = idleTime
+		}
+	}
+	return idleTimeByPod
+}
+
+// findIdleJobs returns the running jobs all of whose GPUs have been idle for longer than the idle time, the longest
+// idle first. The idle time of a job is the idle time of its least idle pod
+func findIdleJobs(jobs []trainer.TrainingJob, idleTimeByPod map[k8stypes.UID]time.Duration, idleTime time.Duration, interactiveOnly bool) []IdleJobInfo {
+	idleJobs := []IdleJobInfo{}
+	for _, job := range jobs {
+		if interactiveOnly && job.Trainer() != trainer.RunaiInteractiveType {
+			continue
+		}
+
+		jobIdleTime, gpus, isIdle := time.Duration(0), 0.0, true
+		for _, pod := range job.AllPods() {
+			podGPUs := cmdUtil.GpuInActivePod(pod)
+			if pod.Status.Phase != v1.PodRunning || podGPUs == 0 {
+				continue
+			}
+			podIdleTime, found := idleTimeByPod[pod.UID]
+			if !found {
+				isIdle = false
+				break
+			}
+			if gpus == 0 || podIdleTime < jobIdleTime {
+				jobIdleTime = podIdleTime
+			}
+			gpus += podGPUs
+		}
+
+		if !isIdle || gpus == 0 || jobIdleTime < idleTime {
+			continue
+		}
+		idleJobs = append(idleJobs, IdleJobInfo{
+			Name:     job.Name(),
+			Project:  job.Project(),
+			User:     job.User(),
+			Type:     job.Trainer(),
+			GPUs:     gpus,
+			IdleTime: jobIdleTime.Seconds(),
+			idleTime: jobIdleTime,
+			job:      job,
+		})
+	}
+
+	sort.SliceStable(idleJobs, func(i, j int) bool { return idleJobs[i].idleTime > idleJobs[j].idleTime })
+	return idleJobs
+}
+
+func printIdleJobs(out io.Writer, idleJobs []IdleJobInfo, output string) {
+	switch output {
+	case ui.NameOutput:
+		names := []string{}
+		for _, idleJob := range idleJobs {
+			names = append(names, idleJob.Name)
+		}
+		ui.PrintNames(out, names)
+	case ui.JsonOutput, ui.YamlOutput:
+		if err := ui.PrintStructuredOutput(out, output, idleJobs); err != nil {
+			log.Error(err)
+			os.Exit(1)
+		}
+	default:
+		if len(idleJobs) == 0 {
+			fmt.Fprintln(out, "No jobs with idle GPUs found")
+			return
+		}
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		ui.Line(w, "NAME", "PROJECT", "USER", "TYPE", "GPUs", "IDLE TIME")
+		for _, idleJob := range idleJobs {
+			ui.Line(w, idleJob.Name, idleJob.Project, idleJob.User, idleJob.Type, fmt.Sprint(idleJob.GPUs), util.ShortHumanDuration(idleJob.idleTime))
+		}
+		_ = w.Flush()
+	}
+}
+
+// idleRoleAssertion asserts the viewer role, or the executor role when the idle jobs are notified or suspended, since
+// that changes them
+func idleRoleAssertion(notify, suspend, allNamespaces *bool) func(cmd *cobra.Command, args []string) {
+	return func(cmd *cobra.Command, args []string) {
+		if !*notify && !*suspend {
+			commandUtil.RoleAssertion(assertion.AssertViewerRole)(cmd, args)
+		} else if *allNamespaces {
+			commandUtil.RoleAssertion(func() error { return assertion.AssertExecutorRole(metav1.NamespaceAll) })(cmd, args)
+		} else {
+			commandUtil.NamespacedRoleAssertion(assertion.AssertExecutorRole)(cmd, args)
+		}
+	}
+}
+
+// notifyIdleJobs adds a warning event to the workloads of the idle jobs, which 'runai describe job' shows
+func notifyIdleJobs(clientset kubernetes.Interface, idleJobs []IdleJobInfo, out io.Writer) {
+	for _, idleJob := range idleJobs {
+		if err := notifyIdleJob(clientset, idleJob); err != nil {
+			log.Errorf("Failed to notify job %s: %v", idleJob.Name, err)
+			continue
+		}
+		fmt.Fprintf(out, "Notified job: %s\n", idleJob.Name)
+	}
+}
+
+func notifyIdleJob(clientset kubernetes.Interface, idleJob IdleJobInfo) error {
+	for _, resource := range idleJob.job.Resources() {
+		if string(resource.ResourceType) != idleJob.job.WorkloadType() {
+			continue
+		}
+		now := metav1.Now()
+		event := &v1.Event{
+			ObjectMeta: metav1.ObjectMeta{
+				GenerateName: resource.Name + ".",
+				Namespace:    idleJob.job.Namespace(),
+			},
+			InvolvedObject: v1.ObjectReference{
+				Kind:      string(resource.ResourceType),
+				Name:      resource.Name,
+				Namespace: idleJob.job.Namespace(),
+				UID:       k8stypes.UID(resource.Uid),
+			},
+			Reason:         idleGPUsEventReason,
+			Message:        fmt.Sprintf("The GPUs of the job have been idle for %s. Please delete or suspend the job to release them.", util.ShortHumanDuration(idleJob.idleTime)),
+			Type:           v1.EventTypeWarning,
+			Source:         v1.EventSource{Component: "runai-cli"},
+			FirstTimestamp: now,
+			LastTimestamp:  now,
+			Count:          1,
+		}
+		_, err := clientset.CoreV1().Events(idleJob.job.Namespace()).Create(event)
+		return err
+	}
+	return fmt.Errorf("could not find the %s of the job", idleJob.job.WorkloadType())
+}
+
+func suspendIdleJobs(kubeClient *client.Client, idleJobs []IdleJobInfo, yes bool, out io.Writer) {
+	if len(idleJobs) == 0 {
+		return
+	}
+	jobs := []trainer.TrainingJob{}
+	for _, idleJob := range idleJobs {
+		jobs = append(jobs, idleJob.job)
+	}
+	confirmed, err := ConfirmJobs("suspend", jobs, yes, out)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	if !confirmed {
+		return
+	}
+
+	runaiClientset := runaiClient.NewForConfigOrDie(kubeClient.GetRestConfig())
+	failed := false
+	for _, job := range jobs {
+		namespaceInfo := types.NamespaceInfo{Namespace: job.Namespace(), ProjectName: job.Project()}
+		if err := workflow.SuspendJob(job.Name(), job.WorkloadType(), namespaceInfo, kubeClient.GetClientset(), runaiClientset); err != nil {
+			log.Error(err)
+			failed = true
+			continue
+		}
+		fmt.Fprintf(out, "Successfully suspended job: %s\n", job.Name())
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
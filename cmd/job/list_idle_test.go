@@ -0,0 +1,99 @@
+package job
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/magiconair/properties/assert"
+	"github.com/run-ai/runai-cli/cmd/constants"
+	"github.com/run-ai/runai-cli/cmd/trainer"
+	"github.com/run-ai/runai-cli/cmd/util"
+	"github.com/run-ai/runai-cli/pkg/types"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func newTestGPUPod(uid string, phase v1.PodPhase) v1.Pod {
+	container := v1.Container{Name: "main"}
+	container.Resources.Limits = v1.ResourceList{util.NVIDIAGPUResourceName: *resource.NewQuantity(1, resource.DecimalSI)}
+	return v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: uid, UID: k8stypes.UID(uid)},
+		Spec:       v1.PodSpec{Containers: []v1.Container{container}},
+		Status:     v1.PodStatus{Phase: phase},
+	}
+}
+
+func newTestIdleWorkload(name string, trainingType string, pods ...v1.Pod) trainer.TrainingJob {
+	jobMetadata := metav1.ObjectMeta{Name: name, Annotations: map[string]string{"user": "john"}, CreationTimestamp: metav1.Now()}
+	podMetadata := metav1.ObjectMeta{Labels: map[string]string{"project": "team"}}
+	return newTestRunaiWorkload(jobMetadata, podMetadata, v1.PodSpec{}, trainingType, constants.Status.Running, pods...)
+}
+
+func idleJobNames(idleJobs []IdleJobInfo) []string {
+	names := []string{}
+	for _, idleJob := range idleJobs {
+		names = append(names, idleJob.Name)
+	}
+	return names
+}
+
+func TestFindIdleJobs(t *testing.T) {
+	jobs := []trainer.TrainingJob{
+		newTestIdleWorkload("build-idle", trainer.RunaiInteractiveType, newTestGPUPod("pod-1", v1.PodRunning)),
+		newTestIdleWorkload("train-idle", trainer.RunaiTrainType, newTestGPUPod("pod-2", v1.PodRunning), newTestGPUPod("pod-3", v1.PodRunning)),
+		newTestIdleWorkload("build-busy", trainer.RunaiInteractiveType, newTestGPUPod("pod-4", v1.PodRunning)),
+		newTestIdleWorkload("train-partly-idle", trainer.RunaiTrainType, newTestGPUPod("pod-5", v1.PodRunning), newTestGPUPod("pod-6", v1.PodRunning)),
+		newTestIdleWorkload("build-pending", trainer.RunaiInteractiveType, newTestGPUPod("pod-7", v1.PodPending)),
+	}
+	idleTimeByPod := map[k8stypes.UID]time.Duration{
+		"pod-1": 2 * time.Hour,
+		"pod-2": 5 * time.Hour,
+		"pod-3": 3 * time.Hour,
+		"pod-4": time.Minute,
+		"pod-5": 5 * time.Hour,
+	}
+
+	idleJobs := findIdleJobs(jobs, idleTimeByPod, time.Hour, false)
+	assert.Equal(t, idleJobNames(idleJobs), []string{"train-idle", "build-idle"})
+	assert.Equal(t, idleJobs[0].GPUs, 2.0)
+	assert.Equal(t, idleJobs[0].IdleTime, (3 * time.Hour).Seconds())
+	assert.Equal(t, idleJobs[0].Project, "team")
+	assert.Equal(t, idleJobs[0].User, "john")
+
+	assert.Equal(t, idleJobNames(findIdleJobs(jobs, idleTimeByPod, time.Hour, true)), []string{"build-idle"})
+	assert.Equal(t, idleJobNames(findIdleJobs(jobs, idleTimeByPod, 4*time.Hour, false)), []string{})
+}
+
+func TestNotifyIdleJob(t *testing.T) {
+	clientset := fake.NewSimpleClientset()
+	jobs := []trainer.TrainingJob{newTestIdleWorkload("build-idle", trainer.RunaiInteractiveType, newTestGPUPod("pod-1", v1.PodRunning))}
+	idleJobs := findIdleJobs(jobs, map[k8stypes.UID]time.Duration{"pod-1": 2 * time.Hour}, time.Hour, false)
+
+	assert.Equal(t, notifyIdleJob(clientset, idleJobs[0]), nil)
+
+	events, err := clientset.CoreV1().Events("runai-team").List(metav1.ListOptions{})
+	assert.Equal(t, err, nil)
+	assert.Equal(t, len(events.Items), 1)
+	event := events.Items[0]
+	assert.Equal(t, event.Reason, idleGPUsEventReason)
+	assert.Equal(t, event.Type, v1.EventTypeWarning)
+	assert.Equal(t, event.InvolvedObject.Kind, string(types.ResourceTypeRunaiJob))
+	assert.Equal(t, event.InvolvedObject.Name, "build-idle")
+	assert.Equal(t, string(event.InvolvedObject.UID), "build-idle-uid")
+	assert.Equal(t, event.Message, "The GPUs of the job have been idle for 2h. Please delete or suspend the job to release them.")
+}
+
+func TestNotifyIdleJobsPrintsToTheGivenWriter(t *testing.T) {
+	clientset := fake.NewSimpleClientset()
+	jobs := []trainer.TrainingJob{newTestIdleWorkload("build-idle", trainer.RunaiInteractiveType, newTestGPUPod("pod-1", v1.PodRunning))}
+	idleJobs := findIdleJobs(jobs, map[k8stypes.UID]time.Duration{"pod-1": 2 * time.Hour}, time.Hour, false)
+
+	// The messages are kept out of the structured output, which is printed to stdout
+	messages := new(bytes.Buffer)
+	notifyIdleJobs(clientset, idleJobs, messages)
+	assert.Equal(t, messages.String(), "Notified job: build-idle\n")
+}
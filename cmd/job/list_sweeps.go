@@ -0,0 +1,154 @@
+package job
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/run-ai/runai-cli/cmd/completion"
+	"github.com/run-ai/runai-cli/cmd/constants"
+	"github.com/run-ai/runai-cli/cmd/flags"
+	"github.com/run-ai/runai-cli/cmd/trainer"
+	"github.com/run-ai/runai-cli/pkg/authentication/assertion"
+	"github.com/run-ai/runai-cli/pkg/client"
+	"github.com/run-ai/runai-cli/pkg/ui"
+	commandUtil "github.com/run-ai/runai-cli/pkg/util/command"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+type SweepInfo struct {
+	Name      string `json:"name" yaml:"name"`
+	Project   string `json:"project" yaml:"project"`
+	Status    string `json:"status" yaml:"status"`
+	Trials    int    `json:"trials" yaml:"trials"`
+	Running   int    `json:"running" yaml:"running"`
+	Pending   int    `json:"pending" yaml:"pending"`
+	Succeeded int    `json:"succeeded" yaml:"succeeded"`
+	Failed    int    `json:"failed" yaml:"failed"`
+}
+
+func ListSweepsCommand() *cobra.Command {
+	var allNamespaces bool
+	var output string
+	var command = &cobra.Command{
+		Use:               "sweeps",
+		Aliases:           []string{"sweep"},
+		Short:             "List all hyperparameter sweeps and the status of their trials.",
+		PreRun:            commandUtil.RoleAssertion(assertion.AssertViewerRole),
+		ValidArgsFunction: completion.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := ui.ValidateOutputFormat(output); err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+
+			kubeClient, err := client.GetClient()
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+
+			namespaceInfo, err := flags.GetNamespaceToUseFromProjectFlagIncludingAll(cmd, kubeClient, allNamespaces)
+			if err != nil {
+				log.Error(err)
+				os.Exit(1)
+			}
+
+			jobs, err := trainer.GetAllJobs(kubeClient, namespaceInfo, nil)
+			if err != nil {
+				log.Error(err)
+				os.Exit(1)
+			}
+
+			printSweeps(os.Stdout, buildSweepInfoList(jobs), output)
+		},
+	}
+
+	command.Flags().BoolVarP(&allNamespaces, "all-projects", "A", false, "list from all projects")
+	flags.AddOutputFlag(command, &output)
+
+	return command
+}
+
+// buildSweepInfoList aggregates the statuses of the jobs labeled as trials of a sweep, by project and sweep name
+func buildSweepInfoList(jobs []trainer.TrainingJob) []SweepInfo {
+	sweepsByKey := map[string]*SweepInfo{}
+	keys := []string{}
+	for _, job := range jobs {
+		sweepName := job.Labels()[constants.RunaiSweepLabel]
+		if sweepName == "" {
+			continue
+		}
+
+		key := job.Project() + "/" + sweepName
+		sweep, found := sweepsByKey[key]
+		if !found {
+			sweep = &SweepInfo{Name: sweepName, Project: job.Project()}
+			sweepsByKey[key] = sweep
+			keys = append(keys, key)
+		}
+
+		sweep.Trials++
+		status := normalizeJobStatus(GetJobRealStatus(job))
+		switch {
+		case status == constants.Status.Running:
+			sweep.Running++
+		case status == constants.Status.Succeeded:
+			sweep.Succeeded++
+		case trainer.IsFinishedStatus(status):
+			sweep.Failed++
+		default:
+			sweep.Pending++
+		}
+	}
+
+	sort.Strings(keys)
+	sweeps := []SweepInfo{}
+	for _, key := range keys {
+		sweep := sweepsByKey[key]
+		sweep.Status = getSweepStatus(sweep)
+		sweeps = append(sweeps, *sweep)
+	}
+	return sweeps
+}
+
+// getSweepStatus returns Running while any trial runs, Pending while trials wait to run, and once all the trials have
+// finished Succeeded only if all of them succeeded
+func getSweepStatus(sweep *SweepInfo) string {
+	switch {
+	case sweep.Running > 0:
+		return constants.Status.Running
+	case sweep.Pending > 0:
+		return constants.Status.Pending
+	case sweep.Failed > 0:
+		return constants.Status.Failed
+	}
+	return constants.Status.Succeeded
+}
+
+func printSweeps(out io.Writer, sweeps []SweepInfo, output string) {
+	switch output {
+	case ui.NameOutput:
+		names := []string{}
+		for _, sweep := range sweeps {
+			names = append(names, sweep.Name)
+		}
+		ui.PrintNames(out, names)
+	case ui.JsonOutput, ui.YamlOutput:
+		if err := ui.PrintStructuredOutput(out, output, sweeps); err != nil {
+			log.Error(err)
+			os.Exit(1)
+		}
+	default:
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		ui.Line(w, "NAME", "STATUS", "PROJECT", "TRIALS", "RUNNING", "PENDING", "SUCCEEDED", "FAILED")
+		for _, sweep := range sweeps {
+			ui.Line(w, sweep.Name, sweep.Status, sweep.Project,
+				fmt.Sprint(sweep.Trials), fmt.Sprint(sweep.Running), fmt.Sprint(sweep.Pending), fmt.Sprint(sweep.Succeeded), fmt.Sprint(sweep.Failed))
+		}
+		_ = w.Flush()
+	}
+}
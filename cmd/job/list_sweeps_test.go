@@ -0,0 +1,32 @@
+package job
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/magiconair/properties/assert"
+	"github.com/run-ai/runai-cli/cmd/constants"
+	"github.com/run-ai/runai-cli/pkg/ui"
+)
+
+func TestGetSweepStatus(t *testing.T) {
+	assert.Equal(t, getSweepStatus(&SweepInfo{Running: 1, Pending: 1, Failed: 1}), constants.Status.Running)
+	assert.Equal(t, getSweepStatus(&SweepInfo{Pending: 1, Succeeded: 2}), constants.Status.Pending)
+	assert.Equal(t, getSweepStatus(&SweepInfo{Succeeded: 2, Failed: 1}), constants.Status.Failed)
+	assert.Equal(t, getSweepStatus(&SweepInfo{Succeeded: 3}), constants.Status.Succeeded)
+}
+
+func TestPrintSweeps(t *testing.T) {
+	sweeps := []SweepInfo{{Name: "lr-sweep", Project: "team-a", Status: constants.Status.Running, Trials: 3, Running: 1, Succeeded: 2}}
+
+	out := &bytes.Buffer{}
+	printSweeps(out, sweeps, ui.DefaultOutput)
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	assert.Equal(t, len(lines), 2)
+	assert.Equal(t, strings.Fields(lines[1]), []string{"lr-sweep", "Running", "team-a", "3", "1", "0", "2", "0"})
+
+	out.Reset()
+	printSweeps(out, sweeps, ui.NameOutput)
+	assert.Equal(t, strings.TrimSpace(out.String()), "lr-sweep")
+}
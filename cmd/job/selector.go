@@ -0,0 +1,253 @@
+package job
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"os/user"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/run-ai/runai-cli/cmd/trainer"
+	"github.com/run-ai/runai-cli/pkg/authentication"
+	"github.com/run-ai/runai-cli/pkg/client"
+	"github.com/run-ai/runai-cli/pkg/types"
+	"github.com/spf13/cobra"
+	"golang.org/x/crypto/ssh/terminal"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+const (
+	allJobsFlag   = "all"
+	selectorFlag  = "selector"
+	statusFlag    = "status"
+	olderThanFlag = "older-than"
+	userFlag      = "user"
+	imageFlag     = "image"
+	yesFlag       = "yes"
+
+	// The value of --user which selects the jobs of the user running the command
+	currentUserSelector = "me"
+)
+
+// JobSelector selects jobs of the project by their labels, status, age, user and image, for the commands which
+// operate on many jobs at once. Jobs match when they match all the given filters
+type JobSelector struct {
+	All       bool
+	Labels    string
+	Statuses  []string
+	OlderThan string
+	User      string
+	Image     string
+	Yes       bool
+}
+
+// jobFilter is a parsed JobSelector
+type jobFilter struct {
+	labels    labels.Selector
+	statuses  []string
+	olderThan time.Duration
+	user      string
+	image     *regexp.Regexp
+}
+
+// AddFlags adds the flags of the selector to the command. The --yes flag is added to commands which ask for a
+// confirmation before operating on the selected jobs
+func (s *JobSelector) AddFlags(command *cobra.Command, withConfirmation bool) {
+	command.Flags().BoolVarP(&s.All, allJobsFlag, "A", false, "Select all the jobs of the project")
+	command.Flags().StringVarP(&s.Labels, selectorFlag, "l", "", "Select the jobs by a label selector (e.g. key1=value1,key2!=value2)")
+	command.Flags().StringSliceVar(&s.Statuses, statusFlag, []string{}, "Select the jobs by status (e.g. Failed or Succeeded,Failed)")
+	command.Flags().StringVar(&s.OlderThan, olderThanFlag, "", "Select the jobs created before a duration (e.g. 7d, 12h or 30m)")
+	command.Flags().StringVar(&s.User, userFlag, "", "Select the jobs submitted by a user ('me' for the current user)")
+	command.Flags().StringVar(&s.Image, imageFlag, "", "Select the jobs by image, '*' matching any characters (e.g. 'ubuntu:*')")
+	if withConfirmation {
+		command.Flags().BoolVarP(&s.Yes, yesFlag, "y", false, "Do not ask for a confirmation before operating on the selected jobs")
+	}
+
+	command.RegisterFlagCompletionFunc(statusFlag, func(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+		return knownJobStatuses(), cobra.ShellCompDirectiveNoFileComp
+	})
+	command.RegisterFlagCompletionFunc(userFlag, func(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+		return []string{currentUserSelector}, cobra.ShellCompDirectiveNoFileComp
+	})
+}
+
+// IsSet returns whether the jobs are selected by the selector rather than by their names
+func (s *JobSelector) IsSet() bool {
+	return s.All || s.Labels != "" || len(s.Statuses) > 0 || s.OlderThan != "" || s.User != "" || s.Image != ""
+}
+
+// IsAllOnly returns whether all the jobs are selected by --all, without any of the other filters
+func (s *JobSelector) IsAllOnly() bool {
+	return s.All && s.Labels == "" && len(s.Statuses) == 0 && s.OlderThan == "" && s.User == "" && s.Image == ""
+}
+
+// CheckArgs validates that the jobs are selected either by their names or by the selector
+func (s *JobSelector) CheckArgs(names []string) error {
+	if s.IsSet() && len(names) > 0 {
+		return fmt.Errorf("please specify either job names or a selector")
+	}
+	if !s.IsSet() && len(names) == 0 {
+		return fmt.Errorf("please specify job names or a selector (e.g. --%s, --%s or --%s)", allJobsFlag, selectorFlag, statusFlag)
+	}
+	return nil
+}
+
+// SelectJobs returns the jobs of the project which match the selector, sorted by name
+func (s *JobSelector) SelectJobs(kubeClient *client.Client, namespaceInfo types.NamespaceInfo) ([]trainer.TrainingJob, error) {
+	filter, err := s.parse(getCurrentUser)
+	if err != nil {
+		return nil, err
+	}
+
+	jobs, err := trainer.GetAllJobs(kubeClient, namespaceInfo, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	selected := filter.filterJobs(jobs)
+	sort.Slice(selected, func(i, j int) bool { return selected[i].Name() < selected[j].Name() })
+	return selected, nil
+}
+
+// Confirm prints the selected jobs and asks the user to confirm the action, unless --yes was given
+func (s *JobSelector) Confirm(action string, jobs []trainer.TrainingJob) (bool, error) {
+	return ConfirmJobs(action, jobs, s.Yes, os.Stdout)
+}
+
+// ConfirmJobs prints the jobs to out and asks the user to confirm the action on them, unless it is confirmed in advance
+func ConfirmJobs(action string, jobs []trainer.TrainingJob, yes bool, out io.Writer) (bool, error) {
+	if yes {
+		return true, nil
+	}
+	return confirmJobs(action, JobNames(jobs), os.Stdin, out, terminal.IsTerminal(int(os.Stdin.Fd())))
+}
+
+func confirmJobs(action string, names []string, in io.Reader, out io.Writer, interactive bool) (bool, error) {
+	if !interactive {
+		return false, fmt.Errorf("use --%s to %s the selected jobs without a confirmation", yesFlag, action)
+	}
+
+	fmt.Fprintf(out, "The following jobs were selected:\n")
+	for _, name := range names {
+		fmt.Fprintf(out, "  %s\n", name)
+	}
+	fmt.Fprintf(out, "%s %d jobs? [y/N] ", strings.Title(action), len(names))
+
+	answer, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return false, err
+	}
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes", nil
+}
+
+// JobNames returns the names of the jobs
+func JobNames(jobs []trainer.TrainingJob) []string {
+	names := []string{}
+	for _, job := range jobs {
+		names = append(names, job.Name())
+	}
+	return names
+}
+
+func (s *JobSelector) parse(currentUser func() (string, error)) (*jobFilter, error) {
+	filter := &jobFilter{user: s.User}
+
+	var err error
+	if filter.labels, err = labels.Parse(s.Labels); err != nil {
+		return nil, fmt.Errorf("invalid --%s: %v", selectorFlag, err)
+	}
+
+	for _, status := range s.Statuses {
+		status = normalizeJobStatus(strings.TrimSpace(status))
+		if !containsStatus(knownJobStatuses(), status) {
+			return nil, fmt.Errorf("invalid --%s '%s', one of: %s", statusFlag, status, strings.Join(knownJobStatuses(), "|"))
+		}
+		filter.statuses = append(filter.statuses, status)
+	}
+
+	if s.OlderThan != "" {
+		if filter.olderThan, err = parseAge(s.OlderThan); err != nil {
+			return nil, fmt.Errorf("invalid --%s: %v", olderThanFlag, err)
+		}
+	}
+
+	if s.User == currentUserSelector {
+		if filter.user, err = currentUser(); err != nil {
+			return nil, fmt.Errorf("could not get the current user: %v", err)
+		}
+	}
+
+	if s.Image != "" {
+		pattern := strings.Replace(regexp.QuoteMeta(s.Image), `\*`, ".*", -1)
+		filter.image = regexp.MustCompile("^" + pattern + "$")
+	}
+	return filter, nil
+}
+
+func (f *jobFilter) filterJobs(jobs []trainer.TrainingJob) []trainer.TrainingJob {
+	selected := []trainer.TrainingJob{}
+	for _, job := range jobs {
+		if f.matches(job) {
+			selected = append(selected, job)
+		}
+	}
+	return selected
+}
+
+func (f *jobFilter) matches(job trainer.TrainingJob) bool {
+	if !f.labels.Matches(labels.Set(job.Labels())) {
+		return false
+	}
+	if len(f.statuses) > 0 && !containsStatus(f.statuses, normalizeJobStatus(GetJobRealStatus(job))) {
+		return false
+	}
+	if f.olderThan > 0 && job.Age() < f.olderThan {
+		return false
+	}
+	if f.user != "" && job.User() != f.user {
+		return false
+	}
+	if f.image != nil && !f.image.MatchString(job.Image()) {
+		return false
+	}
+	return true
+}
+
+func containsStatus(statuses []string, status string) bool {
+	for _, s := range statuses {
+		if strings.EqualFold(s, status) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseAge parses a duration which may also be given in days, e.g. 7d
+func parseAge(age string) (time.Duration, error) {
+	if strings.HasSuffix(age, "d") {
+		days, err := strconv.Atoi(strings.TrimSuffix(age, "d"))
+		if err != nil || days < 0 {
+			return 0, fmt.Errorf("expected a number of days, got '%s'", age)
+		}
+		return time.Duration(days) * 24 * time.Hour, nil
+	}
+	return time.ParseDuration(age)
+}
+
+// getCurrentUser returns the user which jobs are submitted as, see assignUser of the submit command
+func getCurrentUser() (string, error) {
+	if authenticatedUser, err := authentication.GetCurrentAuthenticateUser(); err == nil && authenticatedUser != "" {
+		return authenticatedUser, nil
+	}
+	osUser, err := user.Current()
+	if err != nil {
+		return "", err
+	}
+	return osUser.Username, nil
+}
@@ -0,0 +1,120 @@
+package job
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/magiconair/properties/assert"
+	"github.com/run-ai/runai-cli/cmd/constants"
+	"github.com/run-ai/runai-cli/cmd/trainer"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newTestSelectedWorkload(name, status, user, image string, age time.Duration, labels map[string]string) trainer.TrainingJob {
+	jobMetadata := metav1.ObjectMeta{Name: name, Labels: labels, Annotations: map[string]string{"user": user}, CreationTimestamp: metav1.NewTime(time.Now().Add(-age))}
+	podSpec := v1.PodSpec{Containers: []v1.Container{{Name: name, Image: image}}}
+	return newTestRunaiWorkload(jobMetadata, metav1.ObjectMeta{}, podSpec, trainer.RunaiTrainType, status)
+}
+
+func selectTestJobs(t *testing.T, selector JobSelector) []string {
+	jobs := []trainer.TrainingJob{
+		newTestSelectedWorkload("train1", constants.Status.Running, "john", "ubuntu:18.04", time.Hour, map[string]string{"team": "vision"}),
+		newTestSelectedWorkload("train2", constants.Status.Failed, "john", "pytorch/pytorch:1.6", 10*24*time.Hour, map[string]string{"team": "nlp"}),
+		newTestSelectedWorkload("build1", constants.Status.Succeeded, "jane", "ubuntu:20.04", 3*24*time.Hour, nil),
+	}
+
+	filter, err := selector.parse(func() (string, error) { return "jane", nil })
+	if err != nil {
+		t.Fatal(err)
+	}
+	return JobNames(filter.filterJobs(jobs))
+}
+
+func TestSelectJobsByLabels(t *testing.T) {
+	assert.Equal(t, selectTestJobs(t, JobSelector{Labels: "team=vision"}), []string{"train1"})
+	assert.Equal(t, selectTestJobs(t, JobSelector{Labels: "team"}), []string{"train1", "train2"})
+	assert.Equal(t, selectTestJobs(t, JobSelector{Labels: "team!=vision"}), []string{"train2", "build1"})
+}
+
+func TestSelectJobsByStatus(t *testing.T) {
+	assert.Equal(t, selectTestJobs(t, JobSelector{Statuses: []string{"failed"}}), []string{"train2"})
+	assert.Equal(t, selectTestJobs(t, JobSelector{Statuses: []string{"Failed", "Succeeded"}}), []string{"train2", "build1"})
+}
+
+func TestSelectJobsByAge(t *testing.T) {
+	assert.Equal(t, selectTestJobs(t, JobSelector{OlderThan: "7d"}), []string{"train2"})
+	assert.Equal(t, selectTestJobs(t, JobSelector{OlderThan: "2h"}), []string{"train2", "build1"})
+}
+
+func TestSelectJobsByUser(t *testing.T) {
+	assert.Equal(t, selectTestJobs(t, JobSelector{User: "john"}), []string{"train1", "train2"})
+	assert.Equal(t, selectTestJobs(t, JobSelector{User: currentUserSelector}), []string{"build1"})
+}
+
+func TestSelectJobsByImage(t *testing.T) {
+	assert.Equal(t, selectTestJobs(t, JobSelector{Image: "ubuntu:*"}), []string{"train1", "build1"})
+	assert.Equal(t, selectTestJobs(t, JobSelector{Image: "ubuntu"}), []string{})
+}
+
+func TestSelectJobsByAllFilters(t *testing.T) {
+	assert.Equal(t, selectTestJobs(t, JobSelector{All: true}), []string{"train1", "train2", "build1"})
+	assert.Equal(t, selectTestJobs(t, JobSelector{User: "john", OlderThan: "1d"}), []string{"train2"})
+}
+
+func TestSelectJobsInvalidSelector(t *testing.T) {
+	_, err := (&JobSelector{OlderThan: "a week"}).parse(getCurrentUser)
+	assert.Equal(t, err != nil, true)
+
+	_, err = (&JobSelector{Labels: "team in ("}).parse(getCurrentUser)
+	assert.Equal(t, err != nil, true)
+
+	// A typo in a status would otherwise select no jobs, as if the command succeeded
+	_, err = (&JobSelector{Statuses: []string{"Failed", "Sucess"}}).parse(getCurrentUser)
+	assert.Equal(t, err.Error(), "invalid --status 'Sucess', one of: Running|Pending|Succeeded|Deleted|Failed|TimedOut|Preempted|Suspended|Unknown")
+}
+
+func TestParseAge(t *testing.T) {
+	age, err := parseAge("7d")
+	assert.Equal(t, err, nil)
+	assert.Equal(t, age, 7*24*time.Hour)
+
+	age, err = parseAge("90m")
+	assert.Equal(t, err, nil)
+	assert.Equal(t, age, 90*time.Minute)
+
+	_, err = parseAge("-1d")
+	assert.Equal(t, err != nil, true)
+}
+
+func TestCheckArgs(t *testing.T) {
+	assert.Equal(t, (&JobSelector{}).CheckArgs([]string{"train1"}), nil)
+	assert.Equal(t, (&JobSelector{All: true}).CheckArgs([]string{}), nil)
+	assert.Equal(t, (&JobSelector{}).CheckArgs([]string{}) != nil, true)
+	assert.Equal(t, (&JobSelector{Statuses: []string{"Failed"}}).CheckArgs([]string{"train1"}) != nil, true)
+}
+
+func TestConfirmJobs(t *testing.T) {
+	out := &bytes.Buffer{}
+	confirmed, err := confirmJobs("delete", []string{"train1", "train2"}, strings.NewReader("y\n"), out, true)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, confirmed, true)
+	assert.Equal(t, out.String(), "The following jobs were selected:\n  train1\n  train2\nDelete 2 jobs? [y/N] ")
+
+	confirmed, err = confirmJobs("delete", []string{"train1"}, strings.NewReader("\n"), &bytes.Buffer{}, true)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, confirmed, false)
+
+	confirmed, err = confirmJobs("delete", []string{"train1"}, strings.NewReader(""), &bytes.Buffer{}, false)
+	assert.Equal(t, err.Error(), "use --yes to delete the selected jobs without a confirmation")
+	assert.Equal(t, confirmed, false)
+}
+
+func TestJobSelectorIsAllOnly(t *testing.T) {
+	assert.Equal(t, (&JobSelector{All: true}).IsAllOnly(), true)
+	assert.Equal(t, (&JobSelector{All: true, Yes: true}).IsAllOnly(), true)
+	assert.Equal(t, (&JobSelector{All: true, Statuses: []string{"Failed"}}).IsAllOnly(), false)
+	assert.Equal(t, (&JobSelector{Labels: "team=vision"}).IsAllOnly(), false)
+}
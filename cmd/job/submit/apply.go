@@ -0,0 +1,200 @@
+package submit
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/run-ai/runai-cli/cmd/completion"
+	"github.com/run-ai/runai-cli/cmd/flags"
+	"github.com/run-ai/runai-cli/cmd/trainer"
+	"github.com/run-ai/runai-cli/pkg/authentication/assertion"
+	"github.com/run-ai/runai-cli/pkg/client"
+	"github.com/run-ai/runai-cli/pkg/config"
+	commandUtil "github.com/run-ai/runai-cli/pkg/util/command"
+	"github.com/run-ai/runai-cli/pkg/workflow"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+)
+
+const applyExamples = `
+# Submit the jobs of the file that were not submitted yet
+runai apply -f jobs.yaml
+
+# Read the jobs from the standard input
+cat jobs.json | runai apply -f -
+`
+
+// Keys of the job values which describe the submission rather than the job itself
+var applyIgnoredValues = map[string]bool{
+	"name":          true,
+	"nameparameter": true,
+	"namespace":     true,
+	"project":       true,
+	"user":          true,
+	"cliCommand":    true,
+}
+
+func NewApplyCommand() *cobra.Command {
+	var command = &cobra.Command{
+		Use:               "apply -f FILENAME",
+		Short:             "Submit the jobs defined in a file which do not exist yet.",
+		Long:              "Submit the jobs defined in a YAML or JSON file which do not exist yet. Jobs which already exist are not modified, instead the command reports the ones whose spec differs from the file.",
+		Example:           applyExamples,
+		ValidArgsFunction: completion.NoArgs,
+		Args:              cobra.NoArgs,
+		PreRun:            commandUtil.NamespacedRoleAssertion(assertion.AssertExecutorRole),
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := applyJobManifests(cmd); err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+		},
+	}
+
+	command.Flags().StringVarP(&manifestFile, manifestFileFlag, "f", "", "The YAML or JSON file that defines the jobs ('-' to read from the standard input).")
+	command.MarkFlagRequired(manifestFileFlag)
+
+	return command
+}
+
+func applyJobManifests(cmd *cobra.Command) error {
+	manifests, err := readJobManifests(manifestFile)
+	if err != nil {
+		return err
+	}
+
+	kubeClient, err := client.GetClient()
+	if err != nil {
+		return err
+	}
+
+	// The project flag is the only flag of the command, and like the flags of submit it overrides the file
+	cliArgs := submitRunaiJobArgs{}
+	cliArgs.Project = cmd.Flags().Lookup(flags.ProjectFlag).Value.String()
+
+	failures := 0
+	for _, manifest := range manifests {
+		jobArgs := mergeManifestToRunaiSubmitArgs(cliArgs, manifest)
+		jobArgs.addCliCommand()
+		if err := applyJobManifest(cmd, kubeClient, &jobArgs); err != nil {
+			fmt.Println(err)
+			failures++
+		}
+	}
+
+	if failures > 0 {
+		return fmt.Errorf("failed to apply %d out of %d jobs", failures, len(manifests))
+	}
+	return nil
+}
+
+func applyJobManifest(cmd *cobra.Command, kubeClient *client.Client, jobArgs *submitRunaiJobArgs) error {
+	name := jobArgs.NameParameter
+	if name == "" {
+		return fmt.Errorf("every job must have a name in order to be applied")
+	}
+
+	namespaceInfo, err := getSubmitNamespaceInfo(cmd, kubeClient, jobArgs.Project)
+	if err != nil {
+		return err
+	}
+
+	values, err := workflow.GetJobValues(name, namespaceInfo, kubeClient.GetClientset())
+	if err != nil {
+		log.Debugf("Could not get the values of job %s due to %v", name, err)
+		if _, err := trainer.SearchTrainingJob(kubeClient, name, "", namespaceInfo); err == nil {
+			fmt.Printf("The job '%s' already exists but was not submitted by %s, its spec cannot be compared\n", name, config.CLIName)
+			return nil
+		}
+		return runSubmitRunaiJob(cmd, []string{}, jobArgs, jobArgs.commandAndArgs())
+	}
+
+	differences, err := diffJobSpec(*jobArgs, values)
+	if err != nil {
+		return fmt.Errorf("could not compare job %s: %v", name, err)
+	}
+
+	if len(differences) == 0 {
+		fmt.Printf("The job '%s' already exists and is unchanged\n", name)
+	} else {
+		fmt.Printf("The job '%s' already exists with a different spec (%s). Delete it first to submit the new spec (use '%s delete %s')\n",
+			name, strings.Join(differences, ", "), config.CLIName, name)
+	}
+	return nil
+}
+
+// diffJobSpec returns the keys of the values which the job manifest sets differently than the values of the existing job
+func diffJobSpec(jobArgs submitRunaiJobArgs, existingValues string) ([]string, error) {
+	// Normalize the spec the same way it is done when submitting it
+	if err := HandleVolumesAndPvc(&jobArgs.submitArgs); err != nil {
+		return nil, err
+	}
+	if err := handleRequestedGPUs(&jobArgs.submitArgs); err != nil {
+		return nil, err
+	}
+	if err := handleImagePullPolicy(&jobArgs.submitArgs); err != nil {
+		return nil, err
+	}
+
+	content, err := yaml.Marshal(jobArgs)
+	if err != nil {
+		return nil, err
+	}
+
+	desired := map[interface{}]interface{}{}
+	if err = yaml.Unmarshal(content, &desired); err != nil {
+		return nil, err
+	}
+
+	existing := map[interface{}]interface{}{}
+	if err = yaml.Unmarshal([]byte(existingValues), &existing); err != nil {
+		return nil, err
+	}
+
+	differences := diffValues("", desired, existing)
+	sort.Strings(differences)
+	return differences, nil
+}
+
+// diffValues compares only the values set in desired, as the existing values also hold the defaults filled at submission
+func diffValues(prefix string, desired, existing map[interface{}]interface{}) []string {
+	differences := []string{}
+	for key, desiredValue := range desired {
+		keyName := fmt.Sprint(key)
+		if prefix == "" && applyIgnoredValues[keyName] {
+			continue
+		} else if prefix != "" {
+			keyName = fmt.Sprintf("%s.%s", prefix, keyName)
+		}
+
+		if isEmptyValue(desiredValue) {
+			continue
+		}
+
+		if desiredMap, isMap := desiredValue.(map[interface{}]interface{}); isMap {
+			existingMap, _ := existing[key].(map[interface{}]interface{})
+			differences = append(differences, diffValues(keyName, desiredMap, existingMap)...)
+		} else if !reflect.DeepEqual(desiredValue, existing[key]) {
+			differences = append(differences, keyName)
+		}
+	}
+	return differences
+}
+
+func isEmptyValue(value interface{}) bool {
+	switch value := value.(type) {
+	case nil:
+		return true
+	case string:
+		return value == ""
+	case []interface{}:
+		return len(value) == 0
+	case map[interface{}]interface{}:
+		return len(value) == 0
+	}
+	return false
+}
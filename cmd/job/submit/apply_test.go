@@ -0,0 +1,70 @@
+package submit
+
+import (
+	"testing"
+
+	"github.com/magiconair/properties/assert"
+)
+
+const existingJobValues = `
+image: gcr.io/run-ai-demo/quickstart
+name: train1
+user: john
+gpu: 1
+gpuInt: 1
+imagePullPolicy: Always
+command:
+- python
+- train.py
+isCommand: true
+labels:
+  runai/job-index: "12"
+  team: vision
+gitSync:
+  sync: false
+`
+
+func TestDiffJobSpecUnchanged(t *testing.T) {
+	manifests, err := parseJobManifests([]byte(`
+name: train1
+image: gcr.io/run-ai-demo/quickstart
+gpu: 1
+command: ["python", "train.py"]
+labels:
+  team: vision
+`))
+	if err != nil {
+		t.Fatalf("Failed to parse manifest, %s", err)
+	}
+
+	jobArgs := mergeManifestToRunaiSubmitArgs(submitRunaiJobArgs{}, manifests[0])
+	jobArgs.addCliCommand()
+	differences, err := diffJobSpec(jobArgs, existingJobValues)
+	if err != nil {
+		t.Fatalf("Failed to diff job spec, %s", err)
+	}
+
+	assert.Equal(t, differences, []string{})
+}
+
+func TestDiffJobSpecChanged(t *testing.T) {
+	manifests, err := parseJobManifests([]byte(`
+name: train1
+image: gcr.io/run-ai-demo/quickstart:v2
+gpu: 0.5
+args: ["train.py"]
+labels:
+  team: nlp
+`))
+	if err != nil {
+		t.Fatalf("Failed to parse manifest, %s", err)
+	}
+
+	jobArgs := mergeManifestToRunaiSubmitArgs(submitRunaiJobArgs{}, manifests[0])
+	differences, err := diffJobSpec(jobArgs, existingJobValues)
+	if err != nil {
+		t.Fatalf("Failed to diff job spec, %s", err)
+	}
+
+	assert.Equal(t, differences, []string{"args", "gpu", "gpuFraction", "image", "isCommand", "labels.team"})
+}
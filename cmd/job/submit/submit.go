@@ -18,6 +18,7 @@ import (
 	"fmt"
 	"os"
 	"os/user"
+	"regexp"
 	"strconv"
 	"strings"
 	"syscall"
@@ -30,9 +31,13 @@ import (
 	raUtil "github.com/run-ai/runai-cli/cmd/util"
 	"github.com/run-ai/runai-cli/pkg/client"
 	"github.com/run-ai/runai-cli/pkg/clusterConfig"
+	"github.com/run-ai/runai-cli/pkg/types"
 	"github.com/run-ai/runai-cli/pkg/util"
+	"github.com/run-ai/runai-cli/pkg/workflow"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/client-go/kubernetes"
@@ -59,8 +64,11 @@ const (
 
 var (
 	dryRun                  bool
+	diffJob                 bool
 	templateName            string
 	gitSyncConnectionString string
+	sshAuthorizedKeysSecret string
+	sshAuthorizedKeysPath   string
 )
 
 // The common parts of the submitAthd
@@ -111,6 +119,7 @@ type submitArgs struct {
 	NamePrefix                 string            `yaml:"namePrefix,omitempty"`
 	BackoffLimit               *int              `yaml:"backoffLimit,omitempty"`
 	GitSync                    *GitSync          `yaml:"gitSync,omitempty"`
+	SSH                        *SSH              `yaml:"ssh,omitempty"`
 	generateSuffix             bool
 }
 
@@ -134,7 +143,22 @@ func(submitArgs *submitArgs) addCommonSubmit(fbg flags.FlagsByGroups) {
 }
 
 func(submitArgs *submitArgs) addCliCommand() {
-	submitArgs.CliCommand = strings.Join( os.Args , " ")
+	submitArgs.CliCommand = redactCliCommand(os.Args)
+}
+
+var (
+	// A credential given as KEY=VALUE, e.g. password= of --git-sync or an environment variable like API_TOKEN=
+	cliCommandCredential = regexp.MustCompile(`(?i)(^|[\s,_=-])(password|passwd|token|secret)=[^\s,]*`)
+	// The password of a URL, e.g. of the repository of --git-sync
+	cliCommandURLPassword = regexp.MustCompile(`(://[^\s,/:@]*:)[^\s,/@]*@`)
+)
+
+// redactCliCommand returns the command line without the credentials in it, since the command line is stored in the
+// configmap and the annotations of the job, which everyone who can view the job can read
+func redactCliCommand(args []string) string {
+	command := strings.Join(args, " ")
+	command = cliCommandCredential.ReplaceAllString(command, "${1}${2}=REDACTED")
+	return cliCommandURLPassword.ReplaceAllString(command, "${1}REDACTED@")
 }
 
 func (submitArgs *submitArgs) addCommonFlags(fbg flags.FlagsByGroups) {
@@ -144,9 +168,8 @@ func (submitArgs *submitArgs) addCommonFlags(fbg flags.FlagsByGroups) {
 	flags.AddBoolNullableFlag(flagSet, &(submitArgs.Interactive), "interactive", "", "Mark this Job as interactive.")
 	flagSet.StringVarP(&(templateName), "template", "", "", "Use a specific template to run this job (otherwise use the default template if exists).")
 	flagSet.StringVarP(&(submitArgs.Project), "project", "p", "", "Specifies a project. Set a default project using 'runai config project <project name>'.")
-	// Will not submit the job to the cluster, just print the template to the screen
-	flagSet.BoolVar(&dryRun, "dry-run", false, "Run as dry run")
-	flagSet.MarkHidden("dry-run")
+	flagSet.BoolVar(&dryRun, "dry-run", false, "Validate the job with the cluster without submitting it. The API server checks the job as if it was submitted, including admission webhooks, quotas and policies.")
+	flagSet.BoolVar(&diffJob, "diff", false, "Show the differences between the job and the existing job of the same name, without submitting it.")
 	flagSet.StringVar(&submitArgs.NamePrefix, "job-name-prefix", "", "Set defined prefix for the job name and add index as suffix")
 
 	flagSet = fbg.GetOrAddFlagSet(ContainerDefinitionFlagGroup)
@@ -166,7 +189,7 @@ func (submitArgs *submitArgs) addCommonFlags(fbg flags.FlagsByGroups) {
 	flags.AddBoolNullableFlag(flagSet, &submitArgs.StdIn, "stdin", "", "Keep stdin open on the container(s) in the pod, even if nothing is attached.")
 	flags.AddBoolNullableFlag(flagSet, &submitArgs.Attach, "attach", "", `If true, wait for the Pod to start running, and then attach to the Pod as if 'runai attach ...' were called. Attach makes tty and stdin true by default. Default false`)
 	flagSet.StringVar(&(submitArgs.WorkingDir), "working-dir", "", "Set the container's working directory.")
-	flagSet.StringVar(&gitSyncConnectionString, "git-sync", "", "sync string in the template of: source=REPO,branch=BRANCH_NAME,rev=REVISION,username=USER,password=PASSWORD,target=TARGET_DIRECTORY_TO_CLONE")
+	flagSet.StringVar(&gitSyncConnectionString, "git-sync", "", "sync string in the template of: source=REPO,branch=BRANCH_NAME,rev=REVISION,username=USER,password=PASSWORD,passwordSecret=SECRET/KEY,sshKey=SSH_KEY_FILE,sshKeySecret=SECRET/KEY,knownHosts=KNOWN_HOSTS_FILE,skipHostKeyCheck=true,target=TARGET_DIRECTORY_TO_CLONE. Credentials are stored in a secret owned by the job. The host key of an SSH repository is checked against the known_hosts key of the secret of the SSH key, or the knownHosts file of an SSH key file (by default ~/.ssh/known_hosts)")
 	flags.AddBoolNullableFlag(flagSet, &(submitArgs.RunAsCurrentUser), "run-as-user", "", "Run in the context of the current CLI user rather than the root user.")
 
 	flagSet = fbg.GetOrAddFlagSet(ResourceAllocationFlagGroup)
@@ -196,6 +219,8 @@ func (submitArgs *submitArgs) addCommonFlags(fbg flags.FlagsByGroups) {
 	flagSet = fbg.GetOrAddFlagSet(AccessControlFlagGroup)
 	flags.AddBoolNullableFlag(flagSet, &submitArgs.CreateHomeDir, "create-home-dir", "", "Create a temporary home directory. Default is true when the --run-as-user flag is set, and false if not.")
 	flags.AddBoolNullableFlag(flagSet, &(submitArgs.PreventPrivilegeEscalation), "prevent-privilege-escalation", "", "Prevent the job’s container from gaining additional privileges after start.")
+	flagSet.StringVar(&sshAuthorizedKeysSecret, "ssh", "", "Allow SSH access to the job with 'runai ssh-config', with the authorized keys in an existing secret given as SECRET/KEY. The key defaults to authorized_keys. The image should run an SSH server.")
+	flagSet.StringVar(&sshAuthorizedKeysPath, "ssh-authorized-keys-path", "", "The path of the authorized keys file of the user of the SSH server. Default is /root/.ssh/authorized_keys, or the authorized keys file of the temporary home directory when the job runs as a user.")
 	flagSet.StringVarP(&(submitArgs.User), "user", "u", "", "Use different user to run the Job.")
 	flagSet.MarkHidden("user")
 
@@ -220,7 +245,7 @@ func (submitArgs *submitArgs) setCommonRun(cmd *cobra.Command, args []string, ku
 
 	submitArgs.Name = name
 
-	namespaceInfo, err := flags.GetNamespaceToUseFromProjectFlagAndPrintError(cmd, kubeClient)
+	namespaceInfo, err := getSubmitNamespaceInfo(cmd, kubeClient, submitArgs.Project)
 
 	if err != nil {
 		return err
@@ -270,13 +295,15 @@ func (submitArgs *submitArgs) setCommonRun(cmd *cobra.Command, args []string, ku
 		return err
 	}
 
-	index, err := getJobIndex(clientset)
+	// A job which is not submitted does not take an index, so the index of the next job does not change
+	if !isSubmitPreview() {
+		index, err := getJobIndex(clientset)
 
-	if err != nil {
-		log.Debug("Could not get job index. Will not set a label.")
-	} else {
-		submitArgs.Labels = make(map[string]string)
-		submitArgs.Labels["runai/job-index"] = index
+		if err != nil {
+			log.Debug("Could not get job index. Will not set a label.")
+		} else {
+			submitArgs.setJobIndex(index)
+		}
 	}
 
 	// by default when the user set --attach the --stdin and --tty set to true
@@ -316,6 +343,18 @@ func (submitArgs *submitArgs) setCommonRun(cmd *cobra.Command, args []string, ku
 		return err
 	}
 
+	if sshAuthorizedKeysPath != "" && sshAuthorizedKeysSecret == "" {
+		return fmt.Errorf("the flag --ssh-authorized-keys-path can only be used with --ssh")
+	} else if sshAuthorizedKeysSecret != "" {
+		authorizedKeysPath, err := submitArgs.sshAuthorizedKeysPath(sshAuthorizedKeysPath)
+		if err != nil {
+			return err
+		}
+		if submitArgs.SSH, err = NewSSH(sshAuthorizedKeysSecret, authorizedKeysPath); err != nil {
+			return err
+		}
+	}
+
 	if raUtil.IsBoolPTrue(submitArgs.Interactive) {
 		noBackoffLimit := 0
 		submitArgs.BackoffLimit = &noBackoffLimit
@@ -324,6 +363,20 @@ func (submitArgs *submitArgs) setCommonRun(cmd *cobra.Command, args []string, ku
 	return nil
 }
 
+// getSubmitNamespaceInfo resolves the namespace of the project set on the submit arguments, which may come from a
+// job manifest rather than from the project flag
+func getSubmitNamespaceInfo(cmd *cobra.Command, kubeClient *client.Client, project string) (types.NamespaceInfo, error) {
+	if project == "" || project == cmd.Flags().Lookup(flags.ProjectFlag).Value.String() {
+		return flags.GetNamespaceToUseFromProjectFlagAndPrintError(cmd, kubeClient)
+	}
+
+	namespace, err := raUtil.GetNamespaceFromProjectName(project, kubeClient)
+	return types.NamespaceInfo{
+		Namespace:   namespace,
+		ProjectName: project,
+	}, err
+}
+
 func (sa *submitArgs) applyRunAsAuthenticatedUser() (bool, error) {
 	uid, gid, err := authentication.GetCurrentAuthenticateUserUidGid()
 	if err != nil {
@@ -406,6 +459,11 @@ func tryGetJobIndexOnce(clientset kubernetes.Interface) (string, bool, error) {
 
 func convertOldCommandArgsFlags(cmd *cobra.Command, submitArgs *submitArgs, args []string) []string {
 	commandArgs, isCommand := mergeOldCommandAndArgsWithNew(cmd.ArgsLenAtDash(), args, submitArgs.SpecCommand, submitArgs.SpecArgs, submitArgs.Command)
+	setCommandAndArgs(submitArgs, commandArgs, isCommand)
+	return commandArgs
+}
+
+func setCommandAndArgs(submitArgs *submitArgs, commandArgs []string, isCommand *bool) {
 	if isCommand != nil && *isCommand {
 		submitArgs.SpecCommand = commandArgs
 		submitArgs.SpecArgs = []string{}
@@ -414,7 +472,6 @@ func convertOldCommandArgsFlags(cmd *cobra.Command, submitArgs *submitArgs, args
 		submitArgs.SpecArgs = commandArgs
 	}
 	submitArgs.Command = isCommand
-	return commandArgs
 }
 
 func mergeOldCommandAndArgsWithNew(argsLenAtDash int, positionalArgs, oldCommand, oldArgs []string, isCommand *bool) ([]string, *bool) {
@@ -441,7 +498,8 @@ func mergeOldCommandAndArgsWithNew(argsLenAtDash int, positionalArgs, oldCommand
 func getJobNameWithSuffixGenerationFlag(cmd *cobra.Command, args []string, submitArgs *submitArgs) (string, bool, error) {
 	argsLenUntilDash := cmd.ArgsLenAtDash()
 	argsUntilDash := args
-	if argsLenUntilDash != -1 {
+	// the arguments may have been dropped, when the job name is set by a sweep or a manifest
+	if argsLenUntilDash != -1 && argsLenUntilDash <= len(args) {
 		argsUntilDash = args[:argsLenUntilDash]
 	}
 	if submitArgs.NameParameter != "" {
@@ -460,7 +518,7 @@ func getJobNameWithSuffixGenerationFlag(cmd *cobra.Command, args []string, submi
 }
 
 func AlignArgsPreParsing(args []string) []string {
-	if len(args) < 2 || (args[1] != submitCommand && args[1] != SubmitMpiCommand) {
+	if len(args) < 2 || (args[1] != submitCommand && args[1] != SubmitMpiCommand && args[1] != resubmitCommand) {
 		return args
 	}
 
@@ -481,3 +539,68 @@ func AlignArgsPreParsing(args []string) []string {
 	}
 	return args
 }
+
+func (submitArgs *submitArgs) setJobIndex(index string) {
+	if submitArgs.Labels == nil {
+		submitArgs.Labels = make(map[string]string)
+	}
+	submitArgs.Labels[jobIndexLabel] = index
+}
+
+// isSubmitPreview returns whether the job is only checked or compared to an existing job, and not submitted
+func isSubmitPreview() bool {
+	return dryRun || diffJob
+}
+
+// OwnedSecrets returns the secrets of the local credentials of the job, which the workflow creates along with the job
+func (submitArgs *submitArgs) OwnedSecrets(jobName string) []*corev1.Secret {
+	secrets := []*corev1.Secret{}
+	if submitArgs.GitSync != nil {
+		if secret := submitArgs.GitSync.ownedSecret(jobName); secret != nil {
+			secrets = append(secrets, secret)
+		}
+	}
+	return secrets
+}
+
+// submitWorkflowJob submits the job chart, or runs it through the server-side dry run or compares it to the
+// existing job of the same name when --dry-run or --diff are set
+func (submitArgs *submitArgs) submitWorkflowJob(values interface{}, chart string, clientset kubernetes.Interface) (string, error) {
+	if !diffJob {
+		return workflow.SubmitJob(submitArgs.Name, submitArgs.Namespace, submitArgs.generateSuffix, values, chart, clientset, dryRun)
+	}
+
+	if dryRun {
+		return "", fmt.Errorf("the flags --dry-run and --diff cannot be used together")
+	}
+	if submitArgs.generateSuffix {
+		return "", fmt.Errorf("--diff compares the job with an existing job, use --name to set its name")
+	}
+
+	namespaceInfo := types.NamespaceInfo{Namespace: submitArgs.Namespace, ProjectName: submitArgs.Project}
+	existingValues, err := workflow.GetJobChartValues(submitArgs.Name, namespaceInfo, chart, clientset)
+	if err != nil {
+		return "", err
+	}
+	// The job is compared as if it replaced the existing job, so it keeps the index of the existing job
+	existingJob := struct {
+		Labels map[string]string `yaml:"labels"`
+	}{}
+	if err = yaml.Unmarshal([]byte(existingValues), &existingJob); err != nil {
+		return "", fmt.Errorf("failed to parse the values of the existing job %s: %v", submitArgs.Name, err)
+	}
+	if index, found := existingJob.Labels[jobIndexLabel]; found {
+		submitArgs.setJobIndex(index)
+	}
+
+	diff, err := workflow.DiffJob(submitArgs.Name, namespaceInfo, values, chart, clientset)
+	if err != nil {
+		return "", err
+	}
+	if diff == "" {
+		fmt.Printf("The job '%s' is identical to the existing job\n", submitArgs.Name)
+	} else {
+		fmt.Print(diff)
+	}
+	return submitArgs.Name, nil
+}
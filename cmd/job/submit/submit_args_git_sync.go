@@ -2,27 +2,63 @@ package submit
 
 import (
 	"fmt"
-	raUtil "github.com/run-ai/runai-cli/cmd/util"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"strings"
+
+	raUtil "github.com/run-ai/runai-cli/cmd/util"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 const (
 	defaultGitSyncImage  = "k8s.gcr.io/git-sync/git-sync:v3.2.0"
 	defaultSyncDirectory = "/code"
 	defaultBranch        = "master"
+
+	// The keys of the credentials in the secret which the CLI creates for the job
+	gitSyncPasswordKey   = "password"
+	gitSyncSSHKeyKey     = "ssh"
+	gitSyncKnownHostsKey = "known_hosts"
 )
 
+// SecretKeyRef is a key of a secret in the namespace of the job. A secret without a name is the one which the CLI
+// creates for the job, named after the job
+type SecretKeyRef struct {
+	Name string `yaml:"name,omitempty"`
+	Key  string `yaml:"key"`
+}
+
+// GitSync is the git-sync init container of the job. The credentials given by the user, a password or the path of an
+// SSH key, are never part of the values of the job, which are stored in its configmap. The CLI creates a secret for
+// them instead, which is deleted along with the job. The host key of an SSH repository is checked against the
+// known_hosts key of the secret of the SSH key, unless SkipHostKeyCheck is set
 type GitSync struct {
-	Sync           *bool  `yaml:"sync,omitempty"`
-	Image          string `yaml:"image,omitempty"`
-	Repository     string `yaml:"repository,omitempty"`
-	ByRevision     bool   `yaml:"byRevision,omitempty"`
-	Branch         string `yaml:"branch,omitempty"`
-	Revision       string `yaml:"revision,omitempty"`
-	Username       string `yaml:"username,omitempty"`
-	Password       string `yaml:"password,omitempty"`
-	UseCredentials bool   `yaml:"useCredentials,omitempty"`
-	Directory      string `yaml:"directory,omitempty"`
+	Sync           *bool         `yaml:"sync,omitempty"`
+	Image          string        `yaml:"image,omitempty"`
+	Repository     string        `yaml:"repository,omitempty"`
+	ByRevision     bool          `yaml:"byRevision,omitempty"`
+	Branch         string        `yaml:"branch,omitempty"`
+	Revision       string        `yaml:"revision,omitempty"`
+	Username       string        `yaml:"username,omitempty"`
+	Password       string        `yaml:"-"`
+	PasswordSecret *SecretKeyRef `yaml:"passwordSecret,omitempty"`
+	SSHKeySecret   *SecretKeyRef `yaml:"sshKeySecret,omitempty"`
+	UseCredentials bool          `yaml:"useCredentials,omitempty"`
+	Directory      string        `yaml:"directory,omitempty"`
+	// SkipHostKeyCheck is only set by skipHostKeyCheck=true, since it lets a man in the middle serve the code
+	SkipHostKeyCheck bool `yaml:"skipHostKeyCheck,omitempty"`
+
+	// NAME/KEY references to existing secrets, and the paths of a local SSH key and its known hosts, as given by the user
+	PasswordSecretRef string `yaml:"-"`
+	SSHKeySecretRef   string `yaml:"-"`
+	SSHKeyFile        string `yaml:"-"`
+	KnownHostsFile    string `yaml:"-"`
+	sshKey            []byte
+	knownHosts        []byte
+	// The credentials of the job this job was submitted from, which are copied to the secret of this job
+	copiedCredentials map[string][]byte
 }
 
 func NewGitSync() *GitSync {
@@ -49,7 +85,10 @@ func (gs *GitSync) HandleGitSync() error {
 	} else if gs.Branch == "" {
 		gs.Branch = defaultBranch
 	}
-	if gs.Username != "" || gs.Password != "" {
+	if err := gs.handleCredentials(); err != nil {
+		return err
+	}
+	if gs.Username != "" || gs.PasswordSecret != nil {
 		gs.UseCredentials = true
 	}
 	if gs.Image == "" {
@@ -65,6 +104,113 @@ func (gs *GitSync) HandleGitSync() error {
 	return nil
 }
 
+func (gs *GitSync) handleCredentials() error {
+	if gs.Password != "" && gs.PasswordSecretRef != "" {
+		return fmt.Errorf("git sync cannot contain both password and passwordSecret")
+	}
+	if gs.SSHKeyFile != "" && gs.SSHKeySecretRef != "" {
+		return fmt.Errorf("git sync cannot contain both sshKey and sshKeySecret")
+	}
+
+	var err error
+	if gs.PasswordSecretRef != "" {
+		if gs.PasswordSecret, err = parseSecretKeyRef(gs.PasswordSecretRef, gitSyncPasswordKey); err != nil {
+			return fmt.Errorf("invalid git sync passwordSecret: %v", err)
+		}
+	} else if gs.Password != "" {
+		gs.PasswordSecret = &SecretKeyRef{Key: gitSyncPasswordKey}
+	}
+
+	if gs.SSHKeySecretRef != "" {
+		if gs.SSHKeySecret, err = parseSecretKeyRef(gs.SSHKeySecretRef, gitSyncSSHKeyKey); err != nil {
+			return fmt.Errorf("invalid git sync sshKeySecret: %v", err)
+		}
+	} else if gs.SSHKeyFile != "" {
+		if gs.sshKey, err = ioutil.ReadFile(gs.SSHKeyFile); err != nil {
+			return fmt.Errorf("failed to read the git sync SSH key: %v", err)
+		}
+		gs.SSHKeySecret = &SecretKeyRef{Key: gitSyncSSHKeyKey}
+		if !gs.SkipHostKeyCheck {
+			if gs.knownHosts, err = readKnownHosts(gs.KnownHostsFile); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// readKnownHosts reads the known hosts of the SSH key file, which are stored in the secret of the job with the key
+func readKnownHosts(knownHostsFile string) ([]byte, error) {
+	if knownHostsFile == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, err
+		}
+		knownHostsFile = filepath.Join(home, ".ssh", gitSyncKnownHostsKey)
+	}
+	knownHosts, err := ioutil.ReadFile(knownHostsFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the known hosts of the git sync repository, set knownHosts=FILE, or skipHostKeyCheck=true to not check the host key: %v", err)
+	}
+	return knownHosts, nil
+}
+
+// parseSecretKeyRef parses a NAME/KEY reference to a key of a secret. The key may be omitted
+func parseSecretKeyRef(ref string, defaultKey string) (*SecretKeyRef, error) {
+	parts := strings.SplitN(ref, "/", 2)
+	secretKeyRef := &SecretKeyRef{Name: parts[0], Key: defaultKey}
+	if len(parts) == 2 {
+		secretKeyRef.Key = parts[1]
+	}
+	if secretKeyRef.Name == "" || secretKeyRef.Key == "" {
+		return nil, fmt.Errorf("expected NAME/KEY, got '%s'", ref)
+	}
+	return secretKeyRef, nil
+}
+
+// hasOwnedCredentials returns whether any of the credentials are in the secret which the CLI creates for the job
+func (gs *GitSync) hasOwnedCredentials() bool {
+	for _, secret := range []*SecretKeyRef{gs.PasswordSecret, gs.SSHKeySecret} {
+		if secret != nil && secret.Name == "" {
+			return true
+		}
+	}
+	return false
+}
+
+// GitSyncSecretName is the name of the secret which the CLI creates for the credentials of a job
+func GitSyncSecretName(jobName string) string {
+	return fmt.Sprintf("%s-git-sync", jobName)
+}
+
+// ownedSecret returns the secret of the local credentials of the job, or nil if the job has none
+func (gs *GitSync) ownedSecret(jobName string) *corev1.Secret {
+	data := map[string][]byte{}
+	for key, value := range gs.copiedCredentials {
+		data[key] = value
+	}
+	if gs.Password != "" && gs.PasswordSecretRef == "" {
+		data[gitSyncPasswordKey] = []byte(gs.Password)
+	}
+	if len(gs.sshKey) > 0 {
+		data[gitSyncSSHKeyKey] = gs.sshKey
+	}
+	if len(gs.knownHosts) > 0 {
+		data[gitSyncKnownHostsKey] = gs.knownHosts
+	}
+	if len(data) == 0 {
+		return nil
+	}
+
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: GitSyncSecretName(jobName),
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: data,
+	}
+}
+
 func GitSyncFromConnectionString(connectionString string) *GitSync {
 	parameters := strings.Split(connectionString, ",")
 
@@ -103,6 +249,16 @@ func ParseGitSyncArguments(arguments map[string]string) *GitSync {
 			syncObject.Username = value
 		case "password":
 			syncObject.Password = value
+		case "passwordSecret":
+			syncObject.PasswordSecretRef = value
+		case "sshKey":
+			syncObject.SSHKeyFile = value
+		case "sshKeySecret":
+			syncObject.SSHKeySecretRef = value
+		case "knownHosts":
+			syncObject.KnownHostsFile = value
+		case "skipHostKeyCheck":
+			syncObject.SkipHostKeyCheck = value == "true"
 		case "target":
 			syncObject.Directory = value
 		}
@@ -0,0 +1,50 @@
+package submit
+
+import (
+	"fmt"
+	"path"
+)
+
+const (
+	defaultSSHAuthorizedKeysKey  = "authorized_keys"
+	defaultSSHAuthorizedKeysPath = "/root/.ssh/authorized_keys"
+	// The authorized keys file in the temporary home directory, the home directory of a job which runs as a user
+	homeDirSSHAuthorizedKeysPath = "/home/runai-home/.ssh/authorized_keys"
+)
+
+// SSH is the access to the SSH server of the job, through 'runai ssh-config'. The authorized keys are mounted from
+// an existing secret to the authorized keys file of the user of the SSH server
+type SSH struct {
+	AuthorizedKeysSecret *SecretKeyRef `yaml:"authorizedKeysSecret"`
+	AuthorizedKeysPath   string        `yaml:"authorizedKeysPath"`
+}
+
+// NewSSH returns the SSH access of the authorized keys in a secret, given as NAME/KEY, mounted to the given path
+func NewSSH(authorizedKeysSecretRef string, authorizedKeysPath string) (*SSH, error) {
+	secretKeyRef, err := parseSecretKeyRef(authorizedKeysSecretRef, defaultSSHAuthorizedKeysKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --ssh: %v", err)
+	}
+	if !path.IsAbs(authorizedKeysPath) {
+		return nil, fmt.Errorf("invalid --ssh-authorized-keys-path: %s is not an absolute path", authorizedKeysPath)
+	}
+	return &SSH{AuthorizedKeysSecret: secretKeyRef, AuthorizedKeysPath: authorizedKeysPath}, nil
+}
+
+// sshAuthorizedKeysPath returns the authorized keys file of the user the job runs as, unless it is set explicitly.
+// The home directory of a user given by its id is unknown, so a job which runs as a user gets the temporary home
+// directory, which is its $HOME
+func (submitArgs *submitArgs) sshAuthorizedKeysPath(authorizedKeysPath string) (string, error) {
+	if authorizedKeysPath != "" {
+		return authorizedKeysPath, nil
+	}
+	if submitArgs.RunAsUser == "" || submitArgs.RunAsUser == "0" {
+		return defaultSSHAuthorizedKeysPath, nil
+	}
+	if submitArgs.CreateHomeDir != nil && !*submitArgs.CreateHomeDir {
+		return "", fmt.Errorf("the job runs as user %s without a home directory, use --ssh-authorized-keys-path to set the authorized keys file of the user", submitArgs.RunAsUser)
+	}
+	createHomeDir := true
+	submitArgs.CreateHomeDir = &createHomeDir
+	return homeDirSSHAuthorizedKeysPath, nil
+}
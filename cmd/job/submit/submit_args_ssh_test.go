@@ -0,0 +1,46 @@
+package submit
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+func TestNewSSH(t *testing.T) {
+	ssh, err := NewSSH("my-keys", defaultSSHAuthorizedKeysPath)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, ssh, &SSH{AuthorizedKeysSecret: &SecretKeyRef{Name: "my-keys", Key: "authorized_keys"}, AuthorizedKeysPath: "/root/.ssh/authorized_keys"})
+
+	ssh, err = NewSSH("my-keys/laptop", defaultSSHAuthorizedKeysPath)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, ssh.AuthorizedKeysSecret, &SecretKeyRef{Name: "my-keys", Key: "laptop"})
+
+	_, err = NewSSH("/laptop", defaultSSHAuthorizedKeysPath)
+	assert.ErrorContains(t, err, "expected NAME/KEY")
+
+	_, err = NewSSH("my-keys", ".ssh/authorized_keys")
+	assert.ErrorContains(t, err, "is not an absolute path")
+}
+
+func TestSSHAuthorizedKeysPath(t *testing.T) {
+	args := &submitArgs{}
+	authorizedKeysPath, err := args.sshAuthorizedKeysPath("")
+	assert.NilError(t, err)
+	assert.Equal(t, authorizedKeysPath, "/root/.ssh/authorized_keys")
+
+	// A job which runs as a user has the authorized keys in its temporary home directory
+	args = &submitArgs{RunAsUser: "1000"}
+	authorizedKeysPath, err = args.sshAuthorizedKeysPath("")
+	assert.NilError(t, err)
+	assert.Equal(t, authorizedKeysPath, "/home/runai-home/.ssh/authorized_keys")
+	assert.Equal(t, *args.CreateHomeDir, true)
+
+	createHomeDir := false
+	args = &submitArgs{RunAsUser: "1000", CreateHomeDir: &createHomeDir}
+	_, err = args.sshAuthorizedKeysPath("")
+	assert.ErrorContains(t, err, "use --ssh-authorized-keys-path")
+
+	authorizedKeysPath, err = args.sshAuthorizedKeysPath("/home/john/.ssh/authorized_keys")
+	assert.NilError(t, err)
+	assert.Equal(t, authorizedKeysPath, "/home/john/.ssh/authorized_keys")
+}
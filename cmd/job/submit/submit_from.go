@@ -0,0 +1,208 @@
+package submit
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/run-ai/runai-cli/cmd/flags"
+	"github.com/run-ai/runai-cli/cmd/job"
+	"github.com/run-ai/runai-cli/pkg/client"
+	"github.com/run-ai/runai-cli/pkg/util"
+	"github.com/run-ai/runai-cli/pkg/workflow"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	fromJobFlag      = "from"
+	resubmitCommand  = "resubmit"
+	jobIndexLabel    = "runai/job-index"
+	resubmitExamples = `
+# Submit a copy of the job train1, named train1-0 (or the next free index)
+runai resubmit train1
+
+# Submit a copy of the job train1 with another image and more GPUs
+runai resubmit train1 -i gcr.io/run-ai-demo/quickstart:v2 -g 2
+
+# Submit a copy of the job train1 under a given name, running another command
+runai resubmit train1 --name train2 -- python train.py --epochs 20
+
+# The same, using the submit command
+runai submit --from train1 --name train2 -- python train.py --epochs 20
+`
+)
+
+var (
+	fromJob string
+
+	// The environment variables which are set for the user the job runs as, see applyRunAsAuthenticatedUser
+	runAsUserEnvironmentVariables = []string{"LDAP_UID=", "LDAP_GID="}
+)
+
+func NewResubmitCommand() *cobra.Command {
+	command := NewRunaiJobCommand()
+	command.Use = "resubmit JOB [flags] -- [COMMAND] [args...] [options]"
+	command.Short = "Submit a new job with the configuration of an existing job."
+	command.Long = "Submit a new job with the configuration of an existing job, as it was submitted. Flags override the configuration of the existing job. The new job is named after the existing job, with an index as suffix, unless --name is given."
+	command.Example = resubmitExamples
+	command.ValidArgsFunction = job.GenJobNames
+	command.Args = func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 || cmd.ArgsLenAtDash() == 0 {
+			return fmt.Errorf("the name of the job to resubmit must be set")
+		}
+		return nil
+	}
+
+	run := command.Run
+	command.Run = func(cmd *cobra.Command, args []string) {
+		if cmd.Flags().Changed(fromJobFlag) {
+			fmt.Printf("The flag --%s cannot be used with %s, the job is its first argument\n", fromJobFlag, resubmitCommand)
+			os.Exit(1)
+		}
+		fromJob = args[0]
+		run(cmd, args)
+	}
+
+	return command
+}
+
+// submitRunaiJobFromExistingJob submits a new job with the values of an existing job, merged with the flags of the command
+func submitRunaiJobFromExistingJob(cmd *cobra.Command, args []string, cliArgs *submitRunaiJobArgs) error {
+	argsUntilDash := args
+	if cmd.ArgsLenAtDash() != -1 {
+		argsUntilDash = args[:cmd.ArgsLenAtDash()]
+	}
+	if cmd.Name() == resubmitCommand {
+		argsUntilDash = argsUntilDash[1:]
+	}
+	if len(argsUntilDash) > 0 {
+		return fmt.Errorf("unexpected arguments %v, use --name to set the name of the new job", argsUntilDash)
+	}
+
+	chartsFolder, err := util.GetChartsFolder()
+	if err != nil {
+		return err
+	}
+	kubeClient, err := client.GetClient()
+	if err != nil {
+		return err
+	}
+	namespaceInfo, err := flags.GetNamespaceToUseFromProjectFlagAndPrintError(cmd, kubeClient)
+	if err != nil {
+		return err
+	}
+
+	values, err := workflow.GetJobChartValues(fromJob, namespaceInfo, path.Join(chartsFolder, "runai"), kubeClient.GetClientset())
+	if err != nil {
+		return err
+	}
+	existing, err := parseSubmittedJobValues(fromJob, values)
+	if err != nil {
+		return fmt.Errorf("could not read the configuration of job %s: %v", fromJob, err)
+	}
+	if err = copyGitSyncCredentials(existing.GitSync, fromJob, namespaceInfo.Namespace, kubeClient.GetClientset()); err != nil {
+		return err
+	}
+
+	// The default of the flag should not override the image pull policy of the existing job
+	if !cmd.Flags().Changed(imagePullPolicyFlag) {
+		cliArgs.ImagePullPolicy = ""
+	}
+	// The GPUs may be requested either as devices or as memory, so the flags replace both
+	if cmd.Flags().Changed("gpu") || cmd.Flags().Changed("gpu-memory") {
+		existing.GPU = nil
+		existing.GPUMemory = ""
+	}
+	// A command given by the flags replaces the command of the existing job, along with whether it is a command
+	if commandArgs := convertOldCommandArgsFlags(cmd, &cliArgs.submitArgs, args); len(commandArgs) > 0 {
+		existing.Command = nil
+	}
+	cliArgs.GitSync = GitSyncFromConnectionString(gitSyncConnectionString)
+
+	// The arguments were already checked, the name of the new job is set by --name or by the name prefix
+	jobArgs := mergeManifestToRunaiSubmitArgs(*cliArgs, existing)
+	return runSubmitRunaiJob(cmd, []string{}, &jobArgs, jobArgs.commandAndArgs())
+}
+
+// parseSubmittedJobValues parses the values a job was submitted with, leaving out the values which are set when
+// submitting a job, so they are set again for the new job
+func parseSubmittedJobValues(jobName string, values string) (submitRunaiJobArgs, error) {
+	existing := submitRunaiJobArgs{}
+	// Unlike job manifests, unknown keys are ignored, as the job may have been submitted by another version of the CLI
+	if err := yaml.Unmarshal([]byte(values), &existing); err != nil {
+		return existing, err
+	}
+
+	existing.NamePrefix = resubmittedJobNamePrefix(jobName, existing.submitArgs)
+	existing.NameParameter = ""
+	existing.Name = ""
+	existing.Namespace = ""
+	existing.Project = ""
+	existing.User = ""
+	existing.CliCommand = ""
+	delete(existing.Labels, jobIndexLabel)
+
+	// The user the job runs as is the one who submits it
+	existing.RunAsUser = ""
+	existing.RunAsGroup = ""
+	existing.SupplementalGroups = nil
+	environment := []string{}
+	for _, environmentVariable := range existing.EnvironmentVariable {
+		if !hasAnyPrefix(environmentVariable, runAsUserEnvironmentVariables) {
+			environment = append(environment, environmentVariable)
+		}
+	}
+	existing.EnvironmentVariable = environment
+
+	// The requested GPUs are converted again, the GPU memory having been converted to MiB
+	existing.GPUInt = nil
+	existing.GPUFraction = ""
+	if existing.GPUMemory != "" {
+		existing.GPUMemory = fmt.Sprintf("%sM", existing.GPUMemory)
+	}
+
+	return existing, nil
+}
+
+// copyGitSyncCredentials copies the credentials of git-sync which were given by the user to the secret of the new job,
+// since the secret of the existing job is deleted along with it
+func copyGitSyncCredentials(gitSync *GitSync, jobName, namespace string, clientset kubernetes.Interface) error {
+	if gitSync == nil || !gitSync.hasOwnedCredentials() {
+		return nil
+	}
+	secret, err := clientset.CoreV1().Secrets(namespace).Get(GitSyncSecretName(jobName), metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("could not read the git-sync credentials of job %s: %v", jobName, err)
+	}
+	gitSync.copiedCredentials = secret.Data
+	return nil
+}
+
+// resubmittedJobNamePrefix returns the prefix of the names of the jobs submitted from the given job. A job whose name
+// was generated from the default name gets a generated name as well
+func resubmittedJobNamePrefix(jobName string, existing submitArgs) string {
+	if existing.NamePrefix != "" {
+		return existing.NamePrefix
+	}
+	if existing.NameParameter != "" {
+		return existing.NameParameter
+	}
+	if regexp.MustCompile(fmt.Sprintf("^%s-[0-9]+$", jobDefaultName)).MatchString(jobName) {
+		return ""
+	}
+	return jobName
+}
+
+func hasAnyPrefix(s string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(s, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,139 @@
+package submit
+
+import (
+	"testing"
+
+	"github.com/magiconair/properties/assert"
+	"gopkg.in/yaml.v2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func newSubmittedJobValues(t *testing.T) string {
+	gpu := float64(1)
+	gpuInt := 1
+	isCommand := true
+	submitted := submitRunaiJobArgs{}
+	submitted.Name = "train1"
+	submitted.NameParameter = "train1"
+	submitted.Namespace = "runai-team"
+	submitted.Project = "team"
+	submitted.User = "john"
+	submitted.Image = "gcr.io/run-ai-demo/quickstart"
+	submitted.ImagePullPolicy = pullPolicyIfNotPresent
+	submitted.GPU = &gpu
+	submitted.GPUInt = &gpuInt
+	submitted.EnvironmentVariable = []string{"EPOCHS=10", "LDAP_UID=1000", "LDAP_GID=1000"}
+	submitted.RunAsUser = "1000"
+	submitted.RunAsGroup = "1000"
+	submitted.SupplementalGroups = []int{1000, 2000}
+	submitted.Labels = map[string]string{"runai/job-index": "3", "team": "vision"}
+	submitted.SpecCommand = []string{"python", "train.py"}
+	submitted.Command = &isCommand
+	submitted.CliCommand = "runai submit --name train1"
+	submitted.GitSync = &GitSync{Repository: "https://github.com/run-ai/docs.git", PasswordSecret: &SecretKeyRef{Key: gitSyncPasswordKey}}
+
+	values, err := yaml.Marshal(submitted)
+	if err != nil {
+		t.Fatalf("Failed to marshal the values, %s", err)
+	}
+	return string(values)
+}
+
+func TestParseSubmittedJobValues(t *testing.T) {
+	existing, err := parseSubmittedJobValues("train1", newSubmittedJobValues(t))
+	if err != nil {
+		t.Fatalf("Failed to parse the values, %s", err)
+	}
+
+	assert.Equal(t, existing.Image, "gcr.io/run-ai-demo/quickstart")
+	assert.Equal(t, existing.ImagePullPolicy, pullPolicyIfNotPresent)
+	assert.Equal(t, *existing.GPU, float64(1))
+	assert.Equal(t, existing.GPUInt, (*int)(nil))
+	assert.Equal(t, existing.commandAndArgs(), []string{"python", "train.py"})
+
+	// The values which are set when submitting the job are left out
+	assert.Equal(t, existing.NamePrefix, "train1")
+	assert.Equal(t, existing.NameParameter, "")
+	assert.Equal(t, existing.Name, "")
+	assert.Equal(t, existing.Namespace, "")
+	assert.Equal(t, existing.Project, "")
+	assert.Equal(t, existing.User, "")
+	assert.Equal(t, existing.CliCommand, "")
+	assert.Equal(t, existing.Labels, map[string]string{"team": "vision"})
+	assert.Equal(t, existing.EnvironmentVariable, []string{"EPOCHS=10"})
+	assert.Equal(t, existing.RunAsUser, "")
+	assert.Equal(t, existing.SupplementalGroups, []int(nil))
+
+	// The password is read from the secret of the new job, with the credentials copied from the existing job
+	assert.Equal(t, *existing.GitSync.PasswordSecret, SecretKeyRef{Key: gitSyncPasswordKey})
+}
+
+func TestCopyGitSyncCredentials(t *testing.T) {
+	existing, err := parseSubmittedJobValues("train1", newSubmittedJobValues(t))
+	if err != nil {
+		t.Fatalf("Failed to parse the values, %s", err)
+	}
+
+	clientset := fake.NewSimpleClientset()
+	err = copyGitSyncCredentials(existing.GitSync, "train1", "runai-team", clientset)
+	assert.Equal(t, err != nil, true)
+
+	clientset = fake.NewSimpleClientset(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "train1-git-sync", Namespace: "runai-team"},
+		Data:       map[string][]byte{gitSyncPasswordKey: []byte("hunter2")},
+	})
+	err = copyGitSyncCredentials(existing.GitSync, "train1", "runai-team", clientset)
+	assert.Equal(t, err, nil)
+
+	// The new job owns a secret of its own, so it keeps working when the existing job is deleted
+	secrets := existing.OwnedSecrets("train1-0")
+	assert.Equal(t, len(secrets), 1)
+	assert.Equal(t, secrets[0].Name, "train1-0-git-sync")
+	assert.Equal(t, string(secrets[0].Data[gitSyncPasswordKey]), "hunter2")
+}
+
+func TestParseSubmittedJobValuesGPUMemory(t *testing.T) {
+	existing, err := parseSubmittedJobValues("train1", "image: ubuntu\ngpuMemory: \"4000\"\n")
+	if err != nil {
+		t.Fatalf("Failed to parse the values, %s", err)
+	}
+
+	assert.Equal(t, existing.GPUMemory, "4000M")
+	if err = handleRequestedGPUs(&existing.submitArgs); err != nil {
+		t.Fatalf("Failed to handle the requested GPUs, %s", err)
+	}
+	assert.Equal(t, existing.GPUMemory, "4000")
+}
+
+func TestResubmittedJobNamePrefix(t *testing.T) {
+	assert.Equal(t, resubmittedJobNamePrefix("train1-2", submitArgs{NamePrefix: "train1"}), "train1")
+	assert.Equal(t, resubmittedJobNamePrefix("train1", submitArgs{NameParameter: "train1"}), "train1")
+	assert.Equal(t, resubmittedJobNamePrefix("train1", submitArgs{}), "train1")
+	assert.Equal(t, resubmittedJobNamePrefix("job-12", submitArgs{}), "")
+}
+
+func TestMergeSubmittedJobValuesWithFlags(t *testing.T) {
+	existing, err := parseSubmittedJobValues("train1", newSubmittedJobValues(t))
+	if err != nil {
+		t.Fatalf("Failed to parse the values, %s", err)
+	}
+
+	cliGPU := float64(2)
+	cliArgs := submitRunaiJobArgs{}
+	cliArgs.Image = "gcr.io/run-ai-demo/quickstart:v2"
+	cliArgs.GPU = &cliGPU
+	cliArgs.CliCommand = "runai resubmit train1 -i gcr.io/run-ai-demo/quickstart:v2 -g 2"
+	existing.GPU = nil
+
+	merged := mergeManifestToRunaiSubmitArgs(cliArgs, existing)
+
+	assert.Equal(t, merged.Image, "gcr.io/run-ai-demo/quickstart:v2")
+	assert.Equal(t, *merged.GPU, cliGPU)
+	assert.Equal(t, merged.ImagePullPolicy, pullPolicyIfNotPresent)
+	assert.Equal(t, merged.NamePrefix, "train1")
+	assert.Equal(t, merged.CliCommand, "runai resubmit train1 -i gcr.io/run-ai-demo/quickstart:v2 -g 2")
+	assert.Equal(t, merged.EnvironmentVariable, []string{"EPOCHS=10"})
+	assert.Equal(t, merged.commandAndArgs(), []string{"python", "train.py"})
+}
@@ -0,0 +1,206 @@
+package submit
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"reflect"
+
+	"github.com/run-ai/runai-cli/pkg/templates"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	manifestFileFlag       = "file"
+	manifestFromStdin      = "-"
+	imagePullPolicyFlag    = "image-pull-policy"
+	environmentArgsField   = "EnvironmentVariable"
+	specCommandArgsField   = "SpecCommand"
+	specArgumentsArgsField = "SpecArgs"
+)
+
+var (
+	manifestFile string
+)
+
+// readJobManifests reads the jobs defined in a YAML or JSON file, or in the standard input when the path is '-'
+func readJobManifests(path string) ([]submitRunaiJobArgs, error) {
+	var data []byte
+	var err error
+	if path == manifestFromStdin {
+		data, err = ioutil.ReadAll(os.Stdin)
+	} else {
+		data, err = ioutil.ReadFile(path)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	manifests, err := parseJobManifests(data)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse %s: %v", path, err)
+	}
+	if len(manifests) == 0 {
+		return nil, fmt.Errorf("no jobs are defined in %s", path)
+	}
+	return manifests, nil
+}
+
+// parseJobManifests parses a stream of YAML documents (JSON being a subset of YAML). Each document holds either a single
+// job or a list of jobs, using the same keys as the job values, e.g. image, gpu, command, args and environment
+func parseJobManifests(data []byte) ([]submitRunaiJobArgs, error) {
+	manifests := []submitRunaiJobArgs{}
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	for documentIndex := 1; ; documentIndex++ {
+		var document interface{}
+		err := decoder.Decode(&document)
+		if err == io.EOF {
+			return manifests, nil
+		} else if err != nil {
+			return nil, fmt.Errorf("document %d: %v", documentIndex, err)
+		}
+
+		items, isList := document.([]interface{})
+		if !isList {
+			items = []interface{}{document}
+		}
+
+		for _, item := range items {
+			if item == nil {
+				continue
+			}
+
+			manifest, err := parseJobManifest(item)
+			if err != nil {
+				return nil, fmt.Errorf("document %d: %v", documentIndex, err)
+			}
+			manifests = append(manifests, manifest)
+		}
+	}
+}
+
+func parseJobManifest(item interface{}) (submitRunaiJobArgs, error) {
+	manifest := submitRunaiJobArgs{}
+	content, err := yaml.Marshal(item)
+	if err != nil {
+		return manifest, err
+	}
+
+	// Unknown keys are most likely typos, fail on them rather than silently submitting a different job
+	if err = yaml.UnmarshalStrict(content, &manifest); err != nil {
+		return manifest, err
+	}
+
+	// The name key of the values is set from the name parameter when submitting
+	if manifest.NameParameter == "" {
+		manifest.NameParameter = manifest.Name
+	}
+	manifest.Name = ""
+
+	commandArgs, isCommand := mergeOldCommandAndArgsWithNew(0, []string{}, manifest.SpecCommand, manifest.SpecArgs, manifest.Command)
+	setCommandAndArgs(&manifest.submitArgs, commandArgs, isCommand)
+	return manifest, nil
+}
+
+// mergeManifestToRunaiSubmitArgs merges a job manifest into the arguments given by flags. As with templates, values
+// set by flags win and lists are appended. The command and its arguments are taken as a whole from either of them.
+func mergeManifestToRunaiSubmitArgs(cliArgs submitRunaiJobArgs, manifest submitRunaiJobArgs) submitRunaiJobArgs {
+	merged := cliArgs
+	mergeManifestFields(reflect.ValueOf(&merged).Elem(), reflect.ValueOf(manifest))
+
+	if len(cliArgs.SpecCommand) == 0 && len(cliArgs.SpecArgs) == 0 {
+		merged.SpecCommand = manifest.SpecCommand
+		merged.SpecArgs = manifest.SpecArgs
+	} else {
+		merged.SpecCommand = cliArgs.SpecCommand
+		merged.SpecArgs = cliArgs.SpecArgs
+	}
+
+	if merged.ImagePullPolicy == "" {
+		merged.ImagePullPolicy = pullPolicyAlways
+	}
+	return merged
+}
+
+func mergeManifestFields(cliValue, manifestValue reflect.Value) {
+	for i := 0; i < cliValue.NumField(); i++ {
+		field := cliValue.Type().Field(i)
+		cliField := cliValue.Field(i)
+		manifestField := manifestValue.Field(i)
+
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			mergeManifestFields(cliField, manifestField)
+			continue
+		}
+		if !cliField.CanSet() || field.Name == specCommandArgsField || field.Name == specArgumentsArgsField {
+			continue
+		}
+
+		switch cliField.Kind() {
+		case reflect.Slice:
+			// Always allocate new slices, so jobs merged from the same flags do not share their backing array
+			if field.Name == environmentArgsField {
+				cliEnvironment := append([]string{}, cliField.Interface().([]string)...)
+				manifestEnvironment := manifestField.Interface().([]string)
+				cliField.Set(reflect.ValueOf(templates.MergeEnvironmentVariables(&cliEnvironment, &manifestEnvironment)))
+				continue
+			}
+			merged := reflect.MakeSlice(field.Type, 0, cliField.Len()+manifestField.Len())
+			merged = reflect.AppendSlice(merged, cliField)
+			cliField.Set(reflect.AppendSlice(merged, manifestField))
+		case reflect.Map:
+			merged := reflect.MakeMap(field.Type)
+			for _, source := range []reflect.Value{manifestField, cliField} {
+				for _, key := range source.MapKeys() {
+					merged.SetMapIndex(key, source.MapIndex(key))
+				}
+			}
+			cliField.Set(merged)
+		default:
+			if cliField.IsZero() {
+				cliField.Set(manifestField)
+			}
+		}
+	}
+}
+
+// submitRunaiJobsFromManifest submits every job defined in the manifest file, merged with the flags of the command
+func submitRunaiJobsFromManifest(cmd *cobra.Command, args []string, cliArgs *submitRunaiJobArgs) error {
+	manifests, err := readJobManifests(manifestFile)
+	if err != nil {
+		return err
+	}
+
+	argsUntilDash := args
+	if cmd.ArgsLenAtDash() != -1 {
+		argsUntilDash = args[:cmd.ArgsLenAtDash()]
+	}
+	if len(argsUntilDash) > 0 {
+		return fmt.Errorf("unexpected arguments %v, use the name key of the manifest or --name to set the job name", argsUntilDash)
+	}
+	if len(manifests) > 1 && cliArgs.NameParameter != "" {
+		return fmt.Errorf("--name cannot be used when %s defines more than one job", manifestFile)
+	}
+
+	// The default of the flag should not override the image pull policy of the manifest
+	if !cmd.Flags().Changed(imagePullPolicyFlag) {
+		cliArgs.ImagePullPolicy = ""
+	}
+	convertOldCommandArgsFlags(cmd, &cliArgs.submitArgs, args)
+	cliArgs.GitSync = GitSyncFromConnectionString(gitSyncConnectionString)
+
+	for _, manifest := range manifests {
+		jobArgs := mergeManifestToRunaiSubmitArgs(*cliArgs, manifest)
+		if err = runSubmitRunaiJob(cmd, args, &jobArgs, jobArgs.commandAndArgs()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (sa *submitArgs) commandAndArgs() []string {
+	return append(append([]string{}, sa.SpecCommand...), sa.SpecArgs...)
+}
@@ -0,0 +1,94 @@
+package submit
+
+import (
+	"testing"
+
+	"github.com/magiconair/properties/assert"
+)
+
+const jobManifests = `
+name: train1
+image: gcr.io/run-ai-demo/quickstart
+gpu: 1
+environment:
+  - EPOCHS=10
+command: ["python", "train.py"]
+---
+- name: build1
+  image: ubuntu
+  interactive: true
+- name: build2
+  image: ubuntu
+  args: ["sleep", "infinity"]
+---
+{
+	"name": "inference1",
+	"image": "gcr.io/run-ai-demo/example-triton-server",
+	"inference": true,
+	"labels": {"team": "vision"}
+}
+`
+
+func TestParseJobManifests(t *testing.T) {
+	manifests, err := parseJobManifests([]byte(jobManifests))
+	if err != nil {
+		t.Fatalf("Failed to parse manifests, %s", err)
+	}
+
+	assert.Equal(t, len(manifests), 4)
+
+	assert.Equal(t, manifests[0].NameParameter, "train1")
+	assert.Equal(t, manifests[0].Name, "")
+	assert.Equal(t, *manifests[0].GPU, float64(1))
+	assert.Equal(t, manifests[0].EnvironmentVariable, []string{"EPOCHS=10"})
+	assert.Equal(t, manifests[0].SpecCommand, []string{"python", "train.py"})
+	assert.Equal(t, *manifests[0].Command, true)
+
+	assert.Equal(t, manifests[1].NameParameter, "build1")
+	assert.Equal(t, *manifests[1].Interactive, true)
+
+	assert.Equal(t, manifests[2].SpecCommand, []string{})
+	assert.Equal(t, manifests[2].SpecArgs, []string{"sleep", "infinity"})
+	assert.Equal(t, *manifests[2].Command, false)
+
+	assert.Equal(t, manifests[3].NameParameter, "inference1")
+	assert.Equal(t, *manifests[3].Inference, true)
+	assert.Equal(t, manifests[3].Labels["team"], "vision")
+}
+
+func TestParseJobManifestsUnknownKey(t *testing.T) {
+	_, err := parseJobManifests([]byte("name: train1\nimgae: ubuntu\n"))
+	if err == nil {
+		t.Errorf("Expected an error for an unknown key")
+	}
+}
+
+func TestMergeManifestToRunaiSubmitArgs(t *testing.T) {
+	manifests, err := parseJobManifests([]byte(jobManifests))
+	if err != nil {
+		t.Fatalf("Failed to parse manifests, %s", err)
+	}
+
+	cliGPU := float64(2)
+	cliArgs := submitRunaiJobArgs{}
+	cliArgs.GPU = &cliGPU
+	cliArgs.EnvironmentVariable = []string{"EPOCHS=20", "BATCH=64"}
+	cliArgs.Volumes = []string{"/data:/data"}
+
+	merged := mergeManifestToRunaiSubmitArgs(cliArgs, manifests[0])
+
+	assert.Equal(t, merged.NameParameter, "train1")
+	assert.Equal(t, merged.Image, "gcr.io/run-ai-demo/quickstart")
+	assert.Equal(t, *merged.GPU, cliGPU)
+	assert.Equal(t, merged.EnvironmentVariable, []string{"EPOCHS=20", "BATCH=64"})
+	assert.Equal(t, merged.Volumes, []string{"/data:/data"})
+	assert.Equal(t, merged.ImagePullPolicy, pullPolicyAlways)
+	assert.Equal(t, merged.commandAndArgs(), []string{"python", "train.py"})
+
+	cliArgs.SpecArgs = []string{"evaluate.py"}
+	merged = mergeManifestToRunaiSubmitArgs(cliArgs, manifests[0])
+
+	assert.Equal(t, merged.SpecCommand, []string(nil))
+	assert.Equal(t, merged.SpecArgs, []string{"evaluate.py"})
+	assert.Equal(t, *merged.Command, true)
+}
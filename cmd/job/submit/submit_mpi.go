@@ -31,7 +31,6 @@ import (
 	"github.com/run-ai/runai-cli/pkg/client"
 	"github.com/run-ai/runai-cli/pkg/config"
 	"github.com/run-ai/runai-cli/pkg/util"
-	"github.com/run-ai/runai-cli/pkg/workflow"
 	"github.com/spf13/cobra"
 )
 
@@ -172,17 +171,17 @@ func submitMPIJob(cmd *cobra.Command, args []string, submitArgs *submitMPIJobArg
 
 	// the master is also considered as a worker
 	// submitArgs.WorkerCount = submitArgs.WorkerCount - 1
-	submitArgs.Name, err = workflow.SubmitJob(submitArgs.Name, submitArgs.Namespace, submitArgs.generateSuffix, submitArgs, mpijob_chart, client.GetClientset(), dryRun)
+	submitArgs.Name, err = submitArgs.submitWorkflowJob(submitArgs, mpijob_chart, client.GetClientset())
 	if err != nil {
 		return err
 	}
 
-	if !dryRun {
+	if !isSubmitPreview() {
 		fmt.Printf("The job '%s' has been submitted successfully\n", submitArgs.Name)
 		fmt.Printf("You can run `%s describe job %s -p %s` to check the job status\n", config.CLIName, submitArgs.Name, submitArgs.Project)
 
 		if submitArgs.Attach != nil && *submitArgs.Attach {
-			if err := attach.Attach(cmd, submitArgs.Name, raUtil.IsBoolPTrue(submitArgs.StdIn), raUtil.IsBoolPTrue(submitArgs.TTY), "", attach.DefaultAttachTimeout); err != nil {
+			if err := attach.Attach(cmd, submitArgs.Name, raUtil.IsBoolPTrue(submitArgs.StdIn), raUtil.IsBoolPTrue(submitArgs.TTY), "", "", attach.DefaultAttachTimeout); err != nil {
 				fmt.Println(err)
 				os.Exit(1)
 			}
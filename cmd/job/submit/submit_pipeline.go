@@ -0,0 +1,380 @@
+package submit
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/run-ai/runai-cli/cmd/completion"
+	"github.com/run-ai/runai-cli/cmd/constants"
+	"github.com/run-ai/runai-cli/cmd/flags"
+	"github.com/run-ai/runai-cli/cmd/job"
+	"github.com/run-ai/runai-cli/cmd/trainer"
+	"github.com/run-ai/runai-cli/pkg/authentication/assertion"
+	"github.com/run-ai/runai-cli/pkg/client"
+	"github.com/run-ai/runai-cli/pkg/config"
+	"github.com/run-ai/runai-cli/pkg/types"
+	"github.com/run-ai/runai-cli/pkg/util"
+	commandUtil "github.com/run-ai/runai-cli/pkg/util/command"
+	"github.com/run-ai/runai-cli/pkg/workflow"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	SubmitPipelineCommand  = "submit-pipeline"
+	submitPipelineExamples = `
+# Submit the stages of a pipeline, each once the stages it depends on have succeeded
+runai submit-pipeline -f pipeline.yaml
+
+# Where pipeline.yaml defines the stages and the job of each stage, using the keys of 'runai submit -f'
+name: quickstart
+stages:
+- name: preprocess
+  job:
+    image: gcr.io/run-ai-demo/quickstart
+    command: ["python", "preprocess.py"]
+- name: train
+  dependsOn: [preprocess]
+  job:
+    image: gcr.io/run-ai-demo/quickstart
+    gpu: 1
+`
+)
+
+// pipelineRetryInterval is the time to wait before getting the statuses of the pipeline jobs again after a failure
+const pipelineRetryInterval = 10 * time.Second
+
+var pipelineFile string
+
+type pipelineManifest struct {
+	Name   string                  `yaml:"name"`
+	Stages []pipelineStageManifest `yaml:"stages"`
+}
+
+type pipelineStageManifest struct {
+	Name      string      `yaml:"name"`
+	DependsOn []string    `yaml:"dependsOn,omitempty"`
+	Job       interface{} `yaml:"job"`
+}
+
+func NewSubmitPipelineCommand() *cobra.Command {
+	var command = &cobra.Command{
+		Use:   SubmitPipelineCommand + " -f FILENAME",
+		Short: "Submit a pipeline of jobs which depend on each other.",
+		Long: `Submit a pipeline of jobs which depend on each other.
+
+The stages of the pipeline are submitted in the order of their dependencies, a stage is submitted once all the stages it depends on have succeeded.
+A stage is skipped when one of the stages it depends on does not succeed. The command keeps running until all the stages have finished.
+The state of the pipeline is saved in the cluster, if the command is interrupted run it again with the same file to resume the pipeline.`,
+		Example:           submitPipelineExamples,
+		ValidArgsFunction: completion.NoArgs,
+		Args:              cobra.NoArgs,
+		PreRun:            commandUtil.NamespacedRoleAssertion(assertion.AssertExecutorRole),
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := submitPipeline(cmd); err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+		},
+	}
+
+	command.Flags().StringVarP(&pipelineFile, manifestFileFlag, "f", "", "The YAML or JSON file that defines the pipeline ('-' to read from the standard input).")
+	command.MarkFlagRequired(manifestFileFlag)
+
+	return command
+}
+
+func submitPipeline(cmd *cobra.Command) error {
+	var data []byte
+	var err error
+	if pipelineFile == manifestFromStdin {
+		data, err = ioutil.ReadAll(os.Stdin)
+	} else {
+		data, err = ioutil.ReadFile(pipelineFile)
+	}
+	if err != nil {
+		return err
+	}
+
+	pipeline, stageArgs, err := parsePipelineManifest(data)
+	if err != nil {
+		return fmt.Errorf("could not parse %s: %v", pipelineFile, err)
+	}
+
+	kubeClient, err := client.GetClient()
+	if err != nil {
+		return err
+	}
+
+	namespaceInfo, err := flags.GetNamespaceToUseFromProjectFlagAndPrintError(cmd, kubeClient)
+	if err != nil {
+		return err
+	}
+	if namespaceInfo.ProjectName == "" {
+		return fmt.Errorf("Define a project by --project flag, alternatively set a project as default")
+	}
+
+	// Start watching before the pipeline is created so no status change of its jobs is missed
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	changes, err := trainer.WatchJobChanges(kubeClient, namespaceInfo.Namespace, stopCh)
+	if err != nil {
+		return err
+	}
+
+	pipeline, resumed, err := workflow.CreatePipeline(pipeline, namespaceInfo.Namespace, kubeClient.GetClientset())
+	if err != nil {
+		return err
+	}
+	if resumed {
+		fmt.Printf("The pipeline '%s' already exists, resuming it\n", pipeline.Name)
+	} else {
+		fmt.Printf("The pipeline '%s' has been created\n", pipeline.Name)
+	}
+	fmt.Printf("You can run `%s describe pipeline %s -p %s` to check the pipeline status\n", config.CLIName, pipeline.Name, namespaceInfo.ProjectName)
+
+	for {
+		jobStatuses, err := getPipelineJobStatuses(kubeClient, namespaceInfo, pipeline)
+		if err != nil {
+			// The statuses are retried, a stage is never skipped because the status of its dependencies is unknown
+			fmt.Printf("Failed to get the status of the pipeline jobs, retrying: %v\n", err)
+			select {
+			case <-changes:
+			case <-time.After(pipelineRetryInterval):
+			}
+			continue
+		}
+		toSubmit, toSkip, done := nextPipelineActions(pipeline, jobStatuses)
+		if done {
+			return pipelineResult(pipeline, jobStatuses)
+		}
+
+		for _, stageIndex := range toSkip {
+			stage := &pipeline.Stages[stageIndex]
+			fmt.Printf("Skipping stage %s, as not all the stages it depends on succeeded\n", stage.Name)
+			stage.State = workflow.PipelineStageSkipped
+		}
+
+		if len(toSkip) > 0 {
+			if err = workflow.UpdatePipeline(pipeline, namespaceInfo.Namespace, kubeClient.GetClientset()); err != nil {
+				return err
+			}
+		}
+
+		// The state is saved after every submission, so that a resumed pipeline does not submit a stage twice
+		for _, stageIndex := range toSubmit {
+			stage := &pipeline.Stages[stageIndex]
+			if err = submitPipelineStage(cmd, kubeClient, namespaceInfo, stage, stageArgs[stage.Name]); err != nil {
+				fmt.Printf("Failed to submit stage %s: %v\n", stage.Name, err)
+				stage.State = workflow.PipelineStageSkipped
+			} else {
+				stage.State = workflow.PipelineStageSubmitted
+			}
+			if err = workflow.UpdatePipeline(pipeline, namespaceInfo.Namespace, kubeClient.GetClientset()); err != nil {
+				return err
+			}
+		}
+
+		if len(toSubmit) > 0 || len(toSkip) > 0 {
+			continue
+		}
+
+		<-changes
+	}
+}
+
+// submitPipelineStage submits the job of a stage, unless it was submitted by an interrupted run of the pipeline
+func submitPipelineStage(cmd *cobra.Command, kubeClient *client.Client, namespaceInfo types.NamespaceInfo, stage *workflow.PipelineStage, jobArgs submitRunaiJobArgs) error {
+	status, err := job.GetJobStatusByName(kubeClient, namespaceInfo, stage.JobName)
+	if err != nil {
+		return err
+	}
+	if status != constants.Status.Deleted {
+		fmt.Printf("The job %s of stage %s was already submitted\n", stage.JobName, stage.Name)
+		return nil
+	}
+
+	jobArgs.Project = namespaceInfo.ProjectName
+	return runSubmitRunaiJob(cmd, []string{}, &jobArgs, jobArgs.commandAndArgs())
+}
+
+// parsePipelineManifest validates the graph of the pipeline and parses the job of each of its stages
+func parsePipelineManifest(data []byte) (*workflow.Pipeline, map[string]submitRunaiJobArgs, error) {
+	manifest := pipelineManifest{}
+	if err := yaml.UnmarshalStrict(data, &manifest); err != nil {
+		return nil, nil, err
+	}
+
+	if manifest.Name == "" {
+		return nil, nil, fmt.Errorf("the pipeline must have a name")
+	} else if err := util.ValidateJobName(manifest.Name); err != nil {
+		return nil, nil, err
+	}
+	if len(manifest.Stages) == 0 {
+		return nil, nil, fmt.Errorf("the pipeline must have at least one stage")
+	}
+
+	pipeline := &workflow.Pipeline{Name: manifest.Name}
+	stageArgs := map[string]submitRunaiJobArgs{}
+	for _, stage := range manifest.Stages {
+		if stage.Name == "" {
+			return nil, nil, fmt.Errorf("every stage of the pipeline must have a name")
+		} else if _, found := stageArgs[stage.Name]; found {
+			return nil, nil, fmt.Errorf("the stage %s is defined more than once", stage.Name)
+		} else if stage.Job == nil {
+			return nil, nil, fmt.Errorf("the stage %s has no job", stage.Name)
+		}
+
+		jobArgs, err := parseJobManifest(stage.Job)
+		if err != nil {
+			return nil, nil, fmt.Errorf("stage %s: %v", stage.Name, err)
+		}
+		if jobArgs.NameParameter == "" {
+			jobArgs.NameParameter = fmt.Sprintf("%s-%s", manifest.Name, stage.Name)
+		}
+		if err = util.ValidateJobName(jobArgs.NameParameter); err != nil {
+			return nil, nil, fmt.Errorf("stage %s: %v", stage.Name, err)
+		}
+
+		jobArgs = mergeManifestToRunaiSubmitArgs(submitRunaiJobArgs{}, jobArgs)
+		jobArgs.Labels[workflow.PipelineLabelSelectorName] = manifest.Name
+		jobArgs.addCliCommand()
+		stageArgs[stage.Name] = jobArgs
+
+		pipeline.Stages = append(pipeline.Stages, workflow.PipelineStage{
+			Name:      stage.Name,
+			JobName:   jobArgs.NameParameter,
+			DependsOn: stage.DependsOn,
+			State:     workflow.PipelineStageWaiting,
+		})
+	}
+
+	if err := validatePipelineDependencies(pipeline); err != nil {
+		return nil, nil, err
+	}
+	return pipeline, stageArgs, nil
+}
+
+func validatePipelineDependencies(pipeline *workflow.Pipeline) error {
+	dependencies := map[string][]string{}
+	for _, stage := range pipeline.Stages {
+		dependencies[stage.Name] = stage.DependsOn
+	}
+
+	for _, stage := range pipeline.Stages {
+		for _, dependency := range stage.DependsOn {
+			if _, found := dependencies[dependency]; !found {
+				return fmt.Errorf("the stage %s depends on the stage %s which does not exist", stage.Name, dependency)
+			}
+		}
+	}
+
+	// Detect cycles with a depth first search, a stage in progress that is reached again closes a cycle
+	const (
+		inProgress = 1
+		visited    = 2
+	)
+	states := map[string]int{}
+	var visit func(name string, path []string) error
+	visit = func(name string, path []string) error {
+		switch states[name] {
+		case inProgress:
+			return fmt.Errorf("the stages of the pipeline have a circular dependency: %s", strings.Join(append(path, name), " -> "))
+		case visited:
+			return nil
+		}
+
+		states[name] = inProgress
+		for _, dependency := range dependencies[name] {
+			if err := visit(dependency, append(path, name)); err != nil {
+				return err
+			}
+		}
+		states[name] = visited
+		return nil
+	}
+
+	for _, stage := range pipeline.Stages {
+		if err := visit(stage.Name, []string{}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// getPipelineJobStatuses returns the status of the job of every submitted stage, by stage name
+func getPipelineJobStatuses(kubeClient *client.Client, namespaceInfo types.NamespaceInfo, pipeline *workflow.Pipeline) (map[string]string, error) {
+	jobStatuses := map[string]string{}
+	for _, stage := range pipeline.Stages {
+		if stage.State == workflow.PipelineStageSubmitted {
+			status, err := job.GetJobStatusByName(kubeClient, namespaceInfo, stage.JobName)
+			if err != nil {
+				return nil, err
+			}
+			jobStatuses[stage.Name] = status
+		}
+	}
+	return jobStatuses, nil
+}
+
+// nextPipelineActions returns the indexes of the waiting stages which should be submitted or skipped, and whether all
+// the stages of the pipeline have finished
+func nextPipelineActions(pipeline *workflow.Pipeline, jobStatuses map[string]string) ([]int, []int, bool) {
+	states := map[string]string{}
+	for _, stage := range pipeline.Stages {
+		states[stage.Name] = stage.State
+	}
+
+	toSubmit := []int{}
+	toSkip := []int{}
+	done := true
+	for i, stage := range pipeline.Stages {
+		switch stage.State {
+		case workflow.PipelineStageSubmitted:
+			if !trainer.IsFinishedStatus(jobStatuses[stage.Name]) {
+				done = false
+			}
+		case workflow.PipelineStageWaiting:
+			done = false
+			ready, blocked := true, false
+			for _, dependency := range stage.DependsOn {
+				dependencyStatus := jobStatuses[dependency]
+				if states[dependency] == workflow.PipelineStageSkipped ||
+					(trainer.IsFinishedStatus(dependencyStatus) && dependencyStatus != constants.Status.Succeeded) {
+					blocked = true
+				} else if dependencyStatus != constants.Status.Succeeded {
+					ready = false
+				}
+			}
+
+			if blocked {
+				toSkip = append(toSkip, i)
+			} else if ready {
+				toSubmit = append(toSubmit, i)
+			}
+		}
+	}
+	return toSubmit, toSkip, done
+}
+
+func pipelineResult(pipeline *workflow.Pipeline, jobStatuses map[string]string) error {
+	unsuccessfulStages := []string{}
+	for _, stage := range pipeline.Stages {
+		status := jobStatuses[stage.Name]
+		if stage.State == workflow.PipelineStageSkipped {
+			status = workflow.PipelineStageSkipped
+		}
+		if status != constants.Status.Succeeded {
+			unsuccessfulStages = append(unsuccessfulStages, fmt.Sprintf("%s (%s)", stage.Name, status))
+		}
+	}
+
+	if len(unsuccessfulStages) > 0 {
+		return fmt.Errorf("The pipeline '%s' has finished, but not all its stages succeeded: %s", pipeline.Name, strings.Join(unsuccessfulStages, ", "))
+	}
+	fmt.Printf("The pipeline '%s' has succeeded\n", pipeline.Name)
+	return nil
+}
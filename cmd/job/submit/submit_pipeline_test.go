@@ -0,0 +1,121 @@
+package submit
+
+import (
+	"testing"
+
+	"github.com/magiconair/properties/assert"
+	"github.com/run-ai/runai-cli/cmd/constants"
+	"github.com/run-ai/runai-cli/pkg/workflow"
+)
+
+const pipelineManifestYaml = `
+name: quickstart
+stages:
+- name: preprocess
+  job:
+    image: ubuntu
+    command: ["python", "preprocess.py"]
+- name: train
+  dependsOn: [preprocess]
+  job:
+    name: train-job
+    image: gcr.io/run-ai-demo/quickstart
+    gpu: 1
+- name: evaluate
+  dependsOn: [train]
+  job:
+    image: ubuntu
+`
+
+func TestParsePipelineManifest(t *testing.T) {
+	pipeline, stageArgs, err := parsePipelineManifest([]byte(pipelineManifestYaml))
+	if err != nil {
+		t.Fatalf("Failed to parse pipeline, %s", err)
+	}
+
+	assert.Equal(t, pipeline.Name, "quickstart")
+	assert.Equal(t, len(pipeline.Stages), 3)
+	assert.Equal(t, pipeline.Stages[0].JobName, "quickstart-preprocess")
+	assert.Equal(t, pipeline.Stages[1].JobName, "train-job")
+	assert.Equal(t, pipeline.Stages[1].DependsOn, []string{"preprocess"})
+	assert.Equal(t, pipeline.Stages[2].State, workflow.PipelineStageWaiting)
+
+	assert.Equal(t, stageArgs["preprocess"].NameParameter, "quickstart-preprocess")
+	assert.Equal(t, stageArgs["train"].Labels[workflow.PipelineLabelSelectorName], "quickstart")
+	assert.Equal(t, *stageArgs["train"].GPU, float64(1))
+}
+
+func TestParsePipelineManifestInvalidGraph(t *testing.T) {
+	manifests := map[string]string{
+		"missing dependency": `
+name: p
+stages:
+- name: a
+  dependsOn: [b]
+  job: {image: ubuntu}
+`,
+		"circular dependency": `
+name: p
+stages:
+- name: a
+  dependsOn: [c]
+  job: {image: ubuntu}
+- name: b
+  dependsOn: [a]
+  job: {image: ubuntu}
+- name: c
+  dependsOn: [b]
+  job: {image: ubuntu}
+`,
+		"duplicate stage": `
+name: p
+stages:
+- name: a
+  job: {image: ubuntu}
+- name: a
+  job: {image: ubuntu}
+`,
+	}
+
+	for name, manifest := range manifests {
+		t.Run(name, func(t *testing.T) {
+			if _, _, err := parsePipelineManifest([]byte(manifest)); err == nil {
+				t.Errorf("Expected the pipeline to be invalid")
+			}
+		})
+	}
+}
+
+func TestNextPipelineActions(t *testing.T) {
+	pipeline, _, err := parsePipelineManifest([]byte(pipelineManifestYaml))
+	if err != nil {
+		t.Fatalf("Failed to parse pipeline, %s", err)
+	}
+
+	toSubmit, toSkip, done := nextPipelineActions(pipeline, map[string]string{})
+	assert.Equal(t, toSubmit, []int{0})
+	assert.Equal(t, toSkip, []int{})
+	assert.Equal(t, done, false)
+
+	pipeline.Stages[0].State = workflow.PipelineStageSubmitted
+	toSubmit, toSkip, done = nextPipelineActions(pipeline, map[string]string{"preprocess": constants.Status.Running})
+	assert.Equal(t, toSubmit, []int{})
+	assert.Equal(t, toSkip, []int{})
+	assert.Equal(t, done, false)
+
+	toSubmit, _, _ = nextPipelineActions(pipeline, map[string]string{"preprocess": constants.Status.Succeeded})
+	assert.Equal(t, toSubmit, []int{1})
+
+	toSubmit, toSkip, _ = nextPipelineActions(pipeline, map[string]string{"preprocess": constants.Status.Failed})
+	assert.Equal(t, toSubmit, []int{})
+	assert.Equal(t, toSkip, []int{1})
+
+	pipeline.Stages[1].State = workflow.PipelineStageSkipped
+	toSubmit, toSkip, done = nextPipelineActions(pipeline, map[string]string{"preprocess": constants.Status.Failed})
+	assert.Equal(t, toSkip, []int{2})
+	assert.Equal(t, done, false)
+
+	pipeline.Stages[2].State = workflow.PipelineStageSkipped
+	_, _, done = nextPipelineActions(pipeline, map[string]string{"preprocess": constants.Status.Failed})
+	assert.Equal(t, done, true)
+}
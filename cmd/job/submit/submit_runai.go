@@ -6,7 +6,6 @@ import (
 	"os"
 	"path"
 	"regexp"
-	"strings"
 	"time"
 
 	"github.com/run-ai/runai-cli/cmd/completion"
@@ -20,15 +19,16 @@ import (
 
 	"github.com/run-ai/runai-cli/cmd/attach"
 	"github.com/run-ai/runai-cli/cmd/flags"
+	"github.com/run-ai/runai-cli/cmd/portforward"
 	"github.com/run-ai/runai-cli/cmd/trainer"
 
 	runaiclientset "github.com/run-ai/runai-cli/cmd/mpi/client/clientset/versioned"
 	raUtil "github.com/run-ai/runai-cli/cmd/util"
 	"github.com/run-ai/runai-cli/pkg/client"
 	"github.com/run-ai/runai-cli/pkg/config"
+	forward "github.com/run-ai/runai-cli/pkg/portforward"
 	"github.com/run-ai/runai-cli/pkg/util"
 	"github.com/run-ai/runai-cli/pkg/util/kubectl"
-	"github.com/run-ai/runai-cli/pkg/workflow"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -53,6 +53,32 @@ runai submit --name hpo1 -i gcr.io/run-ai-demo/quickstart-hpo -g 1  \
 
 # Auto generate job name
 runai submit -i gcr.io/run-ai-demo/quickstart -g 1
+
+# Submit a copy of an existing job with more GPUs
+runai submit --from train1 -g 2
+
+# Submit the jobs defined in a file, overriding their GPUs
+runai submit -f jobs.yaml -g 2
+
+# Check a job with the cluster (admission webhooks, quotas and policies) without submitting it
+runai submit --name train1 -i gcr.io/run-ai-demo/quickstart -g 1 --dry-run
+
+# Show how a job would change compared to the existing job of the same name
+runai submit --name train1 -i gcr.io/run-ai-demo/quickstart -g 2 --diff
+
+# Hyperparameter sweep, submitting a job for every trial of sweep.yaml. For example, a grid search over:
+#   strategy: grid
+#   parameters:
+#     LR: [0.1, 0.01]
+#     BATCH_SIZE: [32, 64]
+# or 10 trials sampled with a fixed seed:
+#   strategy: random
+#   seed: 42
+#   trials: 10
+#   parameters:
+#     LR: {min: 0.0001, max: 0.1, log: true}
+#     BATCH_SIZE: [32, 64]
+runai submit --name hpo2 -i gcr.io/run-ai-demo/quickstart-hpo -g 1 --sweep sweep.yaml
 `
 )
 
@@ -71,148 +97,183 @@ func NewRunaiJobCommand() *cobra.Command {
 		Example:               submitExamples,
 		PreRun:                commandUtil.NamespacedRoleAssertion(assertion.AssertExecutorRole),
 		Run: func(cmd *cobra.Command, args []string) {
-			chartsFolder, err := util.GetChartsFolder()
-			if err != nil {
-				fmt.Println(err)
-				os.Exit(1)
+			if fromJob != "" {
+				if sweepFile != "" || manifestFile != "" {
+					fmt.Printf("The flag --%s cannot be used together with --%s or --%s\n", fromJobFlag, sweepFlag, manifestFileFlag)
+					os.Exit(1)
+				}
+				if err := submitRunaiJobFromExistingJob(cmd, args, submitArgs); err != nil {
+					fmt.Println(err)
+					os.Exit(1)
+				}
+				return
 			}
 
-			runaiChart = path.Join(chartsFolder, "runai")
-
-			kubeClient, err := client.GetClient()
-			if err != nil {
-				fmt.Println(err)
-				os.Exit(1)
+			if sweepFile != "" {
+				if manifestFile != "" {
+					fmt.Println("The flags --sweep and --file cannot be used together")
+					os.Exit(1)
+				}
+				if err := submitSweep(cmd, args, submitArgs); err != nil {
+					fmt.Println(err)
+					os.Exit(1)
+				}
+				return
 			}
 
-			clientset := kubeClient.GetClientset()
-			runaijobClient := runaiclientset.NewForConfigOrDie(kubeClient.GetRestConfig())
+			if manifestFile != "" {
+				if err := submitRunaiJobsFromManifest(cmd, args, submitArgs); err != nil {
+					fmt.Println(err)
+					os.Exit(1)
+				}
+				return
+			}
 
 			commandArgs := convertOldCommandArgsFlags(cmd, &submitArgs.submitArgs, args)
 			submitArgs.GitSync = GitSyncFromConnectionString(gitSyncConnectionString)
 
-			err = applyTemplate(submitArgs, commandArgs, clientset)
-			if err != nil {
+			if err := runSubmitRunaiJob(cmd, args, submitArgs, commandArgs); err != nil {
 				fmt.Println(err)
 				os.Exit(1)
 			}
+		},
+	}
 
-			err = submitArgs.setCommonRun(cmd, args, kubeClient, clientset)
-			if err != nil {
-				fmt.Println(err)
-				os.Exit(1)
-			}
+	fbg := flags.NewFlagsByGroups(command)
+	submitArgs.addCommonSubmit(fbg)
 
-			if submitArgs.TtlAfterFinished != nil {
-				ttlSeconds := int(math.Round(submitArgs.TtlAfterFinished.Seconds()))
-				log.Debugf("Using time to live seconds %d", ttlSeconds)
-				submitArgs.TTL = &ttlSeconds
-			}
+	submitArgs.addFlags(fbg)
 
-			if raUtil.IsBoolPTrue(submitArgs.IsJupyter) {
-				submitArgs.UseJupyterDefaultValues()
-			}
+	fbg.UpdateFlagsByGroupsToCmd()
 
-			if raUtil.IsBoolPTrue(submitArgs.Interactive) {
-				if raUtil.IsBoolPTrue(submitArgs.Inference) {
-					fmt.Println("\nThe flags --inference and --interactive cannot be used together")
-					os.Exit(1)
-				}
-				interactiveCompletions := 1
-				submitArgs.Completions = &interactiveCompletions
-			}
+	job.AddSubmitFlagsCompletion(command)
+	command.RegisterFlagCompletionFunc(fromJobFlag, job.GenJobNames)
 
-			if len(submitArgs.Image) == 0 {
-				fmt.Print("\n-i, --image must be set\n\n")
-				os.Exit(1)
-			}
+	return command
+}
 
-			err = submitRunaiJob(submitArgs, clientset, *runaijobClient)
-			if err != nil {
-				fmt.Println(err)
-				os.Exit(1)
-			}
+func runSubmitRunaiJob(cmd *cobra.Command, args []string, submitArgs *submitRunaiJobArgs, commandArgs []string) error {
+	chartsFolder, err := util.GetChartsFolder()
+	if err != nil {
+		return err
+	}
 
-			printJobInfoIfNeeded(submitArgs)
-			if raUtil.IsBoolPTrue(submitArgs.IsJupyter) || (submitArgs.Interactive != nil && *submitArgs.Interactive && submitArgs.ServiceType == "portforward") {
-				kubeClient, err := client.GetClient()
-				if err != nil {
-					return
-				}
+	runaiChart = path.Join(chartsFolder, "runai")
 
-				_, err = exec.WaitForPodToStartRunning(cmd, kubeClient, submitArgs.Name, "", time.Minute)
-				if err != nil {
-					return
-				}
+	kubeClient, err := client.GetClient()
+	if err != nil {
+		return err
+	}
 
-				if err != nil {
-					fmt.Println(err)
-					os.Exit(1)
-				}
+	clientset := kubeClient.GetClientset()
+	runaijobClient := runaiclientset.NewForConfigOrDie(kubeClient.GetRestConfig())
+
+	err = applyTemplate(submitArgs, commandArgs, clientset)
+	if err != nil {
+		return err
+	}
 
-				if raUtil.IsBoolPTrue(submitArgs.IsJupyter) {
-					runaiTrainer := trainer.NewRunaiTrainer(*kubeClient)
-					job, err := runaiTrainer.GetTrainingJob(submitArgs.Name, submitArgs.Namespace)
+	err = submitArgs.setCommonRun(cmd, args, kubeClient, clientset)
+	if err != nil {
+		return err
+	}
 
-					if err != nil {
-						fmt.Println(err)
-						os.Exit(1)
-					}
+	if submitArgs.TtlAfterFinished != nil {
+		ttlSeconds := int(math.Round(submitArgs.TtlAfterFinished.Seconds()))
+		log.Debugf("Using time to live seconds %d", ttlSeconds)
+		submitArgs.TTL = &ttlSeconds
+	}
 
-					pod := job.ChiefPod()
-					logs, err := kubectl.Logs(pod.Name, pod.Namespace)
+	if raUtil.IsBoolPTrue(submitArgs.IsJupyter) {
+		submitArgs.UseJupyterDefaultValues()
+	}
 
-					token, err := getTokenFromJupyterLogs(string(logs))
+	if raUtil.IsBoolPTrue(submitArgs.Interactive) {
+		if raUtil.IsBoolPTrue(submitArgs.Inference) {
+			return fmt.Errorf("\nThe flags --inference and --interactive cannot be used together")
+		}
+		interactiveCompletions := 1
+		submitArgs.Completions = &interactiveCompletions
+	}
 
-					if err != nil {
-						fmt.Println(err)
-						fmt.Printf("Please run '%s logs %s' to view the logs.\n", config.CLIName, submitArgs.Name)
-					}
+	if len(submitArgs.Image) == 0 {
+		return fmt.Errorf("\n-i, --image must be set\n")
+	}
 
-					fmt.Printf("Jupyter notebook token: %s\n", token)
-				}
+	err = submitRunaiJob(submitArgs, clientset, *runaijobClient)
+	if err != nil {
+		return err
+	}
+	if isSubmitPreview() {
+		return nil
+	}
 
-				if submitArgs.Interactive != nil && *submitArgs.Interactive && submitArgs.ServiceType == "portforward" {
-					localPorts := []string{}
-					for _, port := range submitArgs.Ports {
-						split := strings.Split(port, ":")
-						localPorts = append(localPorts, split[0])
-					}
-
-					localUrls := []string{}
-					for _, localPort := range localPorts {
-						localUrls = append(localUrls, fmt.Sprintf("localhost:%s", localPort))
-					}
-
-					accessPoints := strings.Join(localUrls, ",")
-					fmt.Printf("Open access point(s) to service from %s\n", accessPoints)
-					err = kubectl.PortForward(localPorts, submitArgs.Name, submitArgs.Namespace)
-					if err != nil {
-						fmt.Println(err)
-						os.Exit(1)
-					}
-				}
+	printJobInfoIfNeeded(submitArgs)
+	if raUtil.IsBoolPTrue(submitArgs.IsJupyter) || (submitArgs.Interactive != nil && *submitArgs.Interactive && submitArgs.ServiceType == "portforward") {
+		kubeClient, err := client.GetClient()
+		if err != nil {
+			return nil
+		}
+
+		_, err = exec.WaitForPodToStartRunning(cmd, kubeClient, submitArgs.Name, "", time.Minute)
+		if err != nil {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		jupyterToken := ""
+		if raUtil.IsBoolPTrue(submitArgs.IsJupyter) {
+			runaiTrainer := trainer.NewRunaiTrainer(*kubeClient)
+			job, err := runaiTrainer.GetTrainingJob(submitArgs.Name, submitArgs.Namespace)
+
+			if err != nil {
+				return err
 			}
 
-			if submitArgs.Attach != nil && *submitArgs.Attach {
-				if err := attach.Attach(cmd, submitArgs.Name, raUtil.IsBoolPTrue(submitArgs.StdIn), raUtil.IsBoolPTrue(submitArgs.TTY), "", attach.DefaultAttachTimeout); err != nil {
-					fmt.Println(err)
-					os.Exit(1)
-				}
+			pod := job.ChiefPod()
+			logs, err := kubectl.Logs(pod.Name, pod.Namespace)
+
+			token, err := getTokenFromJupyterLogs(string(logs))
+
+			if err != nil {
+				fmt.Println(err)
+				fmt.Printf("Please run '%s logs %s' to view the logs.\n", config.CLIName, submitArgs.Name)
 			}
-		},
-	}
 
-	fbg := flags.NewFlagsByGroups(command)
-	submitArgs.addCommonSubmit(fbg)
+			fmt.Printf("Jupyter notebook token: %s\n", token)
+			jupyterToken = token
+		}
 
-	submitArgs.addFlags(fbg)
+		if submitArgs.Interactive != nil && *submitArgs.Interactive && submitArgs.ServiceType == "portforward" {
+			forwarder, err := portforward.NewJobForwarder(cmd, kubeClient, submitArgs.Name, "", submitArgs.Ports)
+			if err != nil {
+				return err
+			}
+			mappings, err := forwarder.Listen()
+			if err != nil {
+				return err
+			}
+			portforward.PrintPortMappings(mappings)
+			if jupyterToken != "" {
+				fmt.Printf("Open the Jupyter notebook at %s/?token=%s\n", mappings[0].URL(), jupyterToken)
+			}
 
-	fbg.UpdateFlagsByGroupsToCmd()
+			if err = forwarder.Run(forward.StopOnInterrupt()); err != nil {
+				return err
+			}
+		}
+	}
 
-	job.AddSubmitFlagsCompletion(command)
+	if submitArgs.Attach != nil && *submitArgs.Attach {
+		if err := attach.Attach(cmd, submitArgs.Name, raUtil.IsBoolPTrue(submitArgs.StdIn), raUtil.IsBoolPTrue(submitArgs.TTY), "", "", attach.DefaultAttachTimeout); err != nil {
+			return err
+		}
+	}
 
-	return command
+	return nil
 }
 
 func applyTemplate(submitArgs interface{}, extraArgs []string, clientset kubernetes.Interface) error {
@@ -356,9 +417,12 @@ func (sa *submitRunaiJobArgs) addFlags(fbg flags.FlagsByGroups) {
 	flags.AddIntNullableFlag(fs, &(sa.Completions), "completions", "Number of successful pods required for this job to be completed. Used with HPO.")
 	flags.AddIntNullableFlag(fs, &(sa.Parallelism), "parallelism", "Number of pods to run in parallel at any given time.  Used with HPO.")
 	flags.AddDurationNullableFlagP(fs, &(sa.TtlAfterFinished), "ttl-after-finish", "", "The duration, after which a finished job is automatically deleted (e.g. 5s, 2m, 3h).")
+	fs.StringVar(&sweepFile, sweepFlag, "", "Submit a job for every trial of the hyperparameter sweep defined in a YAML or JSON file. The parameters of each trial are set as environment variables.")
 
 	fs = fbg.GetOrAddFlagSet(AliasesAndShortcutsFlagGroup)
 	flags.AddBoolNullableFlag(fs, &(sa.Inference), "inference", "", "Mark this Job as inference.")
+	fs.StringVarP(&manifestFile, manifestFileFlag, "f", "", "Submit the jobs defined in a YAML or JSON file ('-' to read from the standard input). Flags override the values of the file.")
+	fs.StringVar(&fromJob, fromJobFlag, "", "Submit a new job with the configuration of an existing job of the project. Flags override the configuration of the job.")
 
 	// Hidden flags
 	flags.AddBoolNullableFlag(fs, &(sa.IsOldJob), "old-job", "", "submit a job of resource k8s job")
@@ -379,11 +443,11 @@ func submitRunaiJob(submitArgs *submitRunaiJobArgs, clientset kubernetes.Interfa
 		return err
 	}
 	handleRunaiJobCRD(submitArgs, runaiclientset)
-	submitArgs.Name, err = workflow.SubmitJob(submitArgs.Name, submitArgs.Namespace, submitArgs.generateSuffix, submitArgs, runaiChart, clientset, dryRun)
+	submitArgs.Name, err = submitArgs.submitWorkflowJob(submitArgs, runaiChart, clientset)
 	if err != nil {
 		return err
 	}
-	if !dryRun {
+	if !isSubmitPreview() {
 		fmt.Printf("The job '%s' has been submitted successfully\n", submitArgs.Name)
 		fmt.Printf("You can run `%s describe job %s -p %s` to check the job status\n", config.CLIName, submitArgs.Name, submitArgs.Project)
 	}
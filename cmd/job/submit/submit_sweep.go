@@ -0,0 +1,294 @@
+package submit
+
+import (
+	"fmt"
+	"io/ioutil"
+	"math"
+	"math/rand"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/run-ai/runai-cli/cmd/constants"
+	"github.com/run-ai/runai-cli/pkg/config"
+	"github.com/run-ai/runai-cli/pkg/templates"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	sweepFlag = "sweep"
+
+	SweepStrategyGrid   = "grid"
+	SweepStrategyRandom = "random"
+	SweepStrategyList   = "list"
+
+	maxSweepTrials = 1000
+)
+
+var (
+	sweepFile string
+
+	sweepParameterNameRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+)
+
+// sweepSpec describes how to expand a job into trials, each running with its own values of the parameters
+type sweepSpec struct {
+	Strategy   string                     `yaml:"strategy"`
+	Seed       *int64                     `yaml:"seed,omitempty"`
+	Trials     int                        `yaml:"trials,omitempty"`
+	Parameters map[string]*sweepParameter `yaml:"parameters,omitempty"`
+	Runs       []map[string]interface{}   `yaml:"runs,omitempty"`
+}
+
+// sweepParameter holds either the values to choose from, or a range to sample from with the random strategy
+type sweepParameter struct {
+	Values  []interface{} `yaml:"values,omitempty"`
+	Min     *float64      `yaml:"min,omitempty"`
+	Max     *float64      `yaml:"max,omitempty"`
+	Integer bool          `yaml:"integer,omitempty"`
+	Log     bool          `yaml:"log,omitempty"`
+}
+
+// UnmarshalYAML allows the values of a parameter to be given directly as a list
+func (p *sweepParameter) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var values []interface{}
+	if err := unmarshal(&values); err == nil {
+		p.Values = values
+		return nil
+	}
+
+	type plainSweepParameter sweepParameter
+	return unmarshal((*plainSweepParameter)(p))
+}
+
+func readSweepSpec(path string) (*sweepSpec, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	spec := &sweepSpec{}
+	if err = yaml.UnmarshalStrict(data, spec); err != nil {
+		return nil, fmt.Errorf("could not parse the sweep spec %s: %v", path, err)
+	}
+	return spec, nil
+}
+
+// expandSweep returns the parameters of every trial of the sweep, formatted as environment variable values
+func expandSweep(spec *sweepSpec) ([]map[string]string, error) {
+	for name := range spec.Parameters {
+		if !sweepParameterNameRegexp.MatchString(name) {
+			return nil, fmt.Errorf("the sweep parameter '%s' is not a valid environment variable name", name)
+		}
+	}
+
+	var trials []map[string]string
+	var err error
+	switch spec.Strategy {
+	case SweepStrategyGrid:
+		trials, err = expandGridSweep(spec)
+	case SweepStrategyRandom:
+		trials, err = expandRandomSweep(spec)
+	case SweepStrategyList:
+		trials, err = expandListSweep(spec)
+	default:
+		return nil, fmt.Errorf("unknown sweep strategy '%s', one of: %s, %s, %s", spec.Strategy, SweepStrategyGrid, SweepStrategyRandom, SweepStrategyList)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(trials) == 0 {
+		return nil, fmt.Errorf("the sweep has no trials")
+	} else if len(trials) > maxSweepTrials {
+		return nil, fmt.Errorf("the sweep has %d trials, which is more than the maximum of %d", len(trials), maxSweepTrials)
+	}
+	return trials, nil
+}
+
+func sortedSweepParameterNames(spec *sweepSpec) []string {
+	names := []string{}
+	for name := range spec.Parameters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// expandGridSweep returns the cartesian product of the values of all the parameters
+func expandGridSweep(spec *sweepSpec) ([]map[string]string, error) {
+	if len(spec.Parameters) == 0 {
+		return nil, fmt.Errorf("a grid sweep must have parameters")
+	}
+
+	trials := []map[string]string{{}}
+	for _, name := range sortedSweepParameterNames(spec) {
+		parameter := spec.Parameters[name]
+		if parameter == nil || len(parameter.Values) == 0 {
+			return nil, fmt.Errorf("the parameter '%s' of a grid sweep must have values", name)
+		}
+
+		expandedTrials := []map[string]string{}
+		for _, trial := range trials {
+			for _, value := range parameter.Values {
+				expandedTrial := map[string]string{name: formatSweepValue(value)}
+				for trialName, trialValue := range trial {
+					expandedTrial[trialName] = trialValue
+				}
+				expandedTrials = append(expandedTrials, expandedTrial)
+			}
+		}
+		trials = expandedTrials
+	}
+	return trials, nil
+}
+
+// expandRandomSweep samples the given number of trials, the same seed always samples the same trials
+func expandRandomSweep(spec *sweepSpec) ([]map[string]string, error) {
+	if spec.Trials <= 0 {
+		return nil, fmt.Errorf("a random sweep must set the number of trials")
+	}
+	if len(spec.Parameters) == 0 {
+		return nil, fmt.Errorf("a random sweep must have parameters")
+	}
+
+	seed := time.Now().UnixNano()
+	if spec.Seed != nil {
+		seed = *spec.Seed
+	} else {
+		log.Infof("Using random seed %d, set it as the seed of the sweep to sample the same trials again", seed)
+	}
+	random := rand.New(rand.NewSource(seed))
+
+	names := sortedSweepParameterNames(spec)
+	trials := []map[string]string{}
+	for i := 0; i < spec.Trials; i++ {
+		trial := map[string]string{}
+		for _, name := range names {
+			value, err := sampleSweepParameter(random, name, spec.Parameters[name])
+			if err != nil {
+				return nil, err
+			}
+			trial[name] = value
+		}
+		trials = append(trials, trial)
+	}
+	return trials, nil
+}
+
+func sampleSweepParameter(random *rand.Rand, name string, parameter *sweepParameter) (string, error) {
+	if parameter == nil {
+		return "", fmt.Errorf("the parameter '%s' must have values or a range", name)
+	}
+	if len(parameter.Values) > 0 {
+		return formatSweepValue(parameter.Values[random.Intn(len(parameter.Values))]), nil
+	}
+	if parameter.Min == nil || parameter.Max == nil || *parameter.Min > *parameter.Max {
+		return "", fmt.Errorf("the parameter '%s' must have values or a range with min <= max", name)
+	}
+
+	min, max := *parameter.Min, *parameter.Max
+	if parameter.Integer {
+		if math.Floor(max) < math.Ceil(min) {
+			return "", fmt.Errorf("the range of the parameter '%s' has no integers", name)
+		}
+		return strconv.FormatInt(int64(math.Ceil(min))+random.Int63n(int64(math.Floor(max)-math.Ceil(min))+1), 10), nil
+	}
+	if parameter.Log {
+		if min <= 0 {
+			return "", fmt.Errorf("the range of the parameter '%s' must be positive to sample it on a log scale", name)
+		}
+		return strconv.FormatFloat(math.Exp(math.Log(min)+random.Float64()*(math.Log(max)-math.Log(min))), 'g', 6, 64), nil
+	}
+	return strconv.FormatFloat(min+random.Float64()*(max-min), 'g', 6, 64), nil
+}
+
+// expandListSweep returns the runs of the spec as they are
+func expandListSweep(spec *sweepSpec) ([]map[string]string, error) {
+	trials := []map[string]string{}
+	for _, run := range spec.Runs {
+		trial := map[string]string{}
+		for name, value := range run {
+			if !sweepParameterNameRegexp.MatchString(name) {
+				return nil, fmt.Errorf("the sweep parameter '%s' is not a valid environment variable name", name)
+			}
+			trial[name] = formatSweepValue(value)
+		}
+		trials = append(trials, trial)
+	}
+	return trials, nil
+}
+
+func formatSweepValue(value interface{}) string {
+	if floatValue, isFloat := value.(float64); isFloat {
+		return strconv.FormatFloat(floatValue, 'g', -1, 64)
+	}
+	return fmt.Sprint(value)
+}
+
+// sweepEnvironmentVariables converts the parameters of a trial to environment variables, sorted by name
+func sweepEnvironmentVariables(trial map[string]string) []string {
+	environmentVariables := []string{}
+	for name, value := range trial {
+		environmentVariables = append(environmentVariables, fmt.Sprintf("%s=%s", name, value))
+	}
+	sort.Strings(environmentVariables)
+	return environmentVariables
+}
+
+// submitSweep submits a job for every trial of the sweep. The parameters of the trial are set as environment variables
+// and override the ones set by flags, and all the trials carry the sweep label so they can be listed together.
+func submitSweep(cmd *cobra.Command, args []string, cliArgs *submitRunaiJobArgs) error {
+	spec, err := readSweepSpec(sweepFile)
+	if err != nil {
+		return err
+	}
+
+	trials, err := expandSweep(spec)
+	if err != nil {
+		return err
+	}
+
+	commandArgs := convertOldCommandArgsFlags(cmd, &cliArgs.submitArgs, args)
+	cliArgs.GitSync = GitSyncFromConnectionString(gitSyncConnectionString)
+
+	sweepName, generateSuffix, err := getJobNameWithSuffixGenerationFlag(cmd, args, &cliArgs.submitArgs)
+	if err != nil {
+		return err
+	}
+	if generateSuffix {
+		return fmt.Errorf("a sweep must be named, use --name to set its name")
+	}
+
+	for i, trial := range trials {
+		trialArgs := *cliArgs
+		trialArgs.NameParameter = fmt.Sprintf("%s-%d", sweepName, i+1)
+
+		// Copy whatever the submission modifies, so the trials do not share it
+		trialEnvironment := sweepEnvironmentVariables(trial)
+		cliEnvironment := append([]string{}, cliArgs.EnvironmentVariable...)
+		trialArgs.EnvironmentVariable = templates.MergeEnvironmentVariables(&trialEnvironment, &cliEnvironment)
+		trialArgs.Volumes = append([]string{}, cliArgs.Volumes...)
+		trialArgs.PersistentVolumes = append([]string{}, cliArgs.PersistentVolumes...)
+		trialArgs.Ports = append([]string{}, cliArgs.Ports...)
+		trialArgs.Labels = map[string]string{}
+		for key, value := range cliArgs.Labels {
+			trialArgs.Labels[key] = value
+		}
+		trialArgs.Labels[constants.RunaiSweepLabel] = sweepName
+		trialArgs.Labels[constants.RunaiSweepTrialLabel] = strconv.Itoa(i + 1)
+
+		if err = runSubmitRunaiJob(cmd, []string{}, &trialArgs, commandArgs); err != nil {
+			return fmt.Errorf("failed to submit trial %d of sweep %s: %v", i+1, sweepName, err)
+		}
+	}
+
+	if !isSubmitPreview() {
+		fmt.Printf("The %d trials of sweep '%s' have been submitted successfully\n", len(trials), sweepName)
+		fmt.Printf("You can run `%s list sweeps` to check the status of the sweep\n", config.CLIName)
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package submit
+
+import (
+	"testing"
+
+	"github.com/magiconair/properties/assert"
+	"gopkg.in/yaml.v2"
+)
+
+func parseSweepSpec(t *testing.T, data string) *sweepSpec {
+	spec := &sweepSpec{}
+	if err := yaml.UnmarshalStrict([]byte(data), spec); err != nil {
+		t.Fatalf("Failed to parse sweep spec, %s", err)
+	}
+	return spec
+}
+
+func TestExpandGridSweep(t *testing.T) {
+	spec := parseSweepSpec(t, `
+strategy: grid
+parameters:
+  LR: [0.1, 0.01]
+  BATCH_SIZE:
+    values: [32, 64, 128]
+`)
+
+	trials, err := expandSweep(spec)
+	if err != nil {
+		t.Fatalf("Failed to expand sweep, %s", err)
+	}
+
+	assert.Equal(t, len(trials), 6)
+	assert.Equal(t, trials[0], map[string]string{"BATCH_SIZE": "32", "LR": "0.1"})
+	assert.Equal(t, trials[5], map[string]string{"BATCH_SIZE": "128", "LR": "0.01"})
+}
+
+func TestExpandRandomSweepWithSeed(t *testing.T) {
+	spec := parseSweepSpec(t, `
+strategy: random
+seed: 42
+trials: 5
+parameters:
+  LR: {min: 0.0001, max: 0.1, log: true}
+  EPOCHS: {min: 1, max: 10, integer: true}
+  OPTIMIZER: [adam, sgd]
+`)
+
+	trials, err := expandSweep(spec)
+	if err != nil {
+		t.Fatalf("Failed to expand sweep, %s", err)
+	}
+	sameTrials, _ := expandSweep(spec)
+
+	assert.Equal(t, len(trials), 5)
+	assert.Equal(t, trials, sameTrials)
+	for _, trial := range trials {
+		assert.Equal(t, len(trial), 3)
+	}
+}
+
+func TestExpandListSweep(t *testing.T) {
+	spec := parseSweepSpec(t, `
+strategy: list
+runs:
+- {LR: 0.1, MODEL: resnet}
+- {LR: 0.5, MODEL: vgg}
+`)
+
+	trials, err := expandSweep(spec)
+	if err != nil {
+		t.Fatalf("Failed to expand sweep, %s", err)
+	}
+
+	assert.Equal(t, trials, []map[string]string{{"LR": "0.1", "MODEL": "resnet"}, {"LR": "0.5", "MODEL": "vgg"}})
+	assert.Equal(t, sweepEnvironmentVariables(trials[1]), []string{"LR=0.5", "MODEL=vgg"})
+}
+
+func TestExpandInvalidSweep(t *testing.T) {
+	specs := map[string]string{
+		"unknown strategy":      "strategy: bayes\nparameters: {LR: [1]}",
+		"invalid name":          "strategy: grid\nparameters: {learning-rate: [1]}",
+		"grid without values":   "strategy: grid\nparameters: {LR: {min: 1, max: 2}}",
+		"random without trials": "strategy: random\nparameters: {LR: [1]}",
+		"random invalid range":  "strategy: random\ntrials: 2\nparameters: {LR: {min: 2, max: 1}}",
+		"empty list":            "strategy: list",
+	}
+
+	for name, data := range specs {
+		t.Run(name, func(t *testing.T) {
+			if _, err := expandSweep(parseSweepSpec(t, data)); err == nil {
+				t.Errorf("Expected the sweep to be invalid")
+			}
+		})
+	}
+}
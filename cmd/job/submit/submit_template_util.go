@@ -72,6 +72,8 @@ func mergeGitSync(submitArgs *submitArgs, templateGitSync *templates.GitSyncTemp
 	submitArgs.GitSync.Revision = applyTemplateFieldForString(submitArgs.GitSync.Revision, templateGitSync.Revision, "git-sync.revision")
 	submitArgs.GitSync.Username = applyTemplateFieldForString(submitArgs.GitSync.Username, templateGitSync.Username, "git-sync.username")
 	submitArgs.GitSync.Password = applyTemplateFieldForString(submitArgs.GitSync.Password, templateGitSync.Password, "git-sync.password")
+	submitArgs.GitSync.PasswordSecretRef = applyTemplateFieldForString(submitArgs.GitSync.PasswordSecretRef, templateGitSync.PasswordSecret, "git-sync.passwordSecret")
+	submitArgs.GitSync.SSHKeySecretRef = applyTemplateFieldForString(submitArgs.GitSync.SSHKeySecretRef, templateGitSync.SSHKeySecret, "git-sync.sshKeySecret")
 	submitArgs.GitSync.Image = applyTemplateFieldForString(submitArgs.GitSync.Image, templateGitSync.Image, "git-sync.image")
 	submitArgs.GitSync.Directory = applyTemplateFieldForString(submitArgs.GitSync.Directory, templateGitSync.Directory, "git-sync.target")
 }
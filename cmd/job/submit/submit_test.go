@@ -2,8 +2,21 @@ package submit
 
 import (
 	"fmt"
-	"github.com/magiconair/properties/assert"
+	"io/ioutil"
+	"os"
 	"testing"
+
+	"github.com/magiconair/properties/assert"
+	"github.com/run-ai/runai-cli/cmd/flags"
+	"github.com/run-ai/runai-cli/cmd/global"
+	raUtil "github.com/run-ai/runai-cli/cmd/util"
+	"github.com/run-ai/runai-cli/pkg/client"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
 )
 
 func TestArgsAlignmentNoCommand(t *testing.T) {
@@ -137,3 +150,92 @@ func TestGetSpecCommandAndArgsWithMorePositionalArgument(t *testing.T) {
 	assert.Equal(t, extraArgs[1], "60")
 	assert.Equal(t, *isCommandPtr, false)
 }
+
+func newTestSubmitCommand() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Flags().String(flags.ProjectFlag, "", "")
+	return cmd
+}
+
+func newTestSubmitJobArgs() *submitRunaiJobArgs {
+	submitArgs := NewSubmitRunaiJobArgs()
+	submitArgs.NameParameter = "train1"
+	submitArgs.Project = "team"
+	submitArgs.User = "john"
+	submitArgs.Image = "gcr.io/run-ai-demo/quickstart"
+	submitArgs.ImagePullPolicy = pullPolicyIfNotPresent
+	return submitArgs
+}
+
+func getTestJobIndex(t *testing.T, clientset kubernetes.Interface) string {
+	configMap, err := clientset.CoreV1().ConfigMaps(runaiNamespace).Get("runai-cli-index", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Failed to get the job index, %s", err)
+	}
+	return configMap.Data["index"]
+}
+
+func TestSubmitPreviewDoesNotTakeJobIndex(t *testing.T) {
+	global.LogLevel = "info"
+	defer func() { dryRun, diffJob = false, false }()
+	runaiChart := "../../../charts/runai"
+
+	clientset := fake.NewSimpleClientset(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "runai-team", Labels: map[string]string{raUtil.RUNAI_QUEUE_LABEL: "team"}}},
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "runai-cli-index", Namespace: runaiNamespace}, Data: map[string]string{"index": "11"}},
+	)
+	kubeClient := &client.Client{}
+	kubeClient.SetClientset(clientset)
+
+	// The existing job takes the next index when it is submitted
+	submitted := newTestSubmitJobArgs()
+	if err := submitted.setCommonRun(newTestSubmitCommand(), []string{}, kubeClient, clientset); err != nil {
+		t.Fatalf("Failed to set the job, %s", err)
+	}
+	assert.Equal(t, submitted.Labels[jobIndexLabel], "12")
+	assert.Equal(t, getTestJobIndex(t, clientset), "12")
+
+	values, err := yaml.Marshal(submitted)
+	if err != nil {
+		t.Fatalf("Failed to marshal the values, %s", err)
+	}
+	_, err = clientset.CoreV1().ConfigMaps("runai-team").Create(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "train1", Namespace: "runai-team"},
+		Data:       map[string]string{"values": string(values), "runai": "1.0.3"},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create the configmap of the job, %s", err)
+	}
+
+	for _, preview := range []struct {
+		name    string
+		dryRun  bool
+		diffJob bool
+	}{{"dry run", true, false}, {"diff", false, true}} {
+		dryRun, diffJob = preview.dryRun, preview.diffJob
+		submitArgs := newTestSubmitJobArgs()
+		if err := submitArgs.setCommonRun(newTestSubmitCommand(), []string{}, kubeClient, clientset); err != nil {
+			t.Fatalf("Failed to set the job on %s, %s", preview.name, err)
+		}
+		assert.Equal(t, submitArgs.Labels[jobIndexLabel], "")
+		assert.Equal(t, getTestJobIndex(t, clientset), "12")
+	}
+
+	// The same job is compared as if it replaced the existing job, with the index of the existing job
+	stdout := os.Stdout
+	reader, writer, _ := os.Pipe()
+	os.Stdout = writer
+	submitArgs := newTestSubmitJobArgs()
+	err = submitArgs.setCommonRun(newTestSubmitCommand(), []string{}, kubeClient, clientset)
+	if err == nil {
+		_, err = submitArgs.submitWorkflowJob(submitArgs, runaiChart, clientset)
+	}
+	writer.Close()
+	os.Stdout = stdout
+	output, _ := ioutil.ReadAll(reader)
+	if err != nil {
+		t.Fatalf("Failed to diff the job, %s", err)
+	}
+	assert.Equal(t, string(output), "The job 'train1' is identical to the existing job\n")
+	assert.Equal(t, getTestJobIndex(t, clientset), "12")
+}
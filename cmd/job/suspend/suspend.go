@@ -0,0 +1,134 @@
+package suspend
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/run-ai/runai-cli/cmd/flags"
+	"github.com/run-ai/runai-cli/cmd/job"
+	runaiClient "github.com/run-ai/runai-cli/cmd/mpi/client/clientset/versioned"
+	"github.com/run-ai/runai-cli/cmd/trainer"
+	"github.com/run-ai/runai-cli/pkg/authentication/assertion"
+	"github.com/run-ai/runai-cli/pkg/client"
+	"github.com/run-ai/runai-cli/pkg/types"
+	commandUtil "github.com/run-ai/runai-cli/pkg/util/command"
+	"github.com/run-ai/runai-cli/pkg/workflow"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	suspendExamples = `
+# Release the GPUs of a job, keeping the job to resume it later
+runai suspend train1
+
+# Run the job again, with as many pods as it had before it was suspended
+runai resume train1
+
+# Suspend all your running jobs
+runai suspend --status Running --user me
+`
+)
+
+// updateJobFunc suspends or resumes the workload of a job
+type updateJobFunc func(jobName string, workloadType string, namespaceInfo types.NamespaceInfo, clientset kubernetes.Interface, runaiClientset runaiClient.Interface) error
+
+func NewSuspendCommand() *cobra.Command {
+	var selector job.JobSelector
+
+	command := &cobra.Command{
+		Use:               "suspend JOB_NAME...",
+		Short:             "Suspend a job, releasing its GPUs without deleting it.",
+		Long:              "Suspend a job by scaling it down to zero pods, which releases its GPUs. Unlike deleting the job, its definition is kept and it can be resumed with 'runai resume'.",
+		Example:           suspendExamples,
+		ValidArgsFunction: job.GenJobNames,
+		Args: func(cmd *cobra.Command, args []string) error {
+			return selector.CheckArgs(args)
+		},
+		PreRun: commandUtil.NamespacedRoleAssertion(assertion.AssertExecutorRole),
+		Run: func(cmd *cobra.Command, args []string) {
+			updateJobs(cmd, args, &selector, workflow.SuspendJob, "suspend", "suspended")
+		},
+	}
+
+	selector.AddFlags(command, true)
+	return command
+}
+
+func NewResumeCommand() *cobra.Command {
+	var selector job.JobSelector
+
+	command := &cobra.Command{
+		Use:               "resume JOB_NAME...",
+		Short:             "Resume a suspended job.",
+		Long:              "Resume a job which was suspended by 'runai suspend', with as many pods as it had before it was suspended.",
+		Example:           suspendExamples,
+		ValidArgsFunction: job.GenJobNames,
+		Args: func(cmd *cobra.Command, args []string) error {
+			return selector.CheckArgs(args)
+		},
+		PreRun: commandUtil.NamespacedRoleAssertion(assertion.AssertExecutorRole),
+		Run: func(cmd *cobra.Command, args []string) {
+			updateJobs(cmd, args, &selector, workflow.ResumeJob, "resume", "resumed")
+		},
+	}
+
+	selector.AddFlags(command, true)
+	return command
+}
+
+func updateJobs(cmd *cobra.Command, jobNames []string, selector *job.JobSelector, update updateJobFunc, action string, actionDone string) {
+	kubeClient, err := client.GetClient()
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	namespaceInfo, err := flags.GetNamespaceToUseFromProjectFlag(cmd, kubeClient)
+	if err != nil {
+		log.Debugf("Failed due to %v", err)
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	runaiClientset := runaiClient.NewForConfigOrDie(kubeClient.GetRestConfig())
+
+	if selector.IsSet() {
+		jobs, err := selector.SelectJobs(kubeClient, namespaceInfo)
+		if err != nil {
+			log.Error(err)
+			os.Exit(1)
+		}
+		if len(jobs) == 0 {
+			fmt.Println("No jobs matched the given selector")
+			return
+		}
+		confirmed, err := selector.Confirm(action, jobs)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		if !confirmed {
+			return
+		}
+		jobNames = job.JobNames(jobs)
+	}
+
+	failed := false
+	for _, jobName := range jobNames {
+		jobToUpdate, err := trainer.SearchTrainingJob(kubeClient, jobName, "", namespaceInfo)
+		if err == nil {
+			err = update(jobName, jobToUpdate.WorkloadType(), namespaceInfo, kubeClient.GetClientset(), runaiClientset)
+		}
+		if err != nil {
+			log.Error(err)
+			failed = true
+			continue
+		}
+		fmt.Printf("Successfully %s job: %s\n", actionDone, jobName)
+	}
+
+	if failed {
+		os.Exit(1)
+	}
+}
@@ -18,6 +18,7 @@ import (
 	"fmt"
 	"os"
 	"reflect"
+	"time"
 
 	"github.com/run-ai/runai-cli/cmd/completion"
 	"github.com/run-ai/runai-cli/pkg/authentication/assertion"
@@ -81,6 +82,9 @@ var usageFormatters = map[string]ui.FormatFunction{
 // TopCommand top command
 func TopCommand() *cobra.Command {
 	var allNamespaces bool
+	var watch bool
+	var interval time.Duration
+	var sortBy string
 	var command = &cobra.Command{
 		Use:               "jobs",
 		Aliases:           []string{"job"},
@@ -103,6 +107,18 @@ func TopCommand() *cobra.Command {
 				os.Exit(1)
 			}
 
+			if watch {
+				if err := validateTopJobsWatch(interval, sortBy); err != nil {
+					fmt.Println(err)
+					os.Exit(1)
+				}
+				if err := watchTopJobs(kubeClient, namespaceInfo, sortBy, interval); err != nil {
+					fmt.Println(err)
+					os.Exit(1)
+				}
+				return
+			}
+
 			var (
 				jobs []trainer.TrainingJob
 			)
@@ -122,10 +138,20 @@ func TopCommand() *cobra.Command {
 	}
 
 	command.Flags().BoolVarP(&allNamespaces, "all-projects", "A", false, "show all projects.")
+	command.Flags().BoolVarP(&watch, "watch", "w", false, "Display a full-screen dashboard of the jobs, which is refreshed periodically")
+	command.Flags().DurationVar(&interval, "interval", 5*time.Second, "The refresh interval of --watch")
+	command.Flags().StringVar(&sortBy, "sort", jobsSortByUtilization, fmt.Sprintf("The initial order of --watch. One of: %s|%s|%s", jobsSortByUtilization, jobsSortByMemory, jobsSortByName))
 
 	return command
 }
 
+func validateTopJobsWatch(interval time.Duration, sortBy string) error {
+	if interval < time.Second {
+		return fmt.Errorf("--interval must be at least 1s")
+	}
+	return validateJobSort(sortBy)
+}
+
 func topTrainingJob(client *client.Client, jobInfoList []trainer.TrainingJob) {
 	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
 
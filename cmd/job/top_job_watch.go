@@ -0,0 +1,241 @@
+package job
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/run-ai/runai-cli/cmd/trainer"
+	"github.com/run-ai/runai-cli/pkg/client"
+	"github.com/run-ai/runai-cli/pkg/jobs"
+	prom "github.com/run-ai/runai-cli/pkg/prometheus"
+	"github.com/run-ai/runai-cli/pkg/types"
+	"github.com/run-ai/runai-cli/pkg/ui"
+	v1 "k8s.io/api/core/v1"
+)
+
+const (
+	jobsSortByUtilization = "utilization"
+	jobsSortByMemory      = "memory"
+	jobsSortByName        = "name"
+
+	// The title, the help, the status and the table header are shown above the rows
+	jobDashboardHeaderLines = 4
+)
+
+var (
+	jobSortTitles = map[string]string{
+		jobsSortByUtilization: "GPU utilization",
+		jobsSortByMemory:      "GPU memory usage",
+		jobsSortByName:        "name",
+	}
+
+	jobSortKeys = map[ui.Key]string{
+		"u": jobsSortByUtilization,
+		"m": jobsSortByMemory,
+		"n": jobsSortByName,
+	}
+
+	jobDashboardHeader = []string{"NAME", "PROJECT", "USER", "NODE", "DURATION", "GPUs", "GPU UTIL", "GPU MEMORY", "CPU", "MEMORY"}
+)
+
+// jobDashboardData is the running jobs, and why their metrics are missing if they are
+type jobDashboardData struct {
+	views        []types.JobView
+	metricsError error
+}
+
+type jobDashboard struct {
+	fetchJobViews func() (jobDashboardData, error)
+	interval      time.Duration
+
+	views        []types.JobView
+	lines        []string
+	sortBy       string
+	selection    ui.Selection
+	updated      time.Time
+	err          error
+	metricsError error
+	height       int
+}
+
+func newJobDashboard(fetchJobViews func() (jobDashboardData, error), sortBy string, interval time.Duration) *jobDashboard {
+	return &jobDashboard{
+		fetchJobViews: fetchJobViews,
+		sortBy:        sortBy,
+		interval:      interval,
+	}
+}
+
+func validateJobSort(sortBy string) error {
+	if _, found := jobSortTitles[sortBy]; !found {
+		return fmt.Errorf("invalid sort '%s', expected one of: %s, %s, %s", sortBy, jobsSortByUtilization, jobsSortByMemory, jobsSortByName)
+	}
+	return nil
+}
+
+func watchTopJobs(kubeClient *client.Client, namespaceInfo types.NamespaceInfo, sortBy string, interval time.Duration) error {
+	promClient, err := prom.BuildMetricsClient(kubeClient)
+	if err != nil {
+		return fmt.Errorf("error while creating prometheus client: %v", err)
+	}
+
+	// The jobs are shown even when their metrics are not available
+	fetchJobViews := func() (jobDashboardData, error) {
+		jobInfoList, err := trainer.GetAllJobs(kubeClient, namespaceInfo, []v1.PodPhase{v1.PodRunning})
+		if err != nil {
+			return jobDashboardData{}, err
+		}
+		views, metricsError := jobs.GetJobsMetrics(promClient, jobInfoList)
+		return jobDashboardData{views: views, metricsError: metricsError}, nil
+	}
+	return ui.RunDashboard(newJobDashboard(fetchJobViews, sortBy, interval), interval)
+}
+
+func (d *jobDashboard) Fetch() (interface{}, error) {
+	jobData, err := d.fetchJobViews()
+	if err != nil {
+		return nil, err
+	}
+	return jobData, nil
+}
+
+func (d *jobDashboard) Update(data interface{}, err error) {
+	d.updated = time.Now()
+	d.err = err
+	if err != nil {
+		return
+	}
+
+	// Keep the same job selected, even if the order of the jobs changed
+	selected := d.selectedJob()
+	jobData := data.(jobDashboardData)
+	d.views = jobData.views
+	d.metricsError = jobData.metricsError
+	d.sortViews(selected)
+}
+
+func (d *jobDashboard) HandleKey(key ui.Key) bool {
+	if key == ui.KeyQuit {
+		return false
+	}
+	if d.selection.HandleKey(key, len(d.views), d.pageSize()) {
+		return true
+	}
+	if sortBy, found := jobSortKeys[key]; found {
+		d.sortBy = sortBy
+		d.sortViews(d.selectedJob())
+	}
+	return true
+}
+
+func (d *jobDashboard) Render(width, height int) []string {
+	d.height = height
+
+	status := fmt.Sprintf("Refreshed every %v, last at %s", d.interval, d.updated.Format("15:04:05"))
+	if d.updated.IsZero() {
+		status = "Loading..."
+	}
+	if d.err != nil {
+		status = fmt.Sprintf("Failed to refresh: %v", d.err)
+	} else if d.metricsError != nil {
+		status = fmt.Sprintf("%s, metrics are not available: %v", status, d.metricsError)
+	}
+
+	screen := []string{
+		fmt.Sprintf("Running jobs: %d, sorted by %s", len(d.views), jobSortTitles[d.sortBy]),
+		"up/down: select, u/m/n: sort by utilization/memory/name, q: quit",
+		status,
+	}
+	if len(d.lines) == 0 {
+		return screen
+	}
+	return append(screen, ui.RenderList(d.lines[0], d.lines[1:], &d.selection, width, d.pageSize())...)
+}
+
+func (d *jobDashboard) pageSize() int {
+	return d.height - jobDashboardHeaderLines
+}
+
+func (d *jobDashboard) selectedJob() types.JobGeneralInfo {
+	if d.selection.Index < len(d.views) && d.views[d.selection.Index].Info != nil {
+		return *d.views[d.selection.Index].Info
+	}
+	return types.JobGeneralInfo{}
+}
+
+// sortViews sorts the jobs, formats their lines and selects the given job
+func (d *jobDashboard) sortViews(selected types.JobGeneralInfo) {
+	sortJobViews(d.views, d.sortBy)
+
+	rows := [][]string{jobDashboardHeader}
+	for i, view := range d.views {
+		rows = append(rows, jobViewToCells(view))
+		if view.Info != nil && view.Info.Name == selected.Name && view.Info.Project == selected.Project {
+			d.selection.Index = i
+		}
+	}
+	d.lines = ui.FormatColumns(rows)
+	d.selection.Fit(len(d.views), d.pageSize())
+}
+
+// sortJobViews sorts the busiest jobs first
+func sortJobViews(views []types.JobView, sortBy string) {
+	sort.SliceStable(views, func(i, j int) bool {
+		a, b := views[i], views[j]
+		switch sortBy {
+		case jobsSortByUtilization:
+			if jobGPUUtilization(a) != jobGPUUtilization(b) {
+				return jobGPUUtilization(a) > jobGPUUtilization(b)
+			}
+		case jobsSortByMemory:
+			if jobGPUMemoryUsage(a) != jobGPUMemoryUsage(b) {
+				return jobGPUMemoryUsage(a) > jobGPUMemoryUsage(b)
+			}
+		}
+		return jobSortName(a) < jobSortName(b)
+	})
+}
+
+func jobGPUUtilization(view types.JobView) float64 {
+	if view.GPUs == nil {
+		return 0
+	}
+	return view.GPUs.Utilization
+}
+
+func jobGPUMemoryUsage(view types.JobView) float64 {
+	if view.GPUMem == nil || view.GPUMem.Usage == nil {
+		return 0
+	}
+	return view.GPUMem.Usage.Usage
+}
+
+func jobSortName(view types.JobView) string {
+	if view.Info == nil {
+		return ""
+	}
+	return view.Info.Project + "/" + view.Info.Name
+}
+
+func jobViewToCells(view types.JobView) []string {
+	cells := []string{"-", "-", "-", "-", "-", "-", "-", "-", "-", "-"}
+	if view.Info != nil {
+		duration, _ := ui.TimeFormat(view.Info.Duration, nil)
+		cells[0], cells[1], cells[2], cells[3], cells[4] = view.Info.Name, view.Info.Project, view.Info.User, view.Info.Node, duration
+	}
+	if view.GPUs != nil {
+		cells[5] = fmt.Sprintf("%v", view.GPUs.Allocated)
+		cells[6], _ = ui.PrecantageFormat(view.GPUs.Utilization, nil)
+	}
+	if view.GPUMem != nil && view.GPUMem.Usage != nil {
+		cells[7], _ = usageFormatters["memoryusage"](*view.GPUMem.Usage, nil)
+	}
+	if view.CPUs != nil && view.CPUs.Usage != nil {
+		cells[8], _ = usageFormatters["cpuusage"](*view.CPUs.Usage, nil)
+	}
+	if view.Mem != nil && view.Mem.Usage != nil {
+		cells[9], _ = usageFormatters["memoryusage"](*view.Mem.Usage, nil)
+	}
+	return cells
+}
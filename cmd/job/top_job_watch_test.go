@@ -0,0 +1,43 @@
+package job
+
+import (
+	"testing"
+
+	"github.com/magiconair/properties/assert"
+	"github.com/run-ai/runai-cli/pkg/types"
+	"github.com/run-ai/runai-cli/pkg/ui"
+)
+
+func newTestJobView(name string, gpuUtilization, gpuMemoryUsage float64) types.JobView {
+	return types.JobView{
+		Info:   &types.JobGeneralInfo{Name: name, Project: "team"},
+		GPUs:   &types.GPUMetrics{Allocated: 1, Utilization: gpuUtilization},
+		GPUMem: &types.MemoryMetrics{Usage: &types.ResourceUsage{Usage: gpuMemoryUsage}},
+	}
+}
+
+func TestJobDashboardSort(t *testing.T) {
+	views := []types.JobView{newTestJobView("a", 10, 300), newTestJobView("b", 90, 100), {}}
+	dashboard := newJobDashboard(func() (jobDashboardData, error) {
+		return jobDashboardData{views: views}, nil
+	}, jobsSortByUtilization, 0)
+
+	data, err := dashboard.Fetch()
+	assert.Equal(t, err, nil)
+	dashboard.Update(data, nil)
+	assert.Equal(t, dashboard.views[0].Info.Name, "b")
+	assert.Equal(t, dashboard.views[2].Info, (*types.JobGeneralInfo)(nil))
+
+	// The selection follows the selected job when the order changes
+	dashboard.HandleKey("m")
+	assert.Equal(t, dashboard.views[0].Info.Name, "a")
+	assert.Equal(t, dashboard.views[1].Info.Name, "b")
+	assert.Equal(t, dashboard.selection.Index, 1)
+
+	dashboard.Render(120, 10)
+	dashboard.HandleKey(ui.KeyUp)
+	dashboard.HandleKey("u")
+	assert.Equal(t, dashboard.views[1].Info.Name, "a")
+	assert.Equal(t, dashboard.selection.Index, 1)
+	assert.Equal(t, dashboard.Render(120, 10)[0], "Running jobs: 3, sorted by GPU utilization")
+}
@@ -0,0 +1,311 @@
+package job
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/run-ai/runai-cli/cmd/constants"
+	"github.com/run-ai/runai-cli/cmd/flags"
+	"github.com/run-ai/runai-cli/cmd/trainer"
+	"github.com/run-ai/runai-cli/pkg/authentication/assertion"
+	"github.com/run-ai/runai-cli/pkg/client"
+	"github.com/run-ai/runai-cli/pkg/types"
+	commandUtil "github.com/run-ai/runai-cli/pkg/util/command"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+const (
+	waitForStatusPrefix = "status="
+
+	waitExample = `
+# Wait until a job succeeds or fails
+runai wait job JOB_NAME --for=status=Succeeded
+
+# Wait up to 10 minutes for a job to start running
+runai wait job JOB_NAME --for=status=Running --timeout=10m
+
+# Wait for all the jobs labeled with stage=preprocess to succeed
+runai wait job --all -l stage=preprocess --for=status=Succeeded
+`
+)
+
+// Exit codes of the wait command, so scripts can branch on the final status of the jobs
+const (
+	WaitExitCodeConditionMet = 0
+	WaitExitCodeError        = 1
+	WaitExitCodeTimeout      = 2
+	WaitExitCodeFailed       = 3
+	WaitExitCodeSucceeded    = 4
+	WaitExitCodePreempted    = 5
+	WaitExitCodeTimedOut     = 6
+	WaitExitCodeDeleted      = 7
+)
+
+var waitForStatuses = []string{constants.Status.Running, constants.Status.Succeeded, constants.Status.Failed}
+
+type waitArgs struct {
+	forCondition string
+	timeout      time.Duration
+	all          bool
+	selector     string
+}
+
+func WaitCommand() *cobra.Command {
+	args := waitArgs{}
+
+	var command = &cobra.Command{
+		Use:     "job [JOB_NAME]",
+		Aliases: []string{"jobs"},
+		Short:   "Wait until a job reaches a given status.",
+		Long: fmt.Sprintf(`Wait until a job reaches a given status.
+
+The command exits with one of the following codes:
+  %d  the condition was met
+  %d  an error occurred
+  %d  the timeout expired
+  %d  the job failed
+  %d  the job succeeded before the condition was met
+  %d  the job was preempted
+  %d  the job timed out
+  %d  the job was deleted`,
+			WaitExitCodeConditionMet, WaitExitCodeError, WaitExitCodeTimeout, WaitExitCodeFailed,
+			WaitExitCodeSucceeded, WaitExitCodePreempted, WaitExitCodeTimedOut, WaitExitCodeDeleted),
+		Example:           waitExample,
+		ValidArgsFunction: GenJobNames,
+		PreRun:            commandUtil.RoleAssertion(assertion.AssertViewerRole),
+		Run: func(cmd *cobra.Command, names []string) {
+			os.Exit(runWaitCommand(cmd, names, args))
+		},
+	}
+
+	command.Flags().StringVar(&args.forCondition, "for", waitForStatusPrefix+constants.Status.Succeeded, fmt.Sprintf("The condition to wait for, formatted as 'status=STATUS'. One of: %s", strings.Join(waitForStatuses, "|")))
+	command.Flags().DurationVar(&args.timeout, "timeout", 0, "The maximum time to wait (e.g. 30s, 10m, 2h). Zero means wait forever")
+	command.Flags().BoolVar(&args.all, "all", false, "Wait for all the jobs of the project")
+	command.Flags().StringVarP(&args.selector, "selector", "l", "", "Label selector to filter the jobs when using --all (e.g. key1=value1,key2=value2)")
+	command.RegisterFlagCompletionFunc("for", func(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+		conditions := []string{}
+		for _, status := range waitForStatuses {
+			conditions = append(conditions, waitForStatusPrefix+status)
+		}
+		return conditions, cobra.ShellCompDirectiveNoFileComp
+	})
+
+	return command
+}
+
+func runWaitCommand(cmd *cobra.Command, names []string, args waitArgs) int {
+	targetStatus, err := parseWaitCondition(args.forCondition)
+	if err != nil {
+		fmt.Println(err)
+		return WaitExitCodeError
+	}
+
+	if args.all == (len(names) > 0) {
+		fmt.Println("Please specify either job names or --all")
+		return WaitExitCodeError
+	}
+
+	selector, err := labels.Parse(args.selector)
+	if err != nil {
+		fmt.Println(err)
+		return WaitExitCodeError
+	}
+
+	kubeClient, err := client.GetClient()
+	if err != nil {
+		fmt.Println(err)
+		return WaitExitCodeError
+	}
+
+	namespaceInfo, err := flags.GetNamespaceToUseFromProjectFlag(cmd, kubeClient)
+	if err != nil {
+		fmt.Println(err)
+		return WaitExitCodeError
+	}
+
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+
+	// Start watching before the first check so no status change is missed in between
+	changes, err := trainer.WatchJobChanges(kubeClient, namespaceInfo.Namespace, stopCh)
+	if err != nil {
+		fmt.Println(err)
+		return WaitExitCodeError
+	}
+
+	if args.all {
+		names, err = listJobNamesBySelector(kubeClient, namespaceInfo, selector)
+		if err != nil {
+			fmt.Println(err)
+			return WaitExitCodeError
+		}
+		if len(names) == 0 {
+			fmt.Println("No jobs matched the given selector")
+			return WaitExitCodeError
+		}
+	} else {
+		// A job which does not exist from the start was never deleted, e.g. its name is mistyped
+		for _, name := range names {
+			if _, err = trainer.SearchTrainingJob(kubeClient, name, "", namespaceInfo); err != nil {
+				fmt.Println(err)
+				return WaitExitCodeError
+			}
+		}
+	}
+
+	var timeoutCh <-chan time.Time
+	if args.timeout > 0 {
+		timeoutCh = time.After(args.timeout)
+	}
+
+	pendingJobs := names
+	exitCode := WaitExitCodeConditionMet
+	for {
+		var stillPending []string
+		for _, name := range pendingJobs {
+			status, err := GetJobStatusByName(kubeClient, namespaceInfo, name)
+			if err != nil {
+				fmt.Println(err)
+				return WaitExitCodeError
+			}
+			reached, code := evaluateWaitStatus(status, targetStatus)
+			if reached {
+				fmt.Printf("Job %s reached status %s\n", name, status)
+				continue
+			}
+			if code != WaitExitCodeConditionMet {
+				fmt.Printf("Job %s ended with status %s before reaching status %s\n", name, status, targetStatus)
+				if exitCode == WaitExitCodeConditionMet {
+					exitCode = code
+				}
+				continue
+			}
+			stillPending = append(stillPending, name)
+		}
+
+		pendingJobs = stillPending
+		if len(pendingJobs) == 0 {
+			return exitCode
+		}
+
+		select {
+		case <-changes:
+		case <-timeoutCh:
+			fmt.Printf("Timed out waiting for job(s) %s to reach status %s\n", strings.Join(pendingJobs, ", "), targetStatus)
+			return WaitExitCodeTimeout
+		}
+	}
+}
+
+func parseWaitCondition(condition string) (string, error) {
+	if !strings.HasPrefix(condition, waitForStatusPrefix) {
+		return "", fmt.Errorf("unsupported condition '%s', expected the format 'status=STATUS'", condition)
+	}
+
+	status := normalizeJobStatus(strings.TrimPrefix(condition, waitForStatusPrefix))
+	for _, knownStatus := range waitForStatuses {
+		if status == knownStatus {
+			return status, nil
+		}
+	}
+	return "", fmt.Errorf("unsupported status '%s', one of: %s", status, strings.Join(waitForStatuses, "|"))
+}
+
+func listJobNamesBySelector(kubeClient *client.Client, namespaceInfo types.NamespaceInfo, selector labels.Selector) ([]string, error) {
+	jobs, err := trainer.GetAllJobs(kubeClient, namespaceInfo, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	names := []string{}
+	for _, job := range jobs {
+		if selector.Matches(labels.Set(job.Labels())) {
+			names = append(names, job.Name())
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// GetJobStatusByName returns the normalized status of the job, or Deleted if the job does not exist anymore. Any other
+// failure to get the job, e.g. of the API server, is returned as an error
+func GetJobStatusByName(kubeClient *client.Client, namespaceInfo types.NamespaceInfo, name string) (string, error) {
+	job, err := trainer.SearchTrainingJob(kubeClient, name, "", namespaceInfo)
+	if err == nil {
+		return normalizeJobStatus(GetJobRealStatus(job)), nil
+	}
+
+	// The search fails the same way whether the job is missing or the API server failed, so the job is deleted only
+	// if it is missing from the list of the jobs
+	log.Debugf("Failed to get job %s due to %v", name, err)
+	jobs, listErr := trainer.GetAllJobs(kubeClient, namespaceInfo, nil)
+	if listErr != nil {
+		return "", listErr
+	}
+	for _, job := range jobs {
+		if job.Name() == name {
+			return "", err
+		}
+	}
+
+	// A job which was just submitted has a configmap before it has a workload
+	_, err = kubeClient.GetClientset().CoreV1().ConfigMaps(namespaceInfo.Namespace).Get(name, metav1.GetOptions{})
+	if err == nil {
+		return constants.Status.Pending, nil
+	} else if !errors.IsNotFound(err) {
+		return "", err
+	}
+	return constants.Status.Deleted, nil
+}
+
+// evaluateWaitStatus returns whether the target status was reached, and if not whether the job is finished and with which exit code
+func evaluateWaitStatus(status string, targetStatus string) (bool, int) {
+	if status == targetStatus {
+		return true, WaitExitCodeConditionMet
+	}
+
+	// a finished job can still be considered as one that was running
+	if targetStatus == constants.Status.Running && status == constants.Status.Succeeded {
+		return true, WaitExitCodeConditionMet
+	}
+
+	switch status {
+	case constants.Status.Failed:
+		return false, WaitExitCodeFailed
+	case constants.Status.Succeeded:
+		return false, WaitExitCodeSucceeded
+	case constants.Status.Preempted:
+		return false, WaitExitCodePreempted
+	case constants.Status.TimedOut:
+		return false, WaitExitCodeTimedOut
+	case constants.Status.Deleted:
+		return false, WaitExitCodeDeleted
+	}
+	return false, WaitExitCodeConditionMet
+}
+
+// knownJobStatuses returns the statuses set by the scheduler
+func knownJobStatuses() []string {
+	return []string{
+		constants.Status.Running, constants.Status.Pending, constants.Status.Succeeded, constants.Status.Deleted,
+		constants.Status.Failed, constants.Status.TimedOut, constants.Status.Preempted, constants.Status.Suspended,
+		constants.Status.Unknown,
+	}
+}
+
+// normalizeJobStatus converts legacy upper case statuses (e.g. RUNNING) to the statuses set by the scheduler
+func normalizeJobStatus(status string) string {
+	for _, knownStatus := range knownJobStatuses() {
+		if strings.EqualFold(status, knownStatus) {
+			return knownStatus
+		}
+	}
+	return status
+}
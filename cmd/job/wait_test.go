@@ -0,0 +1,54 @@
+package job
+
+import (
+	"testing"
+
+	"github.com/magiconair/properties/assert"
+	"github.com/run-ai/runai-cli/cmd/constants"
+)
+
+func TestParseWaitCondition(t *testing.T) {
+	status, err := parseWaitCondition("status=running")
+
+	assert.Equal(t, err, nil)
+	assert.Equal(t, status, constants.Status.Running)
+}
+
+func TestParseWaitConditionUnknownStatus(t *testing.T) {
+	_, err := parseWaitCondition("status=Pending")
+
+	assert.Equal(t, err != nil, true)
+}
+
+func TestParseWaitConditionWrongFormat(t *testing.T) {
+	_, err := parseWaitCondition("Succeeded")
+
+	assert.Equal(t, err != nil, true)
+}
+
+func TestEvaluateWaitStatusReached(t *testing.T) {
+	reached, code := evaluateWaitStatus(normalizeJobStatus("RUNNING"), constants.Status.Running)
+
+	assert.Equal(t, reached, true)
+	assert.Equal(t, code, WaitExitCodeConditionMet)
+}
+
+func TestEvaluateWaitStatusStillPending(t *testing.T) {
+	reached, code := evaluateWaitStatus(constants.Status.Pending, constants.Status.Succeeded)
+
+	assert.Equal(t, reached, false)
+	assert.Equal(t, code, WaitExitCodeConditionMet)
+}
+
+func TestEvaluateWaitStatusFailedBeforeSucceeded(t *testing.T) {
+	reached, code := evaluateWaitStatus(constants.Status.Failed, constants.Status.Succeeded)
+
+	assert.Equal(t, reached, false)
+	assert.Equal(t, code, WaitExitCodeFailed)
+}
+
+func TestEvaluateWaitStatusSucceededCountsAsRunning(t *testing.T) {
+	reached, _ := evaluateWaitStatus(constants.Status.Succeeded, constants.Status.Running)
+
+	assert.Equal(t, reached, true)
+}
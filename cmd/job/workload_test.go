@@ -0,0 +1,14 @@
+package job
+
+import (
+	"github.com/run-ai/runai-cli/cmd/trainer"
+	"github.com/run-ai/runai-cli/pkg/types"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// newTestRunaiWorkload returns the runai workload of a job with the metadata of the job and of its pods
+func newTestRunaiWorkload(jobMetadata, podMetadata metav1.ObjectMeta, podSpec v1.PodSpec, trainingType, status string, pods ...v1.Pod) trainer.TrainingJob {
+	owner := types.Resource{Name: jobMetadata.Name, ResourceType: types.ResourceTypeRunaiJob, Uid: jobMetadata.Name + "-uid"}
+	return trainer.NewRunaiWorkload(append([]v1.Pod{}, pods...), nil, jobMetadata.CreationTimestamp, trainingType, jobMetadata.Name, true, []string{}, false, podSpec, podMetadata, jobMetadata, "runai-team", owner, status, 0, 1, 0, 0)
+}
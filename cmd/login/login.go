@@ -1,6 +1,7 @@
 package login
 
 import (
+	"fmt"
 	"github.com/run-ai/runai-cli/cmd/completion"
 	"github.com/run-ai/runai-cli/pkg/authentication"
 	"github.com/run-ai/runai-cli/pkg/authentication/types"
@@ -29,6 +30,8 @@ func NewLoginCommand() *cobra.Command {
 	command.Flags().StringVar(&params.IssuerURL, "idp-issuer-url", "", "issuer url")
 	command.Flags().StringVar(&params.ListenAddress, "redirect-server", "", "listen address")
 	command.Flags().StringVar(&params.User, "user", "", "user to log in")
+	command.Flags().StringVar(&params.AuthenticationFlow, "auth-flow", "", fmt.Sprintf("The way to log in: %s, %s (copy a code from a browser on another machine), %s (approve the login in a browser on another machine, for machines without a browser) or %s (user name and password)", types.CodePkceBrowser, types.CodePkceRemoteBrowser, types.DeviceCode, types.Auth0PasswordRealm))
+	command.Flags().BoolVar(&params.UseExecCredential, "exec-credential", false, "Keep the tokens in the cache of runai, and make kubectl get them from 'runai auth exec-credential', which refreshes them when they expire")
 	command.Flags().MarkHidden("client-id")
 	command.Flags().MarkHidden("idp-issuer-url")
 	command.Flags().MarkHidden("redirect-server")
@@ -3,19 +3,37 @@ package login
 import (
 	"fmt"
 	"github.com/run-ai/runai-cli/cmd/completion"
+	"github.com/run-ai/runai-cli/cmd/project"
 	"github.com/run-ai/runai-cli/pkg/authentication"
+	"github.com/run-ai/runai-cli/pkg/authentication/assertion"
+	"github.com/run-ai/runai-cli/pkg/authentication/jwt"
+	"github.com/run-ai/runai-cli/pkg/client"
+	"github.com/run-ai/runai-cli/pkg/ui"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
+	"io"
 	"os"
+	"sort"
 	"strings"
+	"text/tabwriter"
+	"time"
 )
 
 func NewWhoamiCommand() *cobra.Command {
+	var verbose bool
 	var command = &cobra.Command{
 		Use:   "whoami",
 		Short: "Current logged in user",
 		ValidArgsFunction: completion.NoArgs,
 		Run: func(cmd *cobra.Command, args []string) {
+			if verbose {
+				if err := printVerboseWhoami(); err != nil {
+					log.Error(err)
+					os.Exit(1)
+				}
+				return
+			}
+
 			subject, email, err := authentication.GetCurrentAuthenticateUserSubject()
 			if err != nil {
 				if errStr := err.Error(); strings.Contains(errStr, "authProvider.config does not exists") {
@@ -29,5 +47,102 @@ func NewWhoamiCommand() *cobra.Command {
 		},
 	}
 
+	command.Flags().BoolVarP(&verbose, "verbose", "v", false, "Show all the details of the login, and what the user may do in every project")
 	return command
-}
\ No newline at end of file
+}
+
+func printVerboseWhoami() error {
+	token, err := authentication.GetCurrentAuthenticateUserToken()
+	if err != nil {
+		if strings.Contains(err.Error(), "authProvider.config does not exists") {
+			return fmt.Errorf("You are currently not logged in to Run:AI")
+		}
+		return err
+	}
+
+	kubeClient, err := client.GetClient()
+	if err != nil {
+		return err
+	}
+	projectNamespaces, err := project.GetProjectNamespaces(kubeClient)
+	if err != nil {
+		return err
+	}
+	accesses := assertion.GetProjectsAccess(kubeClient.GetClientset(), projectNamespaces)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	printTokenClaims(w, token, time.Now())
+	fmt.Fprintln(w)
+	printProjectsAccess(w, accesses)
+	return w.Flush()
+}
+
+func printTokenClaims(w io.Writer, token jwt.Token, now time.Time) {
+	ui.Line(w, "User:", token.Email)
+	ui.Line(w, "Logged in Id:", token.Subject)
+	if token.Name != "" {
+		ui.Line(w, "Name:", token.Name)
+	}
+	if len(token.Groups) > 0 {
+		ui.Line(w, "Groups:", strings.Join(token.Groups, ", "))
+	}
+	if token.Uid != "" || token.Gid != "" {
+		ui.Line(w, "UID/GID:", fmt.Sprintf("%s/%s", valueOrNone(token.Uid), valueOrNone(token.Gid)))
+	}
+	if token.Issuer != "" {
+		ui.Line(w, "Issuer:", token.Issuer)
+	}
+	if issuedAt := token.IssuedAtTime(); !issuedAt.IsZero() {
+		ui.Line(w, "Issued at:", issuedAt.Format(time.RFC3339))
+	}
+	if expiry := token.ExpiresAt(); !expiry.IsZero() {
+		state := fmt.Sprintf("in %v", expiry.Sub(now).Round(time.Second))
+		if expiry.Before(now) {
+			state = "expired"
+		}
+		ui.Line(w, "Expires at:", fmt.Sprintf("%s (%s)", expiry.Format(time.RFC3339), state))
+	}
+}
+
+func printProjectsAccess(w io.Writer, accesses []*assertion.ProjectAccess) {
+	if len(accesses) == 0 {
+		ui.Line(w, "The user does not have access to any project")
+		return
+	}
+	sort.Slice(accesses, func(i, j int) bool { return accesses[i].Project < accesses[j].Project })
+
+	header := []string{"PROJECT"}
+	for _, permission := range assertion.ProjectPermissions {
+		header = append(header, strings.ToUpper(permission.Name))
+	}
+	ui.Line(w, header...)
+
+	for _, access := range accesses {
+		line := []string{access.Project}
+		for _, permission := range assertion.ProjectPermissions {
+			allowed, checked := access.Allowed[permission.Name]
+			switch {
+			case !checked:
+				line = append(line, "?")
+			case allowed:
+				line = append(line, "yes")
+			default:
+				line = append(line, "no")
+			}
+		}
+		ui.Line(w, line...)
+	}
+
+	for _, access := range accesses {
+		if access.Error != nil {
+			ui.Line(w, fmt.Sprintf("Failed to check the permissions in project %s: %v", access.Project, access.Error))
+		}
+	}
+}
+
+func valueOrNone(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
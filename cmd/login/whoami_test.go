@@ -0,0 +1,47 @@
+package login
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/run-ai/runai-cli/pkg/authentication/assertion"
+	"github.com/run-ai/runai-cli/pkg/authentication/jwt"
+	"gotest.tools/assert"
+)
+
+func TestPrintTokenClaims(t *testing.T) {
+	now := time.Date(2020, 10, 1, 12, 0, 0, 0, time.UTC)
+	token := jwt.Token{
+		Subject: "auth0|1234",
+		Email:   "john@example.com",
+		Uid:     "1000",
+		Groups:  []string{"researchers", "vision"},
+		Expiry:  now.Add(30 * time.Minute).Unix(),
+	}
+	out := &bytes.Buffer{}
+
+	printTokenClaims(out, token, now)
+
+	assert.Equal(t, out.String(), "User:\tjohn@example.com\n"+
+		"Logged in Id:\tauth0|1234\n"+
+		"Groups:\tresearchers, vision\n"+
+		"UID/GID:\t1000/-\n"+
+		"Expires at:\t2020-10-01T12:30:00Z (in 30m0s)\n")
+}
+
+func TestPrintProjectsAccess(t *testing.T) {
+	accesses := []*assertion.ProjectAccess{
+		{Project: "team-b", Allowed: map[string]bool{}, Error: errors.New("Access denied.")},
+		{Project: "team-a", Allowed: map[string]bool{"view": true, "submit": true, "delete": false, "exec": true, "logs": true}},
+	}
+	out := &bytes.Buffer{}
+
+	printProjectsAccess(out, accesses)
+
+	assert.Equal(t, out.String(), "PROJECT\tVIEW\tSUBMIT\tDELETE\tEXEC\tLOGS\n"+
+		"team-a\tyes\tyes\tno\tyes\tyes\n"+
+		"team-b\t?\t?\t?\t?\t?\n"+
+		"Failed to check the permissions in project team-b: Access denied.\n")
+}
@@ -21,6 +21,9 @@ import (
 	"os"
 	"time"
 
+	"golang.org/x/crypto/ssh/terminal"
+	v1 "k8s.io/api/core/v1"
+
 	"github.com/run-ai/runai-cli/cmd/trainer"
 	"github.com/run-ai/runai-cli/pkg/authentication/assertion"
 	commandUtil "github.com/run-ai/runai-cli/pkg/util/command"
@@ -28,6 +31,7 @@ import (
 	"github.com/run-ai/runai-cli/cmd/flags"
 	"github.com/run-ai/runai-cli/pkg/client"
 	"github.com/run-ai/runai-cli/pkg/podlogs"
+	"github.com/run-ai/runai-cli/pkg/types"
 	tlogs "github.com/run-ai/runai-cli/pkg/printer/base/logs"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
@@ -35,17 +39,36 @@ import (
 
 func NewLogsCommand() *cobra.Command {
 	var outerArgs = &podlogs.OuterRequestArgs{}
+	var allPods bool
+	var exportPath string
+	var selector job.JobSelector
 	var command = &cobra.Command{
 		Use:    "logs JOB_NAME",
 		Short:  "Print the logs of a job.",
 		ValidArgsFunction: job.GenJobNames,
 		PreRun: commandUtil.NamespacedRoleAssertion(assertion.AssertExecutorRole), // Viewing logs of a job is explicitly allowed to executors only
 		Run: func(cmd *cobra.Command, args []string) {
-			if len(args) == 0 {
+			if !selector.IsSet() && len(args) == 0 {
 				cmd.HelpFunc()(cmd, args)
 				os.Exit(1)
 			}
-			name := args[0]
+			if err := selector.CheckArgs(args); err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+			if selector.IsSet() && outerArgs.PodName != "" {
+				fmt.Println("the flag --pod cannot be used with a selector")
+				os.Exit(1)
+			}
+
+			if allPods && outerArgs.PodName != "" {
+				fmt.Println("the flags --all-pods and --pod cannot be used together")
+				os.Exit(1)
+			}
+			if exportPath != "" && (outerArgs.Follow || allPods) {
+				fmt.Println("the flag --export cannot be used with --follow or --all-pods, it exports the logs of all the pods")
+				os.Exit(1)
+			}
 
 			kubeClient, err := client.GetClient()
 			if err != nil {
@@ -67,15 +90,53 @@ func NewLogsCommand() *cobra.Command {
 				os.Exit(1)
 			}
 
+			outerArgs.Namespace = namespaceInfo.Namespace
+			outerArgs.RetryCount = 5
+			outerArgs.RetryTimeout = time.Millisecond
+
+			// The logs of the jobs matching a selector are always streamed from all their pods, as with --all-pods
+			if selector.IsSet() {
+				listPods := func() ([]v1.Pod, error) {
+					return listSelectedJobsPods(kubeClient, namespaceInfo, &selector)
+				}
+				if exportPath != "" {
+					pods, err := listPods()
+					if err != nil {
+						fmt.Println(err)
+						os.Exit(1)
+					}
+					exportJobLogs(pods, outerArgs, exportPath)
+					return
+				}
+				streamPodsLogs(outerArgs, listPods)
+				return
+			}
+
+			name := args[0]
 			// podName, err := getPodNameFromJob(printer.kubeClient, namespace, name)
 			job, err := trainer.SearchTrainingJob(kubeClient, name, "", namespaceInfo)
 			if err != nil {
 				fmt.Println(err)
 				os.Exit(1)
 			}
-			outerArgs.Namespace = namespaceInfo.Namespace
-			outerArgs.RetryCount = 5
-			outerArgs.RetryTimeout = time.Millisecond
+
+			if exportPath != "" {
+				exportJobLogs(job.AllPods(), outerArgs, exportPath)
+				return
+			}
+
+			if allPods {
+				listPods := func() ([]v1.Pod, error) {
+					job, err := trainer.SearchTrainingJob(kubeClient, name, "", namespaceInfo)
+					if err != nil {
+						return nil, err
+					}
+					return job.AllPods(), nil
+				}
+				streamPodsLogs(outerArgs, listPods)
+				return
+			}
+
 			names := []string{}
 			for _, pod := range job.AllPods() {
 				names = append(names, pod.Name)
@@ -112,9 +173,80 @@ func NewLogsCommand() *cobra.Command {
 
 	command.Flags().BoolVar(&outerArgs.Timestamps, "timestamps", false, "Include timestamps on each line in the log output.")
 
+	command.Flags().StringVar(&outerArgs.Grep, "grep", "", "Print only the log lines which match a regular expression.")
+	completion.AddFlagDescrpition(command, "grep", "Specify a regular expression")
+	command.Flags().IntVar(&outerArgs.GrepContext, "context", 0, "Print a number of lines of context before and after every line which matches --grep.")
+	completion.AddFlagDescrpition(command, "context", "Specify number of context lines")
+
+	command.Flags().StringVar(&exportPath, "export", "", "Save the logs of all the containers of all the pods of the job, with their timestamps and pod metadata, to a directory or to a .tar.gz file.")
+	completion.AddFlagDescrpition(command, "export", "Specify a directory or a .tar.gz file")
+
+	command.Flags().BoolVar(&allPods, "all-pods", false, "Stream the logs of all the pods of the job concurrently, each line prefixed with its pod. When following, the logs of new pods are streamed as they are created.")
+	command.Flags().StringVarP(&outerArgs.Container, "container", "c", "", "Print the logs of a specific container of the pods. By default --all-pods prints the logs of all the containers.")
+	command.RegisterFlagCompletionFunc("container", job.GenContainerNames)
+	command.Flags().BoolVar(&outerArgs.Previous, "previous", false, "Print the logs of the previous instance of the containers, e.g. of a container which crashed and restarted.")
+
 	// command.Flags().StringVar(&printer.pod, "instance", "", "Only return logs after a specific date (RFC3339). Defaults to all logs. Only one of since-time / since may be used.")
 
 	job.AddPodNameFlag(command ,&outerArgs.PodName)
+	selector.AddFlags(command, false)
 
 	return command
 }
+
+func streamPodsLogs(outerArgs *podlogs.OuterRequestArgs, listPods func() ([]v1.Pod, error)) {
+	multiPodLog, err := podlogs.NewMultiPodLog(outerArgs, listPods, os.Stdout)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	multiPodLog.Color = terminal.IsTerminal(int(os.Stdout.Fd()))
+	if err = multiPodLog.Stream(); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+}
+
+func listSelectedJobsPods(kubeClient *client.Client, namespaceInfo types.NamespaceInfo, selector *job.JobSelector) ([]v1.Pod, error) {
+	jobs, err := selector.SelectJobs(kubeClient, namespaceInfo)
+	if err != nil {
+		return nil, err
+	}
+	pods := []v1.Pod{}
+	for _, selectedJob := range jobs {
+		pods = append(pods, selectedJob.AllPods()...)
+	}
+	return pods, nil
+}
+
+func exportJobLogs(pods []v1.Pod, outerArgs *podlogs.OuterRequestArgs, exportPath string) {
+	if outerArgs.PodName != "" {
+		selectedPods := []v1.Pod{}
+		for _, pod := range pods {
+			if pod.Name == outerArgs.PodName {
+				selectedPods = append(selectedPods, pod)
+			}
+		}
+		pods = selectedPods
+	}
+	if len(pods) == 0 {
+		fmt.Println(tlogs.ErrPodNotFound)
+		os.Exit(1)
+	}
+
+	exporter, err := podlogs.NewLogExporter(outerArgs)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	exported, err := exporter.Export(pods, exportPath)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	if len(exported) == 0 {
+		fmt.Println("None of the containers of the job has logs yet")
+		os.Exit(1)
+	}
+	fmt.Printf("Exported the logs of %d containers to %s\n", len(exported), exportPath)
+}
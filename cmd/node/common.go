@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/run-ai/runai-cli/pkg/client"
+	"github.com/run-ai/runai-cli/pkg/helpers"
 	"github.com/run-ai/runai-cli/pkg/nodes"
 	"github.com/run-ai/runai-cli/pkg/types"
 	"github.com/run-ai/runai-cli/pkg/ui"
@@ -36,6 +37,34 @@ func GetNodeInfos(shouldQueryMetrics bool) (*[]nodes.NodeInfo, error) {
 	return &nodeInfos, nil
 }
 
+func nodeInfoToNodeView(nodeInfo nodes.NodeInfo) types.NodeView {
+	nodeResourcesConvertor := helpers.NodeResourcesStatusConvertor(nodeInfo.GetResourcesStatus())
+
+	return types.NodeView{
+		Info:   nodeInfo.GetGeneralInfo(),
+		CPUs:   nodeResourcesConvertor.ToCpus(),
+		Mem:    nodeResourcesConvertor.ToMemory(),
+		GPUs:   nodeResourcesConvertor.ToGpus(),
+		GPUMem: nodeResourcesConvertor.ToGpuMemory(),
+	}
+}
+
+func nodeInfosToNodeViews(nodeInfos *[]nodes.NodeInfo) []types.NodeView {
+	nodeViews := []types.NodeView{}
+	for _, nodeInfo := range *nodeInfos {
+		nodeViews = append(nodeViews, nodeInfoToNodeView(nodeInfo))
+	}
+	return nodeViews
+}
+
+func getNodeNames(nodeViews []types.NodeView) []string {
+	names := []string{}
+	for _, nodeView := range nodeViews {
+		names = append(names, nodeView.Info.Name)
+	}
+	return names
+}
+
 func handleSpecificNodes(nodeInfos *[]nodes.NodeInfo, displayFunction func(*[]nodes.NodeInfo), selectedNodeNames ...string) {
 	nodeNames := []string{}
 	matchsNodeInfos := []nodes.NodeInfo{}
@@ -2,12 +2,12 @@ package node
 
 import (
 	"fmt"
+	"github.com/run-ai/runai-cli/cmd/flags"
 	"github.com/run-ai/runai-cli/pkg/authentication/assertion"
 	commandUtil "github.com/run-ai/runai-cli/pkg/util/command"
 	"os"
 	"text/tabwriter"
 
-	"github.com/run-ai/runai-cli/pkg/helpers"
 	"github.com/run-ai/runai-cli/pkg/nodes"
 	"github.com/run-ai/runai-cli/pkg/types"
 	"github.com/run-ai/runai-cli/pkg/ui"
@@ -22,6 +22,9 @@ runai list node
 
 # Get list of specific nodes
 runai list node NODE_NAME_1 NODE_NAME_2
+
+# Get list of the nodes as yaml
+runai list node -o yaml
 `
 )
 
@@ -36,9 +39,26 @@ var (
 		"GPUs.Allocatable",
 		"GPUs.Free",
 	})
+	// the wide list adds the capacity of the nodes, and their allocated and unhealthy GPUs
+	showListNodeWideFields = ui.EnsureStringPaths(types.NodeView{}, []string{
+		"Info",
+		"CPUs.Capacity",
+		"CPUs.Allocatable",
+		"CPUs.Allocated",
+		"Mem.Capacity",
+		"Mem.Allocatable",
+		"Mem.Allocated",
+		"GPUs.GpuType",
+		"GPUs.Capacity",
+		"GPUs.Allocatable",
+		"GPUs.Allocated",
+		"GPUs.Free",
+		"GPUs.Unhealthy",
+	})
 )
 
 func ListCommand() *cobra.Command {
+	var output string
 
 	var command = &cobra.Command{
 		Use:     "nodes [...NODE_NAME]",
@@ -49,6 +69,11 @@ func ListCommand() *cobra.Command {
 		PreRun:  commandUtil.RoleAssertion(assertion.AssertViewerRole),
 		Run: func(cmd *cobra.Command, args []string) {
 
+			if err := ui.ValidateOutputFormat(output); err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+
 			nodeInfos, err := GetNodeInfos(false)
 
 			if err != nil {
@@ -56,39 +81,44 @@ func ListCommand() *cobra.Command {
 				os.Exit(1)
 			}
 
-			handleListSpecificNodes(nodeInfos, args...)
+			handleListSpecificNodes(nodeInfos, output, args...)
 
 		},
 	}
 
+	flags.AddOutputFlag(command, &output)
 	return command
 }
 
-func handleListSpecificNodes(nodeInfos *[]nodes.NodeInfo, selectedNodeNames ...string) {
-	handleSpecificNodes(nodeInfos, listNodes, selectedNodeNames...)
+func handleListSpecificNodes(nodeInfos *[]nodes.NodeInfo, output string, selectedNodeNames ...string) {
+	handleSpecificNodes(nodeInfos, func(nodeInfos *[]nodes.NodeInfo) {
+		listNodes(nodeInfos, output)
+	}, selectedNodeNames...)
 }
 
-func listNodes(nodeInfos *[]nodes.NodeInfo) {
-	nodeViews := []types.NodeView{}
-	for _, nodeInfo := range *nodeInfos {
+func listNodes(nodeInfos *[]nodes.NodeInfo, output string) {
+	nodeViews := nodeInfosToNodeViews(nodeInfos)
 
-		nodeResourcesConvertor := helpers.NodeResourcesStatusConvertor(nodeInfo.GetResourcesStatus())
-
-		nodeView := types.NodeView{
-			Info:   nodeInfo.GetGeneralInfo(),
-			CPUs:   nodeResourcesConvertor.ToCpus(),
-			Mem:    nodeResourcesConvertor.ToMemory(),
-			GPUs:   nodeResourcesConvertor.ToGpus(),
-			GPUMem: nodeResourcesConvertor.ToGpuMemory(),
+	switch output {
+	case ui.NameOutput:
+		ui.PrintNames(os.Stdout, getNodeNames(nodeViews))
+		return
+	case ui.JsonOutput, ui.YamlOutput:
+		if err := ui.PrintStructuredOutput(os.Stdout, output, nodeViews); err != nil {
+			fmt.Println(err)
 		}
+		return
+	}
 
-		nodeViews = append(nodeViews, nodeView)
+	showFields := showListNodeFields
+	if output == ui.WideOutput {
+		showFields = showListNodeWideFields
 	}
 
 	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
 
 	err := ui.CreateTable(types.NodeView{}, ui.TableOpt{
-		DisplayOpt: ui.DisplayOpt{Show: showListNodeFields},
+		DisplayOpt: ui.DisplayOpt{Show: showFields},
 	}).Render(w, nodeViews).Error()
 
 	ui.End(w)
@@ -16,11 +16,13 @@ package node
 
 import (
 	"fmt"
+	"github.com/run-ai/runai-cli/cmd/flags"
 	"github.com/run-ai/runai-cli/pkg/authentication/assertion"
 	commandUtil "github.com/run-ai/runai-cli/pkg/util/command"
 	"io"
 	"os"
 	"text/tabwriter"
+	"time"
 
 	"github.com/run-ai/runai-cli/pkg/helpers"
 	"github.com/run-ai/runai-cli/pkg/nodes"
@@ -30,8 +32,18 @@ import (
 	"github.com/spf13/cobra"
 )
 
+// topNodeView is the structured output of top node, the node view along with its GPUs
+type topNodeView struct {
+	types.NodeView `yaml:",inline"`
+	NodeGPUs       []types.GPU `json:"nodeGpus" yaml:"nodeGpus"`
+}
+
 var (
 	showDetails bool
+	output      string
+	watch       bool
+	interval    time.Duration
+	sortBy      string
 
 	commonTopNodeFields = ui.EnsureStringPaths(types.NodeView{}, []string{
 		"Info.Name",
@@ -74,28 +86,87 @@ func TopCommand() *cobra.Command {
 		PreRun:  commandUtil.RoleAssertion(assertion.AssertViewerRole),
 		Run: func(cmd *cobra.Command, args []string) {
 
+			if err := ui.ValidateOutputFormat(output); err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+
+			if watch {
+				if err := validateTopNodesWatch(args); err != nil {
+					fmt.Println(err)
+					os.Exit(1)
+				}
+				if err := watchTopNodes(sortBy, interval); err != nil {
+					fmt.Println(err)
+					os.Exit(1)
+				}
+				return
+			}
+
 			nodeInfos, err := GetNodeInfos(true)
 			if err != nil {
 				fmt.Println(err)
 				os.Exit(1)
 			}
 
-			handleTopSpecificNodes(nodeInfos, showDetails, args...)
+			handleTopSpecificNodes(nodeInfos, showDetails || output == ui.WideOutput, output, args...)
 		},
 	}
 
 	command.Flags().BoolVarP(&showDetails, "details", "d", false, "Display details")
+	command.Flags().BoolVarP(&watch, "watch", "w", false, "Display a full-screen dashboard of the nodes, which is refreshed periodically")
+	command.Flags().DurationVar(&interval, "interval", 5*time.Second, "The refresh interval of --watch")
+	command.Flags().StringVar(&sortBy, "sort", SortByUtilization, fmt.Sprintf("The initial order of --watch. One of: %s|%s|%s|%s", SortByUtilization, SortByMemory, SortByIdleTime, SortByName))
+	flags.AddOutputFlag(command, &output)
 	return command
 }
 
-func handleTopSpecificNodes(nodeInfos *[]nodes.NodeInfo, wide bool, selectedNodeNames ...string) {
+func validateTopNodesWatch(args []string) error {
+	if len(args) > 0 {
+		return fmt.Errorf("--watch shows all the nodes, select a node in the dashboard instead")
+	}
+	if output != ui.DefaultOutput {
+		return fmt.Errorf("--watch cannot be used with --output")
+	}
+	if interval < time.Second {
+		return fmt.Errorf("--interval must be at least 1s")
+	}
+	return validateNodeSort(sortBy)
+}
+
+func handleTopSpecificNodes(nodeInfos *[]nodes.NodeInfo, wide bool, output string, selectedNodeNames ...string) {
 
 	handleSpecificNodes(nodeInfos, func(nodeInfos *[]nodes.NodeInfo) {
-		displayTopNodes(nodeInfos, wide, len(selectedNodeNames) == 0)
+		switch output {
+		case ui.NameOutput:
+			ui.PrintNames(os.Stdout, getNodeNames(nodeInfosToNodeViews(nodeInfos)))
+		case ui.JsonOutput, ui.YamlOutput:
+			displayTopNodesStructured(nodeInfos, output)
+		default:
+			displayTopNodes(nodeInfos, wide, len(selectedNodeNames) == 0)
+		}
 	}, selectedNodeNames...)
 
 }
 
+func displayTopNodesStructured(nodeInfos *[]nodes.NodeInfo, output string) {
+	views := []topNodeView{}
+	for _, nodeInfo := range *nodeInfos {
+		nodeGPUs := nodeInfo.GetResourcesStatus().NodeGPUs
+		if nodeGPUs == nil {
+			nodeGPUs = []types.GPU{}
+		}
+		views = append(views, topNodeView{
+			NodeView: nodeInfoToNodeView(nodeInfo),
+			NodeGPUs: nodeGPUs,
+		})
+	}
+
+	if err := ui.PrintStructuredOutput(os.Stdout, output, views); err != nil {
+		fmt.Println(err)
+	}
+}
+
 func displayTopNodes(nodeInfos *[]nodes.NodeInfo, wide bool, showClusterData bool) {
 
 	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
@@ -0,0 +1,370 @@
+package node
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/run-ai/runai-cli/pkg/client"
+	"github.com/run-ai/runai-cli/pkg/helpers"
+	"github.com/run-ai/runai-cli/pkg/nodes"
+	"github.com/run-ai/runai-cli/pkg/types"
+	"github.com/run-ai/runai-cli/pkg/ui"
+	v1 "k8s.io/api/core/v1"
+)
+
+const (
+	SortByUtilization = "utilization"
+	SortByMemory      = "memory"
+	SortByIdleTime    = "idle"
+	SortByName        = "name"
+
+	// The title, the help, the status and the table header are shown above the rows
+	nodeDashboardHeaderLines = 4
+)
+
+var (
+	nodeSortTitles = map[string]string{
+		SortByUtilization: "GPU utilization",
+		SortByMemory:      "GPU memory usage",
+		SortByIdleTime:    "GPU idle time",
+		SortByName:        "name",
+	}
+
+	nodeSortKeys = map[ui.Key]string{
+		"u": SortByUtilization,
+		"m": SortByMemory,
+		"i": SortByIdleTime,
+		"n": SortByName,
+	}
+
+	nodeDashboardHeader = []string{"NAME", "STATUS", "GPUs", "GPU UTIL", "GPU MEMORY", "CPU UTIL", "MEMORY", "IDLE TIME"}
+)
+
+// nodeRow is a node of the dashboard. Its cells are computed when the data is fetched, so redrawing is cheap
+type nodeRow struct {
+	name           string
+	cells          []string
+	gpuUtilization float64
+	gpuMemoryUsage float64
+	// The shortest idle time of the GPUs of the node, which is how long the whole node has been idle
+	idleTime    float64
+	hasIdleTime bool
+	gpus        []types.GPU
+	pods        []v1.Pod
+}
+
+type nodeDashboard struct {
+	fetchNodeInfos func() ([]nodes.NodeInfo, error)
+	interval       time.Duration
+
+	rows      []nodeRow
+	lines     []string
+	sortBy    string
+	selection ui.Selection
+	updated   time.Time
+	err       error
+
+	// The name of the node whose details are shown, if any, and the scroll position of the details
+	detailsOf     string
+	detailsOffset int
+	height        int
+}
+
+func newNodeDashboard(fetchNodeInfos func() ([]nodes.NodeInfo, error), sortBy string, interval time.Duration) *nodeDashboard {
+	return &nodeDashboard{
+		fetchNodeInfos: fetchNodeInfos,
+		sortBy:         sortBy,
+		interval:       interval,
+	}
+}
+
+func validateNodeSort(sortBy string) error {
+	if _, found := nodeSortTitles[sortBy]; !found {
+		return fmt.Errorf("invalid sort '%s', expected one of: %s, %s, %s, %s", sortBy, SortByUtilization, SortByMemory, SortByIdleTime, SortByName)
+	}
+	return nil
+}
+
+func watchTopNodes(sortBy string, interval time.Duration) error {
+	kubeClient, err := client.GetClient()
+	if err != nil {
+		return err
+	}
+
+	fetchNodeInfos := func() ([]nodes.NodeInfo, error) {
+		// The warnings about missing metrics are not shown, the dashboard shows the nodes without them
+		nodeInfos, _, err := nodes.GetAllNodeInfos(kubeClient, true)
+		return nodeInfos, err
+	}
+	return ui.RunDashboard(newNodeDashboard(fetchNodeInfos, sortBy, interval), interval)
+}
+
+func (d *nodeDashboard) Fetch() (interface{}, error) {
+	nodeInfos, err := d.fetchNodeInfos()
+	if err != nil {
+		return nil, err
+	}
+	return nodeInfosToNodeRows(nodeInfos), nil
+}
+
+func (d *nodeDashboard) Update(data interface{}, err error) {
+	d.updated = time.Now()
+	d.err = err
+	if err != nil {
+		return
+	}
+
+	// Keep the same node selected, even if the order of the nodes changed
+	selectedName := d.selectedName()
+	d.rows = data.([]nodeRow)
+	d.sortRows(selectedName)
+}
+
+func (d *nodeDashboard) HandleKey(key ui.Key) bool {
+	if key == ui.KeyQuit {
+		return false
+	}
+
+	if d.detailsOf != "" {
+		switch key {
+		case ui.KeyBack, ui.KeyEnter:
+			d.detailsOf = ""
+		case ui.KeyUp:
+			d.detailsOffset--
+		case ui.KeyDown:
+			d.detailsOffset++
+		}
+		return true
+	}
+
+	if d.selection.HandleKey(key, len(d.rows), d.pageSize()) {
+		return true
+	}
+	if sortBy, found := nodeSortKeys[key]; found {
+		d.sortBy = sortBy
+		d.sortRows(d.selectedName())
+	} else if key == ui.KeyEnter && len(d.rows) > 0 {
+		d.detailsOf = d.rows[d.selection.Index].name
+		d.detailsOffset = 0
+	}
+	return true
+}
+
+func (d *nodeDashboard) Render(width, height int) []string {
+	d.height = height
+
+	status := fmt.Sprintf("Refreshed every %v, last at %s", d.interval, d.updated.Format("15:04:05"))
+	if d.updated.IsZero() {
+		status = "Loading..."
+	}
+	if d.err != nil {
+		status = fmt.Sprintf("Failed to refresh: %v", d.err)
+	}
+
+	if d.detailsOf != "" {
+		return d.renderDetails(status, height)
+	}
+
+	screen := []string{
+		fmt.Sprintf("Nodes: %d, sorted by %s", len(d.rows), nodeSortTitles[d.sortBy]),
+		"up/down: select, enter: details, u/m/i/n: sort by utilization/memory/idle time/name, q: quit",
+		status,
+	}
+	if len(d.lines) == 0 {
+		return screen
+	}
+
+	return append(screen, ui.RenderList(d.lines[0], d.lines[1:], &d.selection, width, d.pageSize())...)
+}
+
+func (d *nodeDashboard) renderDetails(status string, height int) []string {
+	var row *nodeRow
+	for i := range d.rows {
+		if d.rows[i].name == d.detailsOf {
+			row = &d.rows[i]
+		}
+	}
+
+	header := []string{
+		fmt.Sprintf("Node: %s", d.detailsOf),
+		"up/down: scroll, enter/esc: back, q: quit",
+		status,
+	}
+	if row == nil {
+		return append(header, "", "The node no longer exists")
+	}
+
+	details := []string{"", fmt.Sprintf("GPUs: %d", len(row.gpus))}
+	if len(row.gpus) > 0 {
+		details = append(details, ui.FormatColumns(gpusToRows(row.gpus))...)
+	}
+	details = append(details, "", fmt.Sprintf("Pods: %d", len(row.pods)))
+	if len(row.pods) > 0 {
+		details = append(details, ui.FormatColumns(podsToRows(row.pods))...)
+	}
+
+	maxOffset := len(details) - (height - len(header))
+	if d.detailsOffset > maxOffset {
+		d.detailsOffset = maxOffset
+	}
+	if d.detailsOffset < 0 {
+		d.detailsOffset = 0
+	}
+	return append(header, details[d.detailsOffset:]...)
+}
+
+func (d *nodeDashboard) pageSize() int {
+	return d.height - nodeDashboardHeaderLines
+}
+
+func (d *nodeDashboard) selectedName() string {
+	if d.selection.Index < len(d.rows) {
+		return d.rows[d.selection.Index].name
+	}
+	return ""
+}
+
+// sortRows sorts the rows, formats their lines and selects the named node
+func (d *nodeDashboard) sortRows(selectedName string) {
+	sortNodeRows(d.rows, d.sortBy)
+
+	rows := [][]string{nodeDashboardHeader}
+	for i, row := range d.rows {
+		rows = append(rows, row.cells)
+		if row.name == selectedName {
+			d.selection.Index = i
+		}
+	}
+	d.lines = ui.FormatColumns(rows)
+	d.selection.Fit(len(d.rows), d.pageSize())
+}
+
+// sortNodeRows sorts the busiest, or the longest idle, nodes first. Nodes without metrics come last
+func sortNodeRows(rows []nodeRow, sortBy string) {
+	sort.SliceStable(rows, func(i, j int) bool {
+		a, b := rows[i], rows[j]
+		switch sortBy {
+		case SortByUtilization:
+			if a.gpuUtilization != b.gpuUtilization {
+				return a.gpuUtilization > b.gpuUtilization
+			}
+		case SortByMemory:
+			if a.gpuMemoryUsage != b.gpuMemoryUsage {
+				return a.gpuMemoryUsage > b.gpuMemoryUsage
+			}
+		case SortByIdleTime:
+			if a.hasIdleTime != b.hasIdleTime {
+				return a.hasIdleTime
+			}
+			if a.idleTime != b.idleTime {
+				return a.idleTime > b.idleTime
+			}
+		}
+		return a.name < b.name
+	})
+}
+
+func nodeInfosToNodeRows(nodeInfos []nodes.NodeInfo) []nodeRow {
+	rows := make([]nodeRow, 0, len(nodeInfos))
+	for _, nodeInfo := range nodeInfos {
+		rows = append(rows, nodeInfoToNodeRow(nodeInfo))
+	}
+	return rows
+}
+
+func nodeInfoToNodeRow(nodeInfo nodes.NodeInfo) nodeRow {
+	resources := nodeInfo.GetResourcesStatus()
+	convertor := helpers.NodeResourcesStatusConvertor(resources)
+	info := nodeInfo.GetGeneralInfo()
+
+	row := nodeRow{
+		name: info.Name,
+		gpus: resources.NodeGPUs,
+		pods: nodeInfo.Pods,
+	}
+	sort.Slice(row.gpus, func(i, j int) bool { return row.gpus[i].IndexID < row.gpus[j].IndexID })
+
+	gpus, gpuUtilization, gpuMemory, cpuUtilization, memory, idleTime := "-", "-", "-", "-", "-", "-"
+	if nodeGPUs := convertor.ToGpus(); nodeGPUs != nil {
+		row.gpuUtilization = nodeGPUs.Utilization
+		gpus = fmt.Sprintf("%v/%d", nodeGPUs.Allocated, nodeGPUs.Capacity)
+		gpuUtilization = formatPercentage(nodeGPUs.Utilization)
+	}
+	if nodeGPUMemory := convertor.ToGpuMemory(); nodeGPUMemory != nil {
+		row.gpuMemoryUsage = nodeGPUMemory.Usage
+		gpuMemory = nodeGPUMemory.UsageAndUtilization
+	}
+	if nodeCPUs := convertor.ToCpus(); nodeCPUs != nil {
+		cpuUtilization = formatPercentage(nodeCPUs.Utilization)
+	}
+	if nodeMemory := convertor.ToMemory(); nodeMemory != nil {
+		memory = nodeMemory.UsageAndUtilization
+	}
+	for _, gpu := range row.gpus {
+		if !row.hasIdleTime || gpu.IdleTime < row.idleTime {
+			row.idleTime = gpu.IdleTime
+		}
+		row.hasIdleTime = true
+	}
+	if row.hasIdleTime {
+		idleTime = formatTime(row.idleTime)
+	}
+
+	row.cells = []string{info.Name, info.Status, gpus, gpuUtilization, gpuMemory, cpuUtilization, memory, idleTime}
+	return row
+}
+
+func gpusToRows(gpus []types.GPU) [][]string {
+	rows := [][]string{{"GPU", "ALLOCATED", "UTILIZATION", "MEMORY USAGE", "IDLE TIME"}}
+	for _, gpu := range gpus {
+		rows = append(rows, []string{
+			gpu.IndexID,
+			fmt.Sprintf("%v", gpu.Allocated),
+			formatPercentage(gpu.Utilization),
+			gpu.MemoryUsageAndUtilization,
+			formatTime(gpu.IdleTime),
+		})
+	}
+	return rows
+}
+
+func podsToRows(pods []v1.Pod) [][]string {
+	sorted := make([]v1.Pod, len(pods))
+	copy(sorted, pods)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Namespace != sorted[j].Namespace {
+			return sorted[i].Namespace < sorted[j].Namespace
+		}
+		return sorted[i].Name < sorted[j].Name
+	})
+
+	rows := [][]string{{"NAMESPACE", "NAME", "STATUS", "GPUs", "CPUs", "MEMORY"}}
+	for _, pod := range sorted {
+		status := helpers.GetPodResourceStatus(pod)
+		memory := "-"
+		if status.Requested.Memory > 0 {
+			memory = ui.ByteCountIEC(int64(status.Requested.Memory))
+		}
+		rows = append(rows, []string{
+			pod.Namespace,
+			pod.Name,
+			strings.ToUpper(string(pod.Status.Phase)),
+			fmt.Sprintf("%v", status.Allocated.GPUs),
+			fmt.Sprintf("%vm", status.Requested.CPUs),
+			memory,
+		})
+	}
+	return rows
+}
+
+func formatPercentage(value float64) string {
+	formatted, _ := ui.PrecantageFormat(value, nil)
+	return formatted
+}
+
+func formatTime(seconds float64) string {
+	formatted, _ := ui.TimeFormat(seconds, nil)
+	return formatted
+}
@@ -0,0 +1,101 @@
+package node
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/magiconair/properties/assert"
+	"github.com/run-ai/runai-cli/pkg/nodes"
+	"github.com/run-ai/runai-cli/pkg/ui"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func rowNames(rows []nodeRow) []string {
+	names := []string{}
+	for _, row := range rows {
+		names = append(names, row.name)
+	}
+	return names
+}
+
+func TestSortNodeRows(t *testing.T) {
+	rows := []nodeRow{
+		{name: "a", gpuUtilization: 10, gpuMemoryUsage: 300},
+		{name: "b", gpuUtilization: 90, gpuMemoryUsage: 100, idleTime: 5, hasIdleTime: true},
+		{name: "c", gpuUtilization: 10, gpuMemoryUsage: 200, idleTime: 500, hasIdleTime: true},
+	}
+
+	sortNodeRows(rows, SortByUtilization)
+	assert.Equal(t, rowNames(rows), []string{"b", "a", "c"})
+
+	sortNodeRows(rows, SortByMemory)
+	assert.Equal(t, rowNames(rows), []string{"a", "c", "b"})
+
+	// Nodes without GPU metrics have no idle time, and come last
+	sortNodeRows(rows, SortByIdleTime)
+	assert.Equal(t, rowNames(rows), []string{"c", "b", "a"})
+
+	sortNodeRows(rows, SortByName)
+	assert.Equal(t, rowNames(rows), []string{"a", "b", "c"})
+}
+
+func newTestNodeInfo(name string, podNames ...string) nodes.NodeInfo {
+	nodeInfo := nodes.NodeInfo{
+		Node: v1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Status: v1.NodeStatus{
+				Capacity: v1.ResourceList{
+					v1.ResourceCPU:    resource.MustParse("8"),
+					v1.ResourceMemory: resource.MustParse("16Gi"),
+				},
+				Conditions: []v1.NodeCondition{{Type: v1.NodeReady, Status: v1.ConditionTrue}},
+			},
+		},
+	}
+	for _, podName := range podNames {
+		nodeInfo.Pods = append(nodeInfo.Pods, v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: podName, Namespace: "runai-team"},
+			Status:     v1.PodStatus{Phase: v1.PodRunning},
+		})
+	}
+	return nodeInfo
+}
+
+func TestNodeDashboard(t *testing.T) {
+	nodeInfos := []nodes.NodeInfo{newTestNodeInfo("node-b", "job-1-0"), newTestNodeInfo("node-a")}
+	dashboard := newNodeDashboard(func() ([]nodes.NodeInfo, error) { return nodeInfos, nil }, SortByName, 0)
+
+	data, err := dashboard.Fetch()
+	assert.Equal(t, err, nil)
+	dashboard.Update(data, nil)
+
+	lines := dashboard.Render(120, 10)
+	assert.Equal(t, len(lines), 6)
+	assert.Equal(t, lines[0], "Nodes: 2, sorted by name")
+	assert.Equal(t, strings.Fields(lines[3])[0], "NAME")
+	assert.Equal(t, lines[4], ui.Highlight(dashboard.lines[1], 120))
+	assert.Equal(t, strings.Contains(lines[4], "node-a"), true)
+	assert.Equal(t, strings.Fields(lines[5])[0], "node-b")
+
+	// The selection stays on the same node when it moves
+	dashboard.HandleKey(ui.KeyDown)
+	nodeInfos = nodeInfos[:1]
+	data, _ = dashboard.Fetch()
+	dashboard.Update(data, nil)
+	lines = dashboard.Render(120, 10)
+	assert.Equal(t, len(lines), 5)
+	assert.Equal(t, lines[4], ui.Highlight(dashboard.lines[1], 120))
+	assert.Equal(t, strings.Contains(lines[4], "node-b"), true)
+
+	assert.Equal(t, dashboard.HandleKey(ui.KeyEnter), true)
+	details := strings.Join(dashboard.Render(120, 20), "\n")
+	assert.Equal(t, strings.Contains(details, "Node: node-b"), true)
+	assert.Equal(t, strings.Contains(details, "Pods: 1"), true)
+	assert.Equal(t, strings.Contains(details, "job-1-0"), true)
+
+	dashboard.HandleKey(ui.KeyBack)
+	assert.Equal(t, dashboard.Render(120, 10)[0], "Nodes: 1, sorted by name")
+	assert.Equal(t, dashboard.HandleKey(ui.KeyQuit), false)
+}
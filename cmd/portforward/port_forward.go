@@ -0,0 +1,86 @@
+package portforward
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/run-ai/runai-cli/cmd/exec"
+	"github.com/run-ai/runai-cli/cmd/job"
+	"github.com/run-ai/runai-cli/pkg/authentication/assertion"
+	"github.com/run-ai/runai-cli/pkg/client"
+	forward "github.com/run-ai/runai-cli/pkg/portforward"
+	commandUtil "github.com/run-ai/runai-cli/pkg/util/command"
+	"github.com/spf13/cobra"
+	v1 "k8s.io/api/core/v1"
+)
+
+const (
+	// podRunningTimeout is the time to wait for the pod of the job to run, before retrying to resolve it
+	podRunningTimeout = time.Minute
+
+	portForwardExamples = `
+# Forward local port 8888 to port 8888 of a job
+runai port-forward build1 8888
+
+# Forward local port 8080 to port 80 of a job, and any free local port to port 6006
+runai port-forward build1 8080:80 :6006
+`
+)
+
+func NewPortForwardCommand() *cobra.Command {
+	var podName string
+
+	var command = &cobra.Command{
+		Use:               "port-forward JOB_NAME [LOCAL_PORT:]REMOTE_PORT [...]",
+		Short:             "Forward local ports to the ports of a running job.",
+		Long:              "Forward local ports to the ports of a running job, through the API server. Ports which are in use are replaced with free ports. When the pod of the job is recreated, e.g. after it was preempted, the ports are forwarded to the new pod.",
+		Example:           portForwardExamples,
+		ValidArgsFunction: job.GenJobNames,
+		Args:              cobra.MinimumNArgs(2),
+		PreRun:            commandUtil.NamespacedRoleAssertion(assertion.AssertExecutorRole),
+		Run: commandUtil.WrapRunCommand(func(cmd *cobra.Command, args []string) error {
+			kubeClient, err := client.GetClient()
+			if err != nil {
+				return err
+			}
+
+			forwarder, err := NewJobForwarder(cmd, kubeClient, args[0], podName, args[1:])
+			if err != nil {
+				return err
+			}
+			mappings, err := forwarder.Listen()
+			if err != nil {
+				return err
+			}
+			PrintPortMappings(mappings)
+
+			return forwarder.Run(forward.StopOnInterrupt())
+		}),
+	}
+
+	job.AddPodNameFlag(command, &podName)
+
+	return command
+}
+
+// NewJobForwarder returns a forwarder of the ports of the pod of the job, given as [LOCAL:]REMOTE, which follows the
+// job to its new pod when the pod is recreated
+func NewJobForwarder(cmd *cobra.Command, kubeClient *client.Client, jobName, podName string, ports []string) (*forward.Forwarder, error) {
+	mappings, err := forward.ParsePortMappings(ports)
+	if err != nil {
+		return nil, err
+	}
+
+	resolvePod := func() (*v1.Pod, error) {
+		return exec.WaitForPodToStartRunning(cmd, kubeClient, jobName, podName, podRunningTimeout)
+	}
+	return forward.NewForwarder(kubeClient.GetRestConfig(), kubeClient.GetClientset(), resolvePod, mappings, os.Stdout), nil
+}
+
+func PrintPortMappings(mappings []forward.PortMapping) {
+	for _, mapping := range mappings {
+		fmt.Printf("Forwarding %s -> %d\n", mapping.URL(), mapping.Remote)
+	}
+	fmt.Println("Press Ctrl+C to stop forwarding")
+}
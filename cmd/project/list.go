@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"github.com/run-ai/runai-cli/cmd/completion"
 	"github.com/run-ai/runai-cli/cmd/constants"
+	"github.com/run-ai/runai-cli/cmd/flags"
 	"github.com/run-ai/runai-cli/pkg/authentication/assertion"
 	log "github.com/sirupsen/logrus"
 	"k8s.io/apimachinery/pkg/api/errors"
@@ -36,6 +37,9 @@ var (
 		Version:  "v1",
 		Resource: "projects",
 	}
+
+	// the table of the projects has all their fields, so there is no wide output
+	listProjectsOutputFormats = []string{ui.JsonOutput, ui.YamlOutput, ui.NameOutput}
 )
 
 type ProjectInfo struct {
@@ -48,6 +52,17 @@ type ProjectInfo struct {
 	department                  string
 }
 
+// ProjectView is the structured (json/yaml) output of a project
+type ProjectView struct {
+	Name                        string   `json:"name" yaml:"name"`
+	Department                  string   `json:"department" yaml:"department"`
+	DeservedGPUs                string   `json:"deservedGpus" yaml:"deservedGpus"`
+	Default                     bool     `json:"default" yaml:"default"`
+	InteractiveJobTimeLimitSecs int      `json:"interactiveJobTimeLimitSecs" yaml:"interactiveJobTimeLimitSecs"`
+	NodeAffinityInteractive     []string `json:"nodeAffinityInteractive" yaml:"nodeAffinityInteractive"`
+	NodeAffinityTraining        []string `json:"nodeAffinityTraining" yaml:"nodeAffinityTraining"`
+}
+
 // Required for backwards compatibility with clusters that don't have the Project resource yet.
 type Queue struct {
 	Spec struct {
@@ -76,6 +91,13 @@ type Project struct {
 }
 
 func runListCommand(cmd *cobra.Command, args []string) error {
+	return listProjectsWithOutput(ui.DefaultOutput)
+}
+
+func listProjectsWithOutput(output string) error {
+	if err := ui.ValidateOutputFormatOf(output, listProjectsOutputFormats); err != nil {
+		return err
+	}
 
 	projects, err := PrepareListOfProjects();
 	if err != nil {
@@ -84,10 +106,56 @@ func runListCommand(cmd *cobra.Command, args []string) error {
 
 	// Sort the projects, so they will always appear in the same order
 	projectsArray := getSortedProjects(projects)
-	printProjects(projectsArray)
+
+	switch output {
+	case ui.NameOutput:
+		names := []string{}
+		for _, info := range projectsArray {
+			names = append(names, info.name)
+		}
+		ui.PrintNames(os.Stdout, names)
+	case ui.JsonOutput, ui.YamlOutput:
+		views := []ProjectView{}
+		for _, info := range projectsArray {
+			views = append(views, info.toView())
+		}
+		return ui.PrintStructuredOutput(os.Stdout, output, views)
+	default:
+		printProjects(projectsArray)
+	}
 	return nil
 }
 
+// Name returns the name of the project
+func (info *ProjectInfo) Name() string {
+	return info.name
+}
+
+// Department returns the department which the project belongs to
+func (info *ProjectInfo) Department() string {
+	return info.department
+}
+
+func (info *ProjectInfo) toView() ProjectView {
+	interactiveJobTimeLimitSecs, _ := strconv.Atoi(info.interactiveJobTimeLimitSecs)
+	return ProjectView{
+		Name:                        info.name,
+		Department:                  info.department,
+		DeservedGPUs:                info.deservedGPUs,
+		Default:                     info.defaultProject,
+		InteractiveJobTimeLimitSecs: interactiveJobTimeLimitSecs,
+		NodeAffinityInteractive:     splitAffinity(info.nodeAffinityInteractive),
+		NodeAffinityTraining:        splitAffinity(info.nodeAffinityTraining),
+	}
+}
+
+func splitAffinity(affinity string) []string {
+	if affinity == "" {
+		return []string{}
+	}
+	return strings.Split(affinity, ",")
+}
+
 func PrepareListOfProjects() (map[string]*ProjectInfo, error) {
 	kubeClient, err := client.GetClient()
 	if err != nil {
@@ -243,6 +311,7 @@ func listCommandDEPRECATED() *cobra.Command {
 }
 
 func ListCommand() *cobra.Command {
+	var output string
 
 	var command = &cobra.Command{
 		Use:     "projects",
@@ -250,8 +319,37 @@ func ListCommand() *cobra.Command {
 		Short:   "List all available projects",
 		ValidArgsFunction: completion.NoArgs,
 		PreRun:  commandUtil.RoleAssertion(assertion.AssertViewerRole),
-		Run:     commandUtil.WrapRunCommand(runListCommand),
+		Run: commandUtil.WrapRunCommand(func(cmd *cobra.Command, args []string) error {
+			return listProjectsWithOutput(output)
+		}),
 	}
 
+	flags.AddOutputFlagWithFormats(command, &output, listProjectsOutputFormats)
 	return command
 }
+
+// GetProjectNamespaces returns the namespace of every project. The namespaces are found by the label of their project,
+// unless the user may not list namespaces, in which case they are named after the projects
+func GetProjectNamespaces(kubeClient *client.Client) (map[string]string, error) {
+	namespaceList, err := kubeClient.GetClientset().CoreV1().Namespaces().List(metav1.ListOptions{LabelSelector: constants.RunaiQueueLabel})
+	if err == nil {
+		projectNamespaces := map[string]string{}
+		for _, namespace := range namespaceList.Items {
+			projectNamespaces[namespace.Labels[constants.RunaiQueueLabel]] = namespace.Name
+		}
+		return projectNamespaces, nil
+	} else if !errors.IsForbidden(err) {
+		return nil, err
+	}
+	log.Debugf("Cannot list the namespaces of the projects: %v", err)
+
+	projects, err := PrepareListOfProjects()
+	if err != nil {
+		return nil, err
+	}
+	projectNamespaces := map[string]string{}
+	for name := range projects {
+		projectNamespaces[name] = constants.RunaiNsProjectPrefix + name
+	}
+	return projectNamespaces, nil
+}
@@ -0,0 +1,19 @@
+package report
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func NewReportCommand() *cobra.Command {
+	var command = &cobra.Command{
+		Use:   "report",
+		Short: "Display reports about the usage of the cluster.",
+		Run: func(cmd *cobra.Command, args []string) {
+			cmd.HelpFunc()(cmd, args)
+		},
+	}
+
+	command.AddCommand(NewUsageCommand())
+
+	return command
+}
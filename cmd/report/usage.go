@@ -0,0 +1,442 @@
+package report
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/run-ai/runai-cli/cmd/constants"
+	"github.com/run-ai/runai-cli/cmd/flags"
+	"github.com/run-ai/runai-cli/cmd/project"
+	"github.com/run-ai/runai-cli/cmd/trainer"
+	"github.com/run-ai/runai-cli/pkg/authentication/assertion"
+	"github.com/run-ai/runai-cli/pkg/client"
+	prom "github.com/run-ai/runai-cli/pkg/prometheus"
+	"github.com/run-ai/runai-cli/pkg/types"
+	"github.com/run-ai/runai-cli/pkg/ui"
+	commandUtil "github.com/run-ai/runai-cli/pkg/util/command"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	groupByProject    = "project"
+	groupByUser       = "user"
+	groupByDepartment = "department"
+	groupByNodeType   = "node-type"
+
+	csvOutput      = "csv"
+	dateLayout     = "2006-01-02"
+	defaultRateKey = "default"
+	unknownGroup   = "-"
+
+	nodeTypeLabel = "run.ai/type"
+	gpuTypeLabel  = "nvidia.com/gpu.product"
+
+	podGroupLabel  = "pod_group_uuid"
+	namespaceLabel = "namespace"
+	// The namespace of the pod group is part of the query, so the usage of jobs which were deleted since is
+	// attributed to their project
+	allocatedGpusRangeQuery = `sum(runai_allocated_gpus) by (pod_group_uuid, namespace)`
+	utilizedGpusRangeQuery  = `sum(runai_pod_group_gpu_utilization) by (pod_group_uuid, namespace) / 100`
+
+	usageExamples = `
+# GPU-hours of every project since the beginning of the month
+runai report usage
+
+# GPU-hours and cost of every department in September, as CSV
+runai report usage --from 2020-09-01 --to 2020-10-01 --group-by department --rate A100-SXM4-40GB=3.5,default=1.2 -o csv
+`
+)
+
+var (
+	groupByValues      = []string{groupByProject, groupByUser, groupByDepartment, groupByNodeType}
+	usageOutputFormats = []string{ui.JsonOutput, ui.YamlOutput, csvOutput}
+)
+
+type usageArgs struct {
+	from    string
+	to      string
+	groupBy string
+	step    time.Duration
+	rates   map[string]string
+	output  string
+}
+
+// jobAttribution holds what the usage of a job is grouped and priced by
+type jobAttribution struct {
+	project    string
+	user       string
+	department string
+	nodeType   string
+	gpuType    string
+}
+
+// usageAttributions attribute the usage of a pod group to its job, or to the project of its namespace when the job
+// no longer exists
+type usageAttributions struct {
+	jobs       map[string]jobAttribution
+	namespaces map[string]jobAttribution
+}
+
+// gpuRates are the hourly rates of a GPU by its type
+type gpuRates map[string]float64
+
+// UsageView is a row of the usage report, with the usage of the jobs of a group
+type UsageView struct {
+	Group            string  `json:"group" yaml:"group"`
+	Jobs             int     `json:"jobs" yaml:"jobs"`
+	GpuHours         float64 `json:"gpuHours" yaml:"gpuHours"`
+	UtilizedGpuHours float64 `json:"utilizedGpuHours" yaml:"utilizedGpuHours"`
+	Utilization      float64 `json:"utilization" yaml:"utilization"`
+	Cost             float64 `json:"cost" yaml:"cost"`
+}
+
+// UsageReport is the structured (json/yaml) output of the usage report
+type UsageReport struct {
+	From    time.Time   `json:"from" yaml:"from"`
+	To      time.Time   `json:"to" yaml:"to"`
+	GroupBy string      `json:"groupBy" yaml:"groupBy"`
+	Usage   []UsageView `json:"usage" yaml:"usage"`
+}
+
+func NewUsageCommand() *cobra.Command {
+	args := usageArgs{}
+
+	var command = &cobra.Command{
+		Use:   "usage",
+		Short: "Display the GPU-hours allocated to the jobs of the cluster, by project, user, department or node type.",
+		Long: `Display the GPU-hours allocated to the jobs of the cluster, by project, user, department or node type.
+The GPU-hours are the GPUs allocated to the jobs integrated over time, and the utilized GPU-hours are the GPUs weighted by their utilization.
+The usage of jobs which no longer exist in the cluster is attributed to their project and department, and reported under '-' by user and node type.`,
+		Example: usageExamples,
+		PreRun:  commandUtil.RoleAssertion(assertion.AssertViewerRole),
+		Run: commandUtil.WrapRunCommand(func(cmd *cobra.Command, _ []string) error {
+			return runUsageCommand(args)
+		}),
+	}
+
+	command.Flags().StringVar(&args.from, "from", "", "The beginning of the report, as a date (e.g. 2020-09-01) or a RFC3339 time. Defaults to the beginning of the month.")
+	command.Flags().StringVar(&args.to, "to", "", "The end of the report, as a date (e.g. 2020-10-01) or a RFC3339 time. Defaults to now.")
+	command.Flags().StringVar(&args.groupBy, "group-by", groupByProject, "Group the usage by one of: "+strings.Join(groupByValues, "|"))
+	command.Flags().DurationVar(&args.step, "step", 0, "The resolution of the usage, e.g. 5m. Defaults to the finest resolution which Prometheus allows for the time range.")
+	command.Flags().StringToStringVar(&args.rates, "rate", map[string]string{}, "The hourly rate of a GPU by GPU type, 'default' being the rate of the other types (e.g. A100-SXM4-40GB=3.5,default=1.2)")
+	command.Flags().StringVarP(&args.output, flags.OutputFlag, "o", ui.DefaultOutput, "Output format. One of: "+strings.Join(usageOutputFormats, "|"))
+
+	command.RegisterFlagCompletionFunc("group-by", func(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+		return groupByValues, cobra.ShellCompDirectiveNoFileComp
+	})
+	command.RegisterFlagCompletionFunc(flags.OutputFlag, func(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+		return usageOutputFormats, cobra.ShellCompDirectiveNoFileComp
+	})
+
+	return command
+}
+
+func runUsageCommand(args usageArgs) error {
+	if args.output != ui.DefaultOutput && !ui.Contains(usageOutputFormats, args.output) {
+		return fmt.Errorf("unknown output format: %s. One of: %s", args.output, strings.Join(usageOutputFormats, "|"))
+	}
+	if !ui.Contains(groupByValues, args.groupBy) {
+		return fmt.Errorf("unknown --group-by: %s. One of: %s", args.groupBy, strings.Join(groupByValues, "|"))
+	}
+
+	now := time.Now()
+	from, err := parseReportTime(args.from, beginningOfMonth(now))
+	if err != nil {
+		return fmt.Errorf("invalid --from: %v", err)
+	}
+	to, err := parseReportTime(args.to, now)
+	if err != nil {
+		return fmt.Errorf("invalid --to: %v", err)
+	}
+	if !from.Before(to) {
+		return fmt.Errorf("--from must be before --to")
+	}
+
+	rates, err := parseRates(args.rates)
+	if err != nil {
+		return err
+	}
+
+	kubeClient, err := client.GetClient()
+	if err != nil {
+		return err
+	}
+	promClient, err := prom.BuildMetricsClient(kubeClient)
+	if err != nil {
+		return err
+	}
+	if promClient == nil {
+		return fmt.Errorf("could not find Prometheus in the cluster, which the usage report is based on")
+	}
+
+	attributions, err := getUsageAttributions(kubeClient)
+	if err != nil {
+		return err
+	}
+
+	usage, err := queryUsage(promClient, from, to, prom.RangeStep(from, to, args.step), attributions, args.groupBy, rates)
+	if err != nil {
+		return err
+	}
+	report := UsageReport{
+		From:    from,
+		To:      to,
+		GroupBy: args.groupBy,
+		Usage:   usage,
+	}
+
+	switch args.output {
+	case ui.JsonOutput, ui.YamlOutput:
+		return ui.PrintStructuredOutput(os.Stdout, args.output, report)
+	case csvOutput:
+		return printUsageCSV(os.Stdout, report, len(rates) > 0)
+	default:
+		printUsageTable(os.Stdout, report, len(rates) > 0)
+	}
+	return nil
+}
+
+func beginningOfMonth(now time.Time) time.Time {
+	return time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+}
+
+// parseReportTime parses a date in the local time zone or a RFC3339 time
+func parseReportTime(value string, defaultTime time.Time) (time.Time, error) {
+	if value == "" {
+		return defaultTime, nil
+	}
+	if date, err := time.ParseInLocation(dateLayout, value, time.Local); err == nil {
+		return date, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
+func parseRates(rates map[string]string) (gpuRates, error) {
+	parsed := gpuRates{}
+	for gpuType, rate := range rates {
+		value, err := strconv.ParseFloat(rate, 64)
+		if err != nil || value < 0 {
+			return nil, fmt.Errorf("invalid --rate of %s: %s", gpuType, rate)
+		}
+		parsed[gpuType] = value
+	}
+	return parsed, nil
+}
+
+func (rates gpuRates) rate(gpuType string) float64 {
+	if rate, found := rates[gpuType]; found {
+		return rate
+	}
+	return rates[defaultRateKey]
+}
+
+// getUsageAttributions returns the project, user, department and node of the jobs of all the projects by their pod
+// group, and the project and department of the namespaces of the projects
+func getUsageAttributions(kubeClient *client.Client) (usageAttributions, error) {
+	attributions := usageAttributions{jobs: map[string]jobAttribution{}, namespaces: map[string]jobAttribution{}}
+	jobs, err := trainer.GetAllJobs(kubeClient, types.NamespaceInfo{Namespace: metav1.NamespaceAll, ProjectName: types.AllProjects}, nil)
+	if err != nil {
+		return attributions, err
+	}
+
+	projects, err := project.PrepareListOfProjects()
+	if err != nil {
+		return attributions, err
+	}
+	department := func(projectName string) string {
+		if projectInfo, found := projects[projectName]; found {
+			return projectInfo.Department()
+		}
+		return ""
+	}
+
+	namespaces, err := kubeClient.GetClientset().CoreV1().Namespaces().List(metav1.ListOptions{})
+	if err != nil {
+		log.Debugf("Failed to list the namespaces, the usage of deleted jobs will not be attributed to projects: %v", err)
+	} else {
+		for _, namespace := range namespaces.Items {
+			projectName := namespace.Labels[constants.RunaiQueueLabel]
+			if _, found := projects[strings.TrimPrefix(namespace.Name, constants.RunaiNsProjectPrefix)]; projectName == "" && found {
+				projectName = strings.TrimPrefix(namespace.Name, constants.RunaiNsProjectPrefix)
+			}
+			if projectName != "" {
+				attributions.namespaces[namespace.Name] = jobAttribution{project: projectName, department: department(projectName)}
+			}
+		}
+	}
+
+	nodeLabels := map[string]map[string]string{}
+	nodes, err := kubeClient.GetClientset().CoreV1().Nodes().List(metav1.ListOptions{})
+	if err != nil {
+		log.Debugf("Failed to list the nodes, the usage will not be attributed to node types: %v", err)
+	} else {
+		for _, node := range nodes.Items {
+			nodeLabels[node.Name] = node.Labels
+		}
+	}
+
+	for _, job := range jobs {
+		attribution := jobAttribution{project: job.Project(), user: job.User(), department: department(job.Project())}
+		if chiefPod := job.ChiefPod(); chiefPod != nil {
+			labels := nodeLabels[chiefPod.Spec.NodeName]
+			attribution.nodeType = labels[nodeTypeLabel]
+			attribution.gpuType = labels[gpuTypeLabel]
+		}
+		attributions.jobs[job.GetPodGroupUUID()] = attribution
+	}
+	return attributions, nil
+}
+
+// get returns the attribution of the pod group of a series of the usage queries
+func (attributions usageAttributions) get(metric map[string]string) jobAttribution {
+	if attribution, found := attributions.jobs[metric[podGroupLabel]]; found {
+		return attribution
+	}
+	return attributions.namespaces[metric[namespaceLabel]]
+}
+
+func (attribution jobAttribution) group(groupBy string) string {
+	var group string
+	switch groupBy {
+	case groupByProject:
+		group = attribution.project
+	case groupByUser:
+		group = attribution.user
+	case groupByDepartment:
+		group = attribution.department
+	case groupByNodeType:
+		group = attribution.nodeType
+	}
+	if group == "" {
+		return unknownGroup
+	}
+	return group
+}
+
+// queryUsage integrates the allocated and utilized GPUs of every pod group over time, and sums them by group
+func queryUsage(promClient prom.RangeQueryClient, from, to time.Time, step time.Duration, attributions usageAttributions, groupBy string, rates gpuRates) ([]UsageView, error) {
+	allocated, err := queryRangeMatrix(promClient, allocatedGpusRangeQuery, from, to, step)
+	if err != nil {
+		return nil, err
+	}
+	utilized, err := queryRangeMatrix(promClient, utilizedGpusRangeQuery, from, to, step)
+	if err != nil {
+		return nil, err
+	}
+
+	usageByGroup := map[string]*UsageView{}
+	getUsage := func(stream prom.SampleStream) (*UsageView, jobAttribution) {
+		attribution := attributions.get(stream.Metric)
+		group := attribution.group(groupBy)
+		usage, found := usageByGroup[group]
+		if !found {
+			usage = &UsageView{Group: group}
+			usageByGroup[group] = usage
+		}
+		return usage, attribution
+	}
+
+	for _, stream := range allocated {
+		usage, attribution := getUsage(stream)
+		gpuHours := prom.Integral(stream.Samples, step) / time.Hour.Seconds()
+		usage.Jobs++
+		usage.GpuHours += gpuHours
+		usage.Cost += gpuHours * rates.rate(attribution.gpuType)
+	}
+	for _, stream := range utilized {
+		usage, _ := getUsage(stream)
+		usage.UtilizedGpuHours += prom.Integral(stream.Samples, step) / time.Hour.Seconds()
+	}
+
+	usages := []UsageView{}
+	for _, usage := range usageByGroup {
+		if usage.GpuHours > 0 {
+			usage.Utilization = usage.UtilizedGpuHours / usage.GpuHours * 100
+		}
+		usages = append(usages, *usage)
+	}
+	sort.Slice(usages, func(i, j int) bool { return usages[i].Group < usages[j].Group })
+	return usages, nil
+}
+
+func queryRangeMatrix(promClient prom.RangeQueryClient, query string, from, to time.Time, step time.Duration) ([]prom.SampleStream, error) {
+	data, err := promClient.QueryRange(query, from, to, step)
+	if err != nil {
+		return nil, err
+	}
+	return data.Matrix()
+}
+
+func usageHeaders(report UsageReport, withCost bool) []string {
+	headers := []string{strings.ToUpper(report.GroupBy), "JOBS", "GPU HOURS", "UTILIZED GPU HOURS", "UTILIZATION"}
+	if withCost {
+		headers = append(headers, "COST")
+	}
+	return headers
+}
+
+func usageRow(usage UsageView, withCost bool) []string {
+	row := []string{
+		usage.Group,
+		strconv.Itoa(usage.Jobs),
+		fmt.Sprintf("%.2f", usage.GpuHours),
+		fmt.Sprintf("%.2f", usage.UtilizedGpuHours),
+		fmt.Sprintf("%.0f%%", usage.Utilization),
+	}
+	if withCost {
+		row = append(row, fmt.Sprintf("%.2f", usage.Cost))
+	}
+	return row
+}
+
+func printUsageTable(out io.Writer, report UsageReport, withCost bool) {
+	fmt.Fprintf(out, "GPU usage from %s to %s\n\n", report.From.Format(time.RFC3339), report.To.Format(time.RFC3339))
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	ui.Line(w, usageHeaders(report, withCost)...)
+
+	total := UsageView{Group: "TOTAL"}
+	for _, usage := range report.Usage {
+		ui.Line(w, usageRow(usage, withCost)...)
+		total.Jobs += usage.Jobs
+		total.GpuHours += usage.GpuHours
+		total.UtilizedGpuHours += usage.UtilizedGpuHours
+		total.Cost += usage.Cost
+	}
+	if total.GpuHours > 0 {
+		total.Utilization = total.UtilizedGpuHours / total.GpuHours * 100
+	}
+	ui.Line(w, usageRow(total, withCost)...)
+
+	_ = w.Flush()
+}
+
+func printUsageCSV(out io.Writer, report UsageReport, withCost bool) error {
+	w := csv.NewWriter(out)
+	headers := usageHeaders(report, withCost)
+	for i, header := range headers {
+		headers[i] = strings.Replace(strings.ToLower(header), " ", "_", -1)
+	}
+	if err := w.Write(headers); err != nil {
+		return err
+	}
+	for _, usage := range report.Usage {
+		row := usageRow(usage, withCost)
+		// Keep the utilization numeric for spreadsheets
+		row[4] = fmt.Sprintf("%.2f", usage.Utilization)
+		if err := w.Write(row); err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
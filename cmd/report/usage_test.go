@@ -0,0 +1,159 @@
+package report
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/magiconair/properties/assert"
+	prom "github.com/run-ai/runai-cli/pkg/prometheus"
+	"github.com/run-ai/runai-cli/pkg/util"
+)
+
+var testFrom = time.Date(2020, 9, 1, 0, 0, 0, 0, time.UTC)
+
+func newTestSeries(podGroup string, step time.Duration, values ...float64) prom.MetricResult {
+	return newTestNamespaceSeries(podGroup, "", step, values...)
+}
+
+func newTestNamespaceSeries(podGroup, namespace string, step time.Duration, values ...float64) prom.MetricResult {
+	return util.FakeMatrixResult(map[string]string{podGroupLabel: podGroup, namespaceLabel: namespace}, testFrom, step, values...)
+}
+
+func newTestMatrix(series ...prom.MetricResult) *prom.MetricData {
+	return &prom.MetricData{ResultType: prom.MatrixResultType, Result: series}
+}
+
+var testAttributions = usageAttributions{
+	jobs: map[string]jobAttribution{
+		"uuid-1": {project: "team-a", user: "john", department: "research", nodeType: "dgx", gpuType: "A100"},
+		"uuid-2": {project: "team-a", user: "jane", department: "research", gpuType: "V100"},
+		"uuid-3": {project: "team-b", user: "john", department: "product", gpuType: "V100"},
+	},
+	namespaces: map[string]jobAttribution{
+		"runai-team-a": {project: "team-a", department: "research"},
+		"runai-team-b": {project: "team-b", department: "product"},
+	},
+}
+
+func TestBuildUsageByProject(t *testing.T) {
+	promClient := util.FakePrometheusRangeClient(map[string]*prom.MetricData{
+		allocatedGpusRangeQuery: newTestMatrix(
+			newTestSeries("uuid-1", time.Hour, 2, 2),
+			newTestSeries("uuid-2", time.Hour, 1, 1, 1),
+			newTestSeries("uuid-3", time.Hour, 0.5, 0.5),
+			newTestSeries("uuid-deleted", time.Hour, 1),
+		),
+		utilizedGpusRangeQuery: newTestMatrix(
+			newTestSeries("uuid-1", time.Hour, 1, 1),
+			newTestSeries("uuid-3", time.Hour, 0.5, math.NaN()),
+		),
+	}, nil)
+
+	usage, err := queryUsage(promClient, testFrom, testFrom.Add(3*time.Hour), time.Hour, testAttributions, groupByProject, gpuRates{"A100": 3, defaultRateKey: 1})
+	assert.Equal(t, err, nil)
+	teamAUtilizedGpuHours, teamAGpuHours := 2.0, 7.0
+
+	assert.Equal(t, usage, []UsageView{
+		{Group: unknownGroup, Jobs: 1, GpuHours: 1, Cost: 1},
+		{Group: "team-a", Jobs: 2, GpuHours: 7, UtilizedGpuHours: 2, Utilization: teamAUtilizedGpuHours / teamAGpuHours * 100, Cost: 15},
+		{Group: "team-b", Jobs: 1, GpuHours: 1, UtilizedGpuHours: 0.5, Utilization: 50, Cost: 1},
+	})
+}
+
+func TestBuildUsageOfDeletedJobs(t *testing.T) {
+	promClient := util.FakePrometheusRangeClient(map[string]*prom.MetricData{
+		allocatedGpusRangeQuery: newTestMatrix(
+			newTestNamespaceSeries("uuid-1", "runai-team-a", time.Hour, 1),
+			newTestNamespaceSeries("uuid-deleted-1", "runai-team-a", time.Hour, 2),
+			newTestNamespaceSeries("uuid-deleted-2", "runai-team-b", time.Hour, 4),
+			newTestNamespaceSeries("uuid-deleted-3", "default", time.Hour, 8),
+		),
+	}, nil)
+	usage := func(groupBy string) []UsageView {
+		usage, err := queryUsage(promClient, testFrom, testFrom.Add(time.Hour), time.Hour, testAttributions, groupBy, gpuRates{})
+		assert.Equal(t, err, nil)
+		return usage
+	}
+
+	// The deleted jobs are attributed to the project and department of their namespace
+	assert.Equal(t, usage(groupByProject), []UsageView{
+		{Group: unknownGroup, Jobs: 1, GpuHours: 8},
+		{Group: "team-a", Jobs: 2, GpuHours: 3},
+		{Group: "team-b", Jobs: 1, GpuHours: 4},
+	})
+	assert.Equal(t, usage(groupByDepartment), []UsageView{
+		{Group: unknownGroup, Jobs: 1, GpuHours: 8},
+		{Group: "product", Jobs: 1, GpuHours: 4},
+		{Group: "research", Jobs: 2, GpuHours: 3},
+	})
+	assert.Equal(t, usage(groupByUser), []UsageView{
+		{Group: unknownGroup, Jobs: 3, GpuHours: 14},
+		{Group: "john", Jobs: 1, GpuHours: 1},
+	})
+}
+
+func TestBuildUsageByOtherGroups(t *testing.T) {
+	promClient := util.FakePrometheusRangeClient(map[string]*prom.MetricData{
+		allocatedGpusRangeQuery: newTestMatrix(
+			newTestSeries("uuid-1", 30*time.Minute, 1, 1),
+			newTestSeries("uuid-2", 30*time.Minute, 1),
+			newTestSeries("uuid-3", 30*time.Minute, 1),
+		),
+	}, nil)
+	groups := func(groupBy string) []string {
+		usage, err := queryUsage(promClient, testFrom, testFrom.Add(time.Hour), 30*time.Minute, testAttributions, groupBy, gpuRates{})
+		assert.Equal(t, err, nil)
+		names := []string{}
+		for _, view := range usage {
+			names = append(names, view.Group)
+		}
+		return names
+	}
+
+	assert.Equal(t, groups(groupByUser), []string{"jane", "john"})
+	assert.Equal(t, groups(groupByDepartment), []string{"product", "research"})
+	assert.Equal(t, groups(groupByNodeType), []string{unknownGroup, "dgx"})
+}
+
+func TestQueryUsageFailure(t *testing.T) {
+	promClient := util.FakePrometheusRangeClient(nil, fmt.Errorf("prometheus is down"))
+
+	_, err := queryUsage(promClient, testFrom, testFrom.Add(time.Hour), time.Minute, testAttributions, groupByProject, gpuRates{})
+	assert.Equal(t, err.Error(), "prometheus is down")
+}
+
+func TestParseReportTime(t *testing.T) {
+	date, err := parseReportTime("2020-09-01", time.Time{})
+	assert.Equal(t, err, nil)
+	assert.Equal(t, date, time.Date(2020, 9, 1, 0, 0, 0, 0, time.Local))
+
+	date, err = parseReportTime("2020-09-01T12:00:00Z", time.Time{})
+	assert.Equal(t, err, nil)
+	assert.Equal(t, date.Equal(time.Date(2020, 9, 1, 12, 0, 0, 0, time.UTC)), true)
+
+	_, err = parseReportTime("yesterday", time.Time{})
+	assert.Equal(t, err != nil, true)
+}
+
+func TestParseRates(t *testing.T) {
+	rates, err := parseRates(map[string]string{"A100": "3.5", defaultRateKey: "1"})
+	assert.Equal(t, err, nil)
+	assert.Equal(t, rates.rate("A100"), 3.5)
+	assert.Equal(t, rates.rate("V100"), 1.0)
+
+	_, err = parseRates(map[string]string{"A100": "free"})
+	assert.Equal(t, err != nil, true)
+}
+
+func TestPrintUsageCSV(t *testing.T) {
+	report := UsageReport{GroupBy: groupByNodeType, Usage: []UsageView{
+		{Group: "dgx", Jobs: 2, GpuHours: 10, UtilizedGpuHours: 2.5, Utilization: 25, Cost: 30},
+	}}
+	out := &bytes.Buffer{}
+
+	assert.Equal(t, printUsageCSV(out, report, true), nil)
+	assert.Equal(t, out.String(), "node-type,jobs,gpu_hours,utilized_gpu_hours,utilization,cost\ndgx,2,10.00,2.50,25.00,30.00\n")
+}
@@ -21,6 +21,7 @@ func NewDescribeCommand() *cobra.Command {
 
 	command.AddCommand(node.DescribeCommand())
 	command.AddCommand(job.DescribeCommand())
+	command.AddCommand(job.DescribePipelineCommand())
 	command.AddCommand(template.DescribeCommand())
 
 	return command
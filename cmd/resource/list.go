@@ -2,6 +2,7 @@ package resource
 
 import (
 	"github.com/run-ai/runai-cli/cmd/cluster"
+	"github.com/run-ai/runai-cli/cmd/flags"
 	"github.com/run-ai/runai-cli/cmd/job"
 	"github.com/run-ai/runai-cli/cmd/node"
 	"github.com/run-ai/runai-cli/cmd/project"
@@ -19,6 +20,15 @@ runai list jobs
 # Get list of jobs from all projects
 runai list jobs -A
 
+# Get list of the jobs as json
+runai list jobs -o json
+
+# Get list of the hyperparameter sweeps and the status of their trials
+runai list sweeps
+
+# Get list of the jobs whose GPUs have been idle for more than 4 hours
+runai list idle --idle-time 4h
+
 # Get list of the nodes
 runai list nodes
 
@@ -35,6 +45,7 @@ runai list templates
 
 func NewListCommand() *cobra.Command {
 	var allNamespaces bool
+	var output string
 
 	var command = &cobra.Command{
 		Use:     "list",
@@ -42,15 +53,18 @@ func NewListCommand() *cobra.Command {
 		Example: listExample,
 		PreRun:  commandUtil.RoleAssertion(assertion.AssertViewerRole),
 		Run: func(cmd *cobra.Command, args []string) {
-			job.RunJobList(cmd, args, allNamespaces)
+			job.RunJobList(cmd, args, allNamespaces, output)
 		},
 	}
 
 	command.Flags().BoolVarP(&allNamespaces, "all-projects", "A", false, "list jobs from all projects")
+	flags.AddOutputFlag(command, &output)
 
 	// create subcommands
 	command.AddCommand(node.ListCommand())
 	command.AddCommand(job.ListCommand())
+	command.AddCommand(job.ListSweepsCommand())
+	command.AddCommand(job.ListIdleCommand())
 	command.AddCommand(project.ListCommand())
 	command.AddCommand(cluster.ListCommand())
 	command.AddCommand(template.ListCommand())
@@ -0,0 +1,20 @@
+package resource
+
+import (
+	"github.com/run-ai/runai-cli/cmd/job"
+	"github.com/spf13/cobra"
+)
+
+func NewWaitCommand() *cobra.Command {
+	var command = &cobra.Command{
+		Use:   "wait",
+		Short: "Wait for resources to reach a given state.",
+		Run: func(cmd *cobra.Command, args []string) {
+			cmd.HelpFunc()(cmd, args)
+		},
+	}
+
+	command.AddCommand(job.WaitCommand())
+
+	return command
+}
@@ -15,6 +15,7 @@
 package root
 
 import (
+	"github.com/run-ai/runai-cli/cmd/auth"
 	"github.com/run-ai/runai-cli/cmd/cluster"
 	"github.com/run-ai/runai-cli/cmd/completion"
 	"github.com/run-ai/runai-cli/cmd/flags"
@@ -28,8 +29,12 @@ import (
 	"github.com/run-ai/runai-cli/cmd/global"
 	deleteJob "github.com/run-ai/runai-cli/cmd/job/delete"
 	submitJob "github.com/run-ai/runai-cli/cmd/job/submit"
+	"github.com/run-ai/runai-cli/cmd/job/suspend"
 	"github.com/run-ai/runai-cli/cmd/logs"
+	"github.com/run-ai/runai-cli/cmd/portforward"
 	"github.com/run-ai/runai-cli/cmd/project"
+	"github.com/run-ai/runai-cli/cmd/report"
+	"github.com/run-ai/runai-cli/cmd/ssh"
 	"github.com/run-ai/runai-cli/cmd/template"
 	"github.com/run-ai/runai-cli/pkg/config"
 	"github.com/run-ai/runai-cli/pkg/util"
@@ -64,24 +69,38 @@ func NewCommand() *cobra.Command {
 
 	command.AddCommand(submitJob.NewRunaiJobCommand())
 	command.AddCommand(submitJob.NewRunaiSubmitMPIJobCommand())
+	command.AddCommand(submitJob.NewSubmitPipelineCommand())
+	command.AddCommand(submitJob.NewApplyCommand())
+	command.AddCommand(submitJob.NewResubmitCommand())
 	command.AddCommand(resource.NewListCommand())
 	command.AddCommand(logs.NewLogsCommand())
 	command.AddCommand(deleteJob.NewDeleteCommand())
+	command.AddCommand(suspend.NewSuspendCommand())
+	command.AddCommand(suspend.NewResumeCommand())
 	command.AddCommand(resource.GetCommand())
 	command.AddCommand(resource.NewTopCommand())
 	command.AddCommand(resource.NewDescribeCommand())
+	command.AddCommand(resource.NewWaitCommand())
+	command.AddCommand(report.NewReportCommand())
 	command.AddCommand(resource.ConfigCommand())
 	command.AddCommand(raCmd.NewVersionCmd())
 	command.AddCommand(raCmd.NewUpdateCommand())
 	command.AddCommand(exec.NewBashCommand())
 	command.AddCommand(exec.NewExecCommand())
+	command.AddCommand(exec.NewCopyCommand())
+	command.AddCommand(exec.NewSyncCommand())
 	command.AddCommand(attach.NewAttachCommand())
+	command.AddCommand(attach.NewDebugCommand())
+	command.AddCommand(ssh.NewSSHConfigCommand())
+	command.AddCommand(ssh.NewSSHProxyCommand())
+	command.AddCommand(portforward.NewPortForwardCommand())
 	command.AddCommand(template.NewTemplateCommand())
 	command.AddCommand(project.NewProjectCommand())
 	command.AddCommand(cluster.NewClusterCommand())
 	command.AddCommand(login.NewLoginCommand())
 	command.AddCommand(logout.NewLogoutCommand())
 	command.AddCommand(login.NewWhoamiCommand())
+	command.AddCommand(auth.NewAuthCommand())
 	command.AddCommand(completion.NewCompletionCmd())
 
 	return command
@@ -0,0 +1,198 @@
+package ssh
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/run-ai/runai-cli/cmd/constants"
+	"github.com/run-ai/runai-cli/cmd/flags"
+	"github.com/run-ai/runai-cli/cmd/job"
+	"github.com/run-ai/runai-cli/cmd/trainer"
+	"github.com/run-ai/runai-cli/pkg/authentication/assertion"
+	"github.com/run-ai/runai-cli/pkg/client"
+	commandUtil "github.com/run-ai/runai-cli/pkg/util/command"
+	"github.com/spf13/cobra"
+)
+
+const (
+	sshConfigExamples = `
+# Print the SSH configuration of the running interactive jobs of the project
+runai ssh-config
+
+# Add the jobs to ~/.ssh/config, and connect to one of them, e.g. with VS Code Remote-SSH or PyCharm
+runai ssh-config --write
+ssh runai-team-a-build1
+
+# Print the SSH configuration of specific jobs, with an identity file
+runai ssh-config build1 build2 --identity-file ~/.ssh/runai
+`
+
+	sshHostPrefix = "runai-"
+)
+
+// SSHConfigOptions are the options of the entries of the SSH configuration of the jobs
+type SSHConfigOptions struct {
+	User         string
+	IdentityFile string
+	Port         int
+	// Executable is the path of the runai binary, which ssh runs as the ProxyCommand
+	Executable string
+}
+
+func NewSSHConfigCommand() *cobra.Command {
+	var options SSHConfigOptions
+	var write bool
+	var configFile string
+
+	var command = &cobra.Command{
+		Use:               "ssh-config [JOB_NAME ...]",
+		Short:             "Generate the SSH configuration of jobs, to connect to them with ssh, VS Code Remote-SSH or PyCharm.",
+		Long:              "Generate the SSH configuration of jobs, which tunnels SSH through the API server to the SSH server of the job. By default, of all the running interactive jobs of the project. The image of the job should run an SSH server, with the authorized keys given by 'runai submit --ssh'.",
+		Example:           sshConfigExamples,
+		ValidArgsFunction: job.GenJobNames,
+		PreRun:            commandUtil.NamespacedRoleAssertion(assertion.AssertExecutorRole),
+		Run: commandUtil.WrapRunCommand(func(cmd *cobra.Command, args []string) error {
+			kubeClient, err := client.GetClient()
+			if err != nil {
+				return err
+			}
+			namespaceInfo, err := flags.GetNamespaceToUseFromProjectFlag(cmd, kubeClient)
+			if err != nil {
+				return err
+			}
+
+			if options.Executable, err = os.Executable(); err != nil {
+				return err
+			}
+
+			jobNames := args
+			if len(jobNames) == 0 {
+				jobs, err := trainer.GetAllJobs(kubeClient, namespaceInfo, nil)
+				if err != nil {
+					return err
+				}
+				jobNames = runningInteractiveJobNames(jobs)
+				if len(jobNames) == 0 && !write {
+					return fmt.Errorf("No running interactive jobs found in project %s", namespaceInfo.ProjectName)
+				}
+			}
+
+			config := sshConfig(namespaceInfo.ProjectName, jobNames, options)
+			if !write {
+				fmt.Print(config)
+				return nil
+			}
+
+			if configFile == "" {
+				home, err := os.UserHomeDir()
+				if err != nil {
+					return err
+				}
+				configFile = filepath.Join(home, ".ssh", "config")
+			}
+			if err = writeSSHConfig(configFile, namespaceInfo.ProjectName, config); err != nil {
+				return err
+			}
+			fmt.Printf("Updated %s with the SSH configuration of %d jobs of project %s\n", configFile, len(jobNames), namespaceInfo.ProjectName)
+			for _, jobName := range jobNames {
+				fmt.Printf("    ssh %s\n", sshHost(namespaceInfo.ProjectName, jobName))
+			}
+			return nil
+		}),
+	}
+
+	command.Flags().StringVar(&options.User, "user", "root", "The user to connect as.")
+	command.Flags().StringVar(&options.IdentityFile, "identity-file", "", "The private key to connect with. By default the keys of ssh.")
+	command.Flags().IntVar(&options.Port, "port", defaultSSHPort, "The port of the SSH server in the jobs.")
+	command.Flags().BoolVar(&write, "write", false, "Write the configuration to the SSH configuration file, replacing the previous configuration of the project.")
+	command.Flags().StringVar(&configFile, "config-file", "", "The SSH configuration file to write to. By default ~/.ssh/config.")
+
+	return command
+}
+
+func runningInteractiveJobNames(jobs []trainer.TrainingJob) []string {
+	names := []string{}
+	for _, trainingJob := range jobs {
+		// The status is normalized as in the other commands, e.g. a suspended job is not running
+		if trainingJob.Trainer() == trainer.RunaiInteractiveType && strings.EqualFold(job.GetJobRealStatus(trainingJob), constants.Status.Running) {
+			names = append(names, trainingJob.Name())
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func sshHost(project, jobName string) string {
+	return sshHostPrefix + project + "-" + jobName
+}
+
+// sshConfig returns the Host entries of the jobs. The host keys of the jobs are not checked, since they change
+// whenever the pods of the jobs are recreated
+func sshConfig(project string, jobNames []string, options SSHConfigOptions) string {
+	builder := strings.Builder{}
+	for _, jobName := range jobNames {
+		proxyCommand := fmt.Sprintf("%s ssh-proxy %s --project %s", quoteSSHConfigValue(options.Executable), jobName, project)
+		if options.Port != defaultSSHPort {
+			proxyCommand += fmt.Sprintf(" --port %d", options.Port)
+		}
+
+		fmt.Fprintf(&builder, "Host %s\n", sshHost(project, jobName))
+		fmt.Fprintf(&builder, "  HostName %s\n", jobName)
+		fmt.Fprintf(&builder, "  User %s\n", options.User)
+		fmt.Fprintf(&builder, "  ProxyCommand %s\n", proxyCommand)
+		fmt.Fprintf(&builder, "  StrictHostKeyChecking no\n")
+		fmt.Fprintf(&builder, "  UserKnownHostsFile /dev/null\n")
+		if options.IdentityFile != "" {
+			fmt.Fprintf(&builder, "  IdentityFile %s\n", quoteSSHConfigValue(options.IdentityFile))
+		}
+		builder.WriteString("\n")
+	}
+	return builder.String()
+}
+
+func quoteSSHConfigValue(value string) string {
+	if strings.ContainsAny(value, " \t") {
+		return `"` + value + `"`
+	}
+	return value
+}
+
+func sshConfigBlockMarkers(project string) (string, string) {
+	return fmt.Sprintf("# BEGIN runai ssh-config %s", project), fmt.Sprintf("# END runai ssh-config %s", project)
+}
+
+// replaceSSHConfigBlock replaces the block of the project in the SSH configuration file, which 'runai ssh-config
+// --write' generated before, or appends it
+func replaceSSHConfigBlock(content, project, config string) string {
+	begin, end := sshConfigBlockMarkers(project)
+	block := begin + "\n" + config + end + "\n"
+
+	beginIndex := strings.Index(content, begin+"\n")
+	endIndex := strings.Index(content, end+"\n")
+	if beginIndex >= 0 && endIndex > beginIndex {
+		return content[:beginIndex] + block + content[endIndex+len(end)+1:]
+	}
+
+	if content != "" && !strings.HasSuffix(content, "\n") {
+		content += "\n"
+	}
+	if content != "" {
+		content += "\n"
+	}
+	return content + block
+}
+
+func writeSSHConfig(configFile, project, config string) error {
+	content, err := ioutil.ReadFile(configFile)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(configFile), 0700); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(configFile, []byte(replaceSSHConfigBlock(string(content), project, config)), 0600)
+}
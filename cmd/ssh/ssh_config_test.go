@@ -0,0 +1,85 @@
+package ssh
+
+import (
+	"testing"
+
+	"github.com/run-ai/runai-cli/cmd/constants"
+	"github.com/run-ai/runai-cli/cmd/trainer"
+	"github.com/run-ai/runai-cli/pkg/types"
+	"gotest.tools/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSSHConfig(t *testing.T) {
+	options := SSHConfigOptions{User: "root", Port: 22, Executable: "/usr/local/bin/runai", IdentityFile: "/home/john/My Keys/id_rsa"}
+
+	assert.Equal(t, sshConfig("team-a", []string{"build1"}, options), `Host runai-team-a-build1
+  HostName build1
+  User root
+  ProxyCommand /usr/local/bin/runai ssh-proxy build1 --project team-a
+  StrictHostKeyChecking no
+  UserKnownHostsFile /dev/null
+  IdentityFile "/home/john/My Keys/id_rsa"
+
+`)
+
+	options = SSHConfigOptions{User: "john", Port: 2222, Executable: "/opt/run ai/runai"}
+	assert.Equal(t, sshConfig("team-a", []string{"build1"}, options), `Host runai-team-a-build1
+  HostName build1
+  User john
+  ProxyCommand "/opt/run ai/runai" ssh-proxy build1 --project team-a --port 2222
+  StrictHostKeyChecking no
+  UserKnownHostsFile /dev/null
+
+`)
+}
+
+func TestReplaceSSHConfigBlock(t *testing.T) {
+	config := "Host runai-team-a-build1\n  HostName build1\n\n"
+
+	content := replaceSSHConfigBlock("Host github.com\n  User git", "team-a", config)
+	assert.Equal(t, content, `Host github.com
+  User git
+
+# BEGIN runai ssh-config team-a
+Host runai-team-a-build1
+  HostName build1
+
+# END runai ssh-config team-a
+`)
+
+	content = replaceSSHConfigBlock(content, "team-b", "Host runai-team-b-build2\n\n")
+	content = replaceSSHConfigBlock(content, "team-a", "Host runai-team-a-build3\n\n")
+	assert.Equal(t, content, `Host github.com
+  User git
+
+# BEGIN runai ssh-config team-a
+Host runai-team-a-build3
+
+# END runai ssh-config team-a
+
+# BEGIN runai ssh-config team-b
+Host runai-team-b-build2
+
+# END runai ssh-config team-b
+`)
+}
+
+func newTestInteractiveWorkload(name, status string, annotations map[string]string) trainer.TrainingJob {
+	jobMetadata := metav1.ObjectMeta{Name: name, Annotations: annotations}
+	owner := types.Resource{Name: name, ResourceType: types.ResourceTypeRunaiJob}
+	return trainer.NewRunaiWorkload([]v1.Pod{}, nil, metav1.Now(), trainer.RunaiInteractiveType, name, true, []string{}, false,
+		v1.PodSpec{}, metav1.ObjectMeta{}, jobMetadata, "runai-team", owner, status, 0, 1, 0, 0)
+}
+
+func TestRunningInteractiveJobNames(t *testing.T) {
+	jobs := []trainer.TrainingJob{
+		newTestInteractiveWorkload("build2", constants.Status.Running, nil),
+		newTestInteractiveWorkload("build1", "RUNNING", nil),
+		newTestInteractiveWorkload("pending", constants.Status.Pending, nil),
+		newTestInteractiveWorkload("suspended", constants.Status.Running, map[string]string{constants.WorkloadSuspendedReplicas: "1"}),
+	}
+
+	assert.DeepEqual(t, runningInteractiveJobNames(jobs), []string{"build1", "build2"})
+}
@@ -0,0 +1,70 @@
+package ssh
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/run-ai/runai-cli/cmd/exec"
+	"github.com/run-ai/runai-cli/cmd/job"
+	raUtil "github.com/run-ai/runai-cli/cmd/util"
+	"github.com/run-ai/runai-cli/pkg/authentication/assertion"
+	"github.com/run-ai/runai-cli/pkg/client"
+	"github.com/run-ai/runai-cli/pkg/portforward"
+	commandUtil "github.com/run-ai/runai-cli/pkg/util/command"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+const defaultSSHPort = 22
+
+// NewSSHProxyCommand creates the ssh-proxy command, the ProxyCommand of the entries of 'runai ssh-config'. Its
+// output is the SSH connection, so errors are only logged to stderr
+func NewSSHProxyCommand() *cobra.Command {
+	var podName string
+	var port int
+
+	var command = &cobra.Command{
+		Use:               "ssh-proxy JOB_NAME",
+		Short:             "Tunnel stdin and stdout to the SSH server of a running job, as the ProxyCommand of ssh.",
+		Hidden:            true,
+		ValidArgsFunction: job.GenJobNames,
+		Args:              cobra.ExactArgs(1),
+		PreRun:            commandUtil.NamespacedRoleAssertion(assertion.AssertExecutorRole),
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := sshProxy(cmd, args[0], podName, port); err != nil {
+				log.Error(err)
+				os.Exit(1)
+			}
+		},
+	}
+
+	job.AddPodNameFlag(command, &podName)
+	command.Flags().IntVar(&port, "port", defaultSSHPort, "The port of the SSH server in the job.")
+
+	return command
+}
+
+func sshProxy(cmd *cobra.Command, jobName, podName string, port int) error {
+	kubeClient, err := client.GetClient()
+	if err != nil {
+		return err
+	}
+
+	pod, err := exec.GetPodFromCmd(cmd, kubeClient, jobName, podName, exec.DefaultExecTimeout)
+	if err != nil {
+		return err
+	}
+	if isRunning, err := raUtil.PodRunning(pod); err != nil {
+		return err
+	} else if !isRunning {
+		return fmt.Errorf("Unable to connect to a pod that is not running")
+	}
+
+	conn, err := portforward.DialPod(kubeClient.GetRestConfig(), kubeClient.GetClientset(), pod)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	return portforward.ForwardStream(conn, port, 0, os.Stdin, os.Stdout)
+}
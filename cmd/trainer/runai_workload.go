@@ -468,3 +468,11 @@ func (rj *RunaiWorkload) TotalRequestedGPUsMemory() uint64 {
 func (rj *RunaiWorkload) CliCommand() string {
 	return getCliCommand(rj.jobMetadata.Annotations)
 }
+
+func (rj *RunaiWorkload) Labels() map[string]string {
+	return rj.jobMetadata.Labels
+}
+
+func (rj *RunaiWorkload) Annotations() map[string]string {
+	return rj.jobMetadata.Annotations
+}
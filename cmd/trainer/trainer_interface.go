@@ -101,6 +101,12 @@ type TrainingJob interface {
 	TotalRequestedGPUsString() string
 	CurrentRequestedGpusString() string
 	CliCommand() string
+
+	// Get the labels of the workload of the Training Job
+	Labels() map[string]string
+
+	// Get the annotations of the workload of the Training Job
+	Annotations() map[string]string
 }
 
 // Trainer interface for querying specific types of training jobs
@@ -818,6 +818,16 @@ func (mj *MPIJob) CliCommand() string {
 	return getCliCommand(mj.mpijob.ObjectMeta.Annotations)
 }
 
+// Get the labels of the mpijob
+func (mj *MPIJob) Labels() map[string]string {
+	return mj.mpijob.ObjectMeta.Labels
+}
+
+// Get the annotations of the mpijob
+func (mj *MPIJob) Annotations() map[string]string {
+	return mj.mpijob.ObjectMeta.Annotations
+}
+
 func getPodsOfMPIJob(name string, namespace string, tt *MPIJobTrainer, podList []v1.Pod) (pods []v1.Pod, chiefPod v1.Pod) {
 	pods = []v1.Pod{}
 	for _, item := range podList {
@@ -0,0 +1,101 @@
+package trainer
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/run-ai/runai-cli/cmd/constants"
+	clientset "github.com/run-ai/runai-cli/cmd/mpi/client/clientset/versioned"
+	"github.com/run-ai/runai-cli/pkg/client"
+	log "github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+const watchReopenInterval = time.Second
+
+type watchFunc func(options metav1.ListOptions) (watch.Interface, error)
+
+// WatchJobChanges notifies on the returned channel whenever a runai workload or one of its pods changes in the namespace.
+// Notifications are coalesced, so a single read may stand for several changes. The watches stop when stopCh is closed.
+func WatchJobChanges(kubeClient *client.Client, namespace string, stopCh <-chan struct{}) (<-chan struct{}, error) {
+	kubeClientset := kubeClient.GetClientset()
+	runaijobClient, err := clientset.NewForConfig(kubeClient.GetRestConfig())
+	if err != nil {
+		return nil, err
+	}
+
+	podsWatch := func(options metav1.ListOptions) (watch.Interface, error) {
+		options.FieldSelector = fmt.Sprintf("spec.schedulerName=%s", constants.SchedulerName)
+		return kubeClientset.CoreV1().Pods(namespace).Watch(options)
+	}
+
+	// The pods watch is mandatory, the workload watches are best effort as some clusters lack their resources
+	podsWatcher, err := podsWatch(metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make(chan struct{}, 1)
+	go forwardWatchEvents(podsWatcher, podsWatch, changes, stopCh)
+
+	workloadWatches := []watchFunc{
+		kubeClientset.BatchV1().Jobs(namespace).Watch,
+		kubeClientset.AppsV1().StatefulSets(namespace).Watch,
+		kubeClientset.AppsV1().Deployments(namespace).Watch,
+		runaijobClient.RunV1().RunaiJobs(namespace).Watch,
+	}
+
+	for _, workloadWatch := range workloadWatches {
+		watcher, err := workloadWatch(metav1.ListOptions{})
+		if err != nil {
+			log.Debugf("Failed to watch workloads in namespace %s due to %v", namespace, err)
+			continue
+		}
+		go forwardWatchEvents(watcher, workloadWatch, changes, stopCh)
+	}
+
+	return changes, nil
+}
+
+func forwardWatchEvents(watcher watch.Interface, reopen watchFunc, changes chan<- struct{}, stopCh <-chan struct{}) {
+	for {
+		select {
+		case <-stopCh:
+			watcher.Stop()
+			return
+		case _, ok := <-watcher.ResultChan():
+			if !ok {
+				// The api server closes watches from time to time, reopen it until we are asked to stop
+				watcher = reopenWatch(reopen, stopCh)
+				if watcher == nil {
+					return
+				}
+			}
+			notifyChange(changes)
+		}
+	}
+}
+
+func reopenWatch(reopen watchFunc, stopCh <-chan struct{}) watch.Interface {
+	for {
+		watcher, err := reopen(metav1.ListOptions{})
+		if err == nil {
+			return watcher
+		}
+		log.Debugf("Failed to reopen watch due to %v", err)
+
+		select {
+		case <-stopCh:
+			return nil
+		case <-time.After(watchReopenInterval):
+		}
+	}
+}
+
+func notifyChange(changes chan<- struct{}) {
+	select {
+	case changes <- struct{}{}:
+	default:
+	}
+}
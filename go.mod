@@ -3,7 +3,6 @@ module github.com/run-ai/runai-cli
 go 1.13
 
 require (
-	github.com/Azure/go-autorest/autorest v0.9.6 // indirect
 	github.com/coreos/go-oidc v2.2.1+incompatible
 	github.com/dsnet/compress v0.0.1 // indirect
 	github.com/frankban/quicktest v1.9.0 // indirect
@@ -11,7 +10,6 @@ require (
 	github.com/golang/glog v0.0.0-20160126235308-23def4e6c14b
 	github.com/golang/snappy v0.0.1 // indirect
 	github.com/int128/oauth2cli v1.13.0
-	github.com/kubeflow/mpi-operator v0.2.2 // indirect
 	github.com/magiconair/properties v1.8.1
 	github.com/mholt/archiver v3.1.1+incompatible
 	github.com/mitchellh/go-homedir v1.1.0
@@ -26,17 +24,16 @@ require (
 	github.com/sirupsen/logrus v1.5.0
 	github.com/spf13/cobra v1.1.1
 	github.com/spf13/pflag v1.0.5
-	github.com/ugorji/go v1.1.4 // indirect
 	github.com/ulikunitz/xz v0.5.7 // indirect
 	github.com/xi2/xz v0.0.0-20171230120015-48954b6210f8 // indirect
 	golang.org/x/crypto v0.0.0-20200622213623-75b288015ac9
 	golang.org/x/oauth2 v0.0.0-20200107190931-bf48bf16ab8d
 	golang.org/x/sync v0.0.0-20201020160332-67f06af15bc9
 	golang.org/x/time v0.0.0-20191024005414-555d28b269f0 // indirect
-	google.golang.org/appengine v1.6.1
-	gopkg.in/square/go-jose.v2 v2.5.1 // indirect
+	gopkg.in/square/go-jose.v2 v2.5.1
 	gopkg.in/yaml.v2 v2.4.0
 	gotest.tools v2.2.0+incompatible
+	helm.sh/helm/v3 v3.1.3
 	k8s.io/api v0.17.6
 	k8s.io/apimachinery v0.19.3
 	k8s.io/cli-runtime v0.17.4
@@ -0,0 +1,85 @@
+package assertion
+
+import (
+	"sync"
+
+	authv1 "k8s.io/api/authorization/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// The number of access reviews which are sent at once
+const accessReviewsConcurrency = 10
+
+// ProjectPermission is an action on the jobs of a project, and the access review which checks whether the user may
+// perform it
+type ProjectPermission struct {
+	Name       string
+	Attributes authv1.ResourceAttributes
+}
+
+// ProjectPermissions are the actions which are checked by 'runai whoami --verbose', in the order they are shown
+var ProjectPermissions = []ProjectPermission{
+	{Name: "view", Attributes: authv1.ResourceAttributes{Verb: "list", Version: "v1", Resource: "pods"}},
+	// Submitting a job creates its configmap first, the same as AssertExecutorRole checks
+	{Name: "submit", Attributes: authv1.ResourceAttributes{Verb: "create", Version: "v1", Resource: "configmaps"}},
+	{Name: "delete", Attributes: authv1.ResourceAttributes{Verb: "delete", Version: "v1", Resource: "configmaps"}},
+	{Name: "exec", Attributes: authv1.ResourceAttributes{Verb: "create", Version: "v1", Resource: "pods", Subresource: "exec"}},
+	{Name: "logs", Attributes: authv1.ResourceAttributes{Verb: "get", Version: "v1", Resource: "pods", Subresource: "log"}},
+}
+
+// ProjectAccess is what the user may do in a project. Allowed has an entry for every one of the ProjectPermissions
+type ProjectAccess struct {
+	Project   string
+	Namespace string
+	Allowed   map[string]bool
+	// Why an access review failed, when it did
+	Error error
+}
+
+// GetProjectsAccess runs a self subject access review for every one of the ProjectPermissions in every project. The
+// key of projectNamespaces is the name of the project, the value is its namespace
+func GetProjectsAccess(clientset kubernetes.Interface, projectNamespaces map[string]string) []*ProjectAccess {
+	type accessReview struct {
+		access     *ProjectAccess
+		permission ProjectPermission
+	}
+
+	accesses := []*ProjectAccess{}
+	reviews := make(chan accessReview)
+	for project, namespace := range projectNamespaces {
+		accesses = append(accesses, &ProjectAccess{Project: project, Namespace: namespace, Allowed: map[string]bool{}})
+	}
+
+	var lock sync.Mutex
+	var waitGroup sync.WaitGroup
+	for i := 0; i < accessReviewsConcurrency; i++ {
+		waitGroup.Add(1)
+		go func() {
+			defer waitGroup.Done()
+			for review := range reviews {
+				attributes := review.permission.Attributes
+				attributes.Namespace = review.access.Namespace
+				response, err := clientset.AuthorizationV1().SelfSubjectAccessReviews().Create(&authv1.SelfSubjectAccessReview{
+					Spec: authv1.SelfSubjectAccessReviewSpec{ResourceAttributes: &attributes},
+				})
+
+				lock.Lock()
+				if err != nil {
+					review.access.Error = getAuthorizationErrorIfNeeded(err)
+				} else {
+					review.access.Allowed[review.permission.Name] = response.Status.Allowed
+				}
+				lock.Unlock()
+			}
+		}()
+	}
+
+	for _, access := range accesses {
+		for _, permission := range ProjectPermissions {
+			reviews <- accessReview{access: access, permission: permission}
+		}
+	}
+	close(reviews)
+	waitGroup.Wait()
+	return accesses
+}
@@ -0,0 +1,34 @@
+package assertion
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+	authv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestGetProjectsAccess(t *testing.T) {
+	clientset := fake.NewSimpleClientset()
+	// The user may view everything, and do anything else only in the namespace of team-a
+	clientset.PrependReactor("create", "selfsubjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		review := action.(k8stesting.CreateAction).GetObject().(*authv1.SelfSubjectAccessReview)
+		attributes := review.Spec.ResourceAttributes
+		review.Status.Allowed = attributes.Namespace == "runai-team-a" || (attributes.Verb == "list" && attributes.Resource == "pods")
+		return true, review, nil
+	})
+
+	accesses := GetProjectsAccess(clientset, map[string]string{"team-a": "runai-team-a", "team-b": "runai-team-b"})
+
+	assert.Equal(t, len(accesses), 2)
+	for _, access := range accesses {
+		assert.NilError(t, access.Error)
+		if access.Project == "team-a" {
+			assert.DeepEqual(t, access.Allowed, map[string]bool{"view": true, "submit": true, "delete": true, "exec": true, "logs": true})
+		} else {
+			assert.DeepEqual(t, access.Allowed, map[string]bool{"view": true, "submit": false, "delete": false, "exec": false, "logs": false})
+		}
+	}
+}
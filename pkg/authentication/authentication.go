@@ -3,19 +3,26 @@ package authentication
 import (
 	"context"
 	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
 	"github.com/run-ai/runai-cli/cmd/util"
+	"github.com/run-ai/runai-cli/pkg/authentication/execcredential"
 	"github.com/run-ai/runai-cli/pkg/authentication/flows/code-pkce-browser"
 	code_pkce_remote_browser "github.com/run-ai/runai-cli/pkg/authentication/flows/code-pkce-remote-browser"
+	device_code "github.com/run-ai/runai-cli/pkg/authentication/flows/device-code"
 	"github.com/run-ai/runai-cli/pkg/authentication/flows/password"
 	"github.com/run-ai/runai-cli/pkg/authentication/jwt"
 	"github.com/run-ai/runai-cli/pkg/authentication/kubeconfig"
+	"github.com/run-ai/runai-cli/pkg/authentication/tokencache"
 	"github.com/run-ai/runai-cli/pkg/authentication/types"
 	log "github.com/sirupsen/logrus"
 	"golang.org/x/oauth2"
 )
 
 func GetCurrentAuthenticateUser() (string, error) {
-	idToken, err := kubeconfig.GetCurrentUserIdToken()
+	idToken, err := getCurrentUserIdToken()
 	if err != nil {
 		return "", err
 	}
@@ -27,8 +34,17 @@ func GetCurrentAuthenticateUser() (string, error) {
 	return token.Email, nil
 }
 
+// GetCurrentAuthenticateUserToken returns all the claims of the id token of the current user
+func GetCurrentAuthenticateUserToken() (jwt.Token, error) {
+	idToken, err := getCurrentUserIdToken()
+	if err != nil {
+		return jwt.Token{}, err
+	}
+	return jwt.Decode(idToken)
+}
+
 func GetCurrentAuthenticateUserSubject() (string, string, error) {
-	idToken, err := kubeconfig.GetCurrentUserIdToken()
+	idToken, err := getCurrentUserIdToken()
 	if err != nil {
 		return "", "", err
 	}
@@ -41,7 +57,7 @@ func GetCurrentAuthenticateUserSubject() (string, string, error) {
 }
 
 func GetCurrentAuthenticateUserUidGid() (string, string, error) {
-	idToken, err := kubeconfig.GetCurrentUserIdToken()
+	idToken, err := getCurrentUserIdToken()
 	if err != nil {
 		return "", "", err
 	}
@@ -65,12 +81,71 @@ func Authenticate(params *types.AuthenticationParams) error {
 		return err
 	}
 	log.Debug("Authentication process done successfully")
+	if params.UseExecCredential {
+		return setExecCredential(params, token)
+	}
 	if params.User == "" {
 		return kubeconfig.SetTokenToCurrentUser(params.AuthenticationFlow, token)
 	}
 	return kubeconfig.SetTokenToUser(params.User, params.AuthenticationFlow, token)
 }
 
+// setExecCredential caches the tokens, and makes kubectl get them from 'runai auth exec-credential'
+func setExecCredential(params *types.AuthenticationParams, token *oauth2.Token) error {
+	var err error
+	if params.User == "" {
+		if params.User, err = kubeconfig.GetCurrentUser(); err != nil {
+			return err
+		}
+	}
+	cache, err := tokencache.NewCache()
+	if err != nil {
+		return err
+	}
+	if err = execcredential.SaveTokens(params, cache, token); err != nil {
+		return fmt.Errorf("failed to cache the tokens: %v", err)
+	}
+
+	command, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	if command, err = filepath.EvalSymlinks(command); err != nil {
+		return err
+	}
+	return kubeconfig.SetExecCredentialToUser(params.User, command, params)
+}
+
+// GetExecCredentialIdToken returns the cached id token of a user which logged in with the exec credential plugin
+func GetExecCredentialIdToken(params *types.AuthenticationParams) (string, time.Time, error) {
+	if params.ClientId == "" || params.IssuerURL == "" || params.User == "" {
+		return "", time.Time{}, fmt.Errorf("client-id, idp-issuer-url and user must be set")
+	}
+	cache, err := tokencache.NewCache()
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return execcredential.GetIdToken(context.Background(), params, cache)
+}
+
+// DeleteExecCredentialTokens removes the cached tokens of a user which logged in with the exec credential plugin
+func DeleteExecCredentialTokens(params *types.AuthenticationParams) error {
+	cache, err := tokencache.NewCache()
+	if err != nil {
+		return err
+	}
+	return execcredential.DeleteTokens(params, cache)
+}
+
+func getCurrentUserIdToken() (string, error) {
+	params, err := kubeconfig.GetCurrentUserAuthenticationParams()
+	if err != nil || !params.UseExecCredential {
+		return kubeconfig.GetCurrentUserIdToken()
+	}
+	idToken, _, err := GetExecCredentialIdToken(params)
+	return idToken, err
+}
+
 func GetFinalAuthenticationParams(cliParams *types.AuthenticationParams) (*types.AuthenticationParams, error) {
 	var kubeConfigParams *types.AuthenticationParams
 	var err error
@@ -98,6 +173,8 @@ func runAuthenticationByFlow(ctx context.Context, params *types.AuthenticationPa
 		return password.AuthenticateAuth0PasswordRealm(ctx, params)
 	case types.CodePkceRemoteBrowser:
 		return code_pkce_remote_browser.AuthenticateCodePkceRemoteBrowser(ctx, params)
+	case types.DeviceCode:
+		return device_code.AuthenticateDeviceCode(ctx, params)
 	}
 	return nil, fmt.Errorf("unidentified authentication method %v", params.AuthenticationFlow)
 }
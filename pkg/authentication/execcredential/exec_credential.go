@@ -0,0 +1,118 @@
+package execcredential
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/run-ai/runai-cli/pkg/authentication/flows"
+	"github.com/run-ai/runai-cli/pkg/authentication/jwt"
+	"github.com/run-ai/runai-cli/pkg/authentication/kubeconfig"
+	"github.com/run-ai/runai-cli/pkg/authentication/tokencache"
+	"github.com/run-ai/runai-cli/pkg/authentication/types"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/oauth2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientauthv1beta1 "k8s.io/client-go/pkg/apis/clientauthentication/v1beta1"
+)
+
+// An id token which expires sooner than that is refreshed, so it does not expire while a request is sent
+const expirySkew = time.Minute
+
+// Refresher gets new tokens from the identity provider with a refresh token
+type Refresher func(ctx context.Context, params *types.AuthenticationParams, refreshToken string) (*oauth2.Token, error)
+
+// GetIdToken returns the cached id token of the user, refreshed with the cached refresh token if it expired
+func GetIdToken(ctx context.Context, params *types.AuthenticationParams, cache *tokencache.Cache) (string, time.Time, error) {
+	return getIdToken(ctx, params, cache, RefreshTokens, time.Now())
+}
+
+func getIdToken(ctx context.Context, params *types.AuthenticationParams, cache *tokencache.Cache, refresh Refresher, now time.Time) (string, time.Time, error) {
+	key := tokencache.Key(params.User, params.IssuerURL, params.ClientId)
+	tokens, err := cache.Load(key)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to read the cached tokens: %v", err)
+	}
+	if tokens == nil || tokens.IdToken == "" {
+		return "", time.Time{}, fmt.Errorf("user %s is not logged in, run 'runai login'", params.User)
+	}
+
+	token, err := jwt.Decode(tokens.IdToken)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("invalid cached id token: %v", err)
+	}
+	expiry := token.ExpiresAt()
+	if expiry.IsZero() || now.Add(expirySkew).Before(expiry) {
+		return tokens.IdToken, expiry, nil
+	}
+
+	if tokens.RefreshToken == "" {
+		return "", time.Time{}, fmt.Errorf("the login of user %s expired, run 'runai login'", params.User)
+	}
+	log.Debugf("Refreshing the id token of user %s, which expires at %v", params.User, expiry)
+	refreshed, err := refresh(ctx, params, tokens.RefreshToken)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to refresh the login of user %s, run 'runai login': %v", params.User, err)
+	}
+
+	newTokens := TokensFromOauth2Token(refreshed)
+	if newTokens.IdToken == "" {
+		return "", time.Time{}, fmt.Errorf("the identity provider did not return an id token for user %s", params.User)
+	}
+	if newTokens.RefreshToken == "" {
+		newTokens.RefreshToken = tokens.RefreshToken
+	}
+	if err = cache.Save(key, newTokens); err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to cache the refreshed tokens: %v", err)
+	}
+
+	token, err = jwt.Decode(newTokens.IdToken)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("invalid refreshed id token: %v", err)
+	}
+	return newTokens.IdToken, token.ExpiresAt(), nil
+}
+
+// RefreshTokens gets new tokens with the refresh token grant of the identity provider
+func RefreshTokens(ctx context.Context, params *types.AuthenticationParams, refreshToken string) (*oauth2.Token, error) {
+	oauth2Config, err := flows.GetOauth2Config(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	return oauth2Config.TokenSource(ctx, &oauth2.Token{RefreshToken: refreshToken}).Token()
+}
+
+// SaveTokens caches the tokens which the user got by logging in
+func SaveTokens(params *types.AuthenticationParams, cache *tokencache.Cache, token *oauth2.Token) error {
+	return cache.Save(tokencache.Key(params.User, params.IssuerURL, params.ClientId), TokensFromOauth2Token(token))
+}
+
+// DeleteTokens removes the cached tokens of the user when logging out
+func DeleteTokens(params *types.AuthenticationParams, cache *tokencache.Cache) error {
+	return cache.Delete(tokencache.Key(params.User, params.IssuerURL, params.ClientId))
+}
+
+func TokensFromOauth2Token(token *oauth2.Token) *tokencache.Tokens {
+	tokens := &tokencache.Tokens{RefreshToken: token.RefreshToken}
+	if idToken, isString := token.Extra(kubeconfig.IdTokenRawTokenName).(string); isString {
+		tokens.IdToken = idToken
+	}
+	return tokens
+}
+
+// NewExecCredential returns the response of an exec credential plugin to kubectl. kubectl runs the plugin again a bit
+// before the id token expires, which refreshes it
+func NewExecCredential(idToken string, expiry time.Time) *clientauthv1beta1.ExecCredential {
+	status := &clientauthv1beta1.ExecCredentialStatus{Token: idToken}
+	if !expiry.IsZero() {
+		expirationTimestamp := metav1.NewTime(expiry.Add(-expirySkew))
+		status.ExpirationTimestamp = &expirationTimestamp
+	}
+	return &clientauthv1beta1.ExecCredential{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: clientauthv1beta1.SchemeGroupVersion.String(),
+			Kind:       "ExecCredential",
+		},
+		Status: status,
+	}
+}
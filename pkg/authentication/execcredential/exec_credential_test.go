@@ -0,0 +1,141 @@
+package execcredential
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/run-ai/runai-cli/pkg/authentication/tokencache"
+	"github.com/run-ai/runai-cli/pkg/authentication/types"
+	"golang.org/x/oauth2"
+	"gotest.tools/assert"
+)
+
+func newTestIdToken(subject string, expiry time.Time) string {
+	encode := func(content string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(content))
+	}
+	claims := fmt.Sprintf(`{"sub":"%s","exp":%d}`, subject, expiry.Unix())
+	return encode(`{"alg":"none"}`) + "." + encode(claims) + ".signature"
+}
+
+// newFakeIssuer serves the discovery document and the refresh token grant of an identity provider
+func newFakeIssuer(t *testing.T, idToken string) *httptest.Server {
+	var issuer *httptest.Server
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 issuer.URL,
+			"authorization_endpoint": issuer.URL + "/auth",
+			"token_endpoint":         issuer.URL + "/token",
+			"jwks_uri":               issuer.URL + "/keys",
+		})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		assert.NilError(t, r.ParseForm())
+		if r.Form.Get("grant_type") != "refresh_token" || r.Form.Get("refresh_token") != "old-refresh-token" {
+			http.Error(w, `{"error":"invalid_grant"}`, http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token":  "access-token",
+			"token_type":    "Bearer",
+			"expires_in":    3600,
+			"id_token":      idToken,
+			"refresh_token": "new-refresh-token",
+		})
+	})
+	issuer = httptest.NewServer(mux)
+	return issuer
+}
+
+func newTestCache(t *testing.T) (*tokencache.Cache, func()) {
+	dir, err := ioutil.TempDir("", "runai-tokens")
+	assert.NilError(t, err)
+	return tokencache.NewCacheInDir(dir), func() { os.RemoveAll(dir) }
+}
+
+func TestGetIdTokenNotExpired(t *testing.T) {
+	cache, cleanup := newTestCache(t)
+	defer cleanup()
+	params := &types.AuthenticationParams{User: "user", IssuerURL: "https://issuer", ClientId: "runai-cli"}
+	expiry := time.Now().Add(time.Hour)
+	idToken := newTestIdToken("john", expiry)
+	assert.NilError(t, cache.Save(tokencache.Key("user", "https://issuer", "runai-cli"), &tokencache.Tokens{IdToken: idToken}))
+
+	refresh := func(ctx context.Context, params *types.AuthenticationParams, refreshToken string) (*oauth2.Token, error) {
+		t.Fatal("a valid token should not be refreshed")
+		return nil, nil
+	}
+	result, resultExpiry, err := getIdToken(context.Background(), params, cache, refresh, time.Now())
+
+	assert.NilError(t, err)
+	assert.Equal(t, result, idToken)
+	assert.Equal(t, resultExpiry.Unix(), expiry.Unix())
+}
+
+func TestGetIdTokenRefreshesExpiredToken(t *testing.T) {
+	cache, cleanup := newTestCache(t)
+	defer cleanup()
+	expiry := time.Now().Add(time.Hour)
+	newIdToken := newTestIdToken("john", expiry)
+	issuer := newFakeIssuer(t, newIdToken)
+	defer issuer.Close()
+
+	params := &types.AuthenticationParams{User: "user", IssuerURL: issuer.URL, ClientId: "runai-cli"}
+	key := tokencache.Key("user", issuer.URL, "runai-cli")
+	oldTokens := &tokencache.Tokens{IdToken: newTestIdToken("john", time.Now().Add(-time.Minute)), RefreshToken: "old-refresh-token"}
+	assert.NilError(t, cache.Save(key, oldTokens))
+
+	result, resultExpiry, err := GetIdToken(context.Background(), params, cache)
+
+	assert.NilError(t, err)
+	assert.Equal(t, result, newIdToken)
+	assert.Equal(t, resultExpiry.Unix(), expiry.Unix())
+	cached, err := cache.Load(key)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, cached, &tokencache.Tokens{IdToken: newIdToken, RefreshToken: "new-refresh-token"})
+}
+
+func TestGetIdTokenRefreshFailure(t *testing.T) {
+	cache, cleanup := newTestCache(t)
+	defer cleanup()
+	issuer := newFakeIssuer(t, "")
+	defer issuer.Close()
+
+	params := &types.AuthenticationParams{User: "user", IssuerURL: issuer.URL, ClientId: "runai-cli"}
+	oldTokens := &tokencache.Tokens{IdToken: newTestIdToken("john", time.Now().Add(-time.Minute)), RefreshToken: "revoked-refresh-token"}
+	assert.NilError(t, cache.Save(tokencache.Key("user", issuer.URL, "runai-cli"), oldTokens))
+
+	_, _, err := GetIdToken(context.Background(), params, cache)
+
+	assert.ErrorContains(t, err, "failed to refresh the login of user user, run 'runai login'")
+}
+
+func TestGetIdTokenNotLoggedIn(t *testing.T) {
+	cache, cleanup := newTestCache(t)
+	defer cleanup()
+	params := &types.AuthenticationParams{User: "user", IssuerURL: "https://issuer", ClientId: "runai-cli"}
+
+	_, _, err := GetIdToken(context.Background(), params, cache)
+
+	assert.ErrorContains(t, err, "user user is not logged in")
+}
+
+func TestNewExecCredential(t *testing.T) {
+	expiry := time.Date(2020, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	credential, err := json.Marshal(NewExecCredential("id-token", expiry))
+
+	assert.NilError(t, err)
+	assert.Equal(t, string(credential), `{"kind":"ExecCredential","apiVersion":"client.authentication.k8s.io/v1beta1","spec":{},"status":{"expirationTimestamp":"2020-10-01T11:59:00Z","token":"id-token"}}`)
+}
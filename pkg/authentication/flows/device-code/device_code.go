@@ -0,0 +1,221 @@
+package device_code
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/coreos/go-oidc"
+	"github.com/run-ai/runai-cli/pkg/authentication/flows"
+	"github.com/run-ai/runai-cli/pkg/authentication/types"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/oauth2"
+)
+
+const (
+	deviceCodeGrantType = "urn:ietf:params:oauth:grant-type:device_code"
+
+	// The errors of the token endpoint while the user has not approved the login yet, see RFC 8628 section 3.5
+	authorizationPendingError = "authorization_pending"
+	slowDownError             = "slow_down"
+	accessDeniedError         = "access_denied"
+	expiredTokenError         = "expired_token"
+
+	defaultPollInterval = 5 * time.Second
+	slowDownInterval    = 5 * time.Second
+)
+
+type deviceAuthorizationResponse struct {
+	DeviceCode              string `json:"device_code"`
+	UserCode                string `json:"user_code"`
+	VerificationURI         string `json:"verification_uri"`
+	VerificationURIComplete string `json:"verification_uri_complete,omitempty"`
+	// Auth0 names it verification_url, like the draft of the RFC did
+	VerificationURL string `json:"verification_url,omitempty"`
+	ExpiresIn       int    `json:"expires_in"`
+	Interval        int    `json:"interval,omitempty"`
+}
+
+type tokenResponse struct {
+	AccessToken      string `json:"access_token"`
+	TokenType        string `json:"token_type"`
+	RefreshToken     string `json:"refresh_token"`
+	ExpiresIn        int    `json:"expires_in"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// deviceCodeFlow is the OAuth 2.0 device authorization grant (RFC 8628), for machines without a browser. The user
+// approves the login in a browser on another machine, while the CLI polls the identity provider until they do
+type deviceCodeFlow struct {
+	out   io.Writer
+	sleep func(time.Duration)
+	now   func() time.Time
+}
+
+func AuthenticateDeviceCode(ctx context.Context, authParams *types.AuthenticationParams) (*oauth2.Token, error) {
+	flow := &deviceCodeFlow{out: os.Stdout, sleep: time.Sleep, now: time.Now}
+	return flow.authenticate(ctx, authParams)
+}
+
+func (f *deviceCodeFlow) authenticate(ctx context.Context, authParams *types.AuthenticationParams) (*oauth2.Token, error) {
+	log.Debug("Authentication process start with device authorization flow")
+	provider, err := oidc.NewProvider(ctx, authParams.IssuerURL)
+	if err != nil {
+		return nil, err
+	}
+	var providerClaims struct {
+		DeviceAuthorizationEndpoint string `json:"device_authorization_endpoint"`
+	}
+	if err = provider.Claims(&providerClaims); err != nil {
+		return nil, err
+	}
+	if providerClaims.DeviceAuthorizationEndpoint == "" {
+		return nil, fmt.Errorf("the identity provider %v does not support the device authorization flow", authParams.IssuerURL)
+	}
+
+	authorization, err := requestDeviceAuthorization(ctx, providerClaims.DeviceAuthorizationEndpoint, authParams.ClientId)
+	if err != nil {
+		return nil, err
+	}
+	verificationURI := authorization.VerificationURI
+	if verificationURI == "" {
+		verificationURI = authorization.VerificationURL
+	}
+	fmt.Fprintf(f.out, "Go to the following link in a browser on any device: \n\t%v\n", verificationURI)
+	fmt.Fprintf(f.out, "And enter the code: %v\n", authorization.UserCode)
+	if authorization.VerificationURIComplete != "" {
+		fmt.Fprintf(f.out, "Or go to the following link, which already contains the code: \n\t%v\n", authorization.VerificationURIComplete)
+	}
+
+	return f.pollToken(ctx, provider.Endpoint().TokenURL, authParams.ClientId, authorization)
+}
+
+func requestDeviceAuthorization(ctx context.Context, endpoint, clientId string) (*deviceAuthorizationResponse, error) {
+	requestParams := url.Values{
+		"client_id": {clientId},
+		"scope":     {strings.Join(flows.Scopes, " ")},
+	}
+	body, statusCode, err := postForm(ctx, endpoint, requestParams)
+	if err != nil {
+		return nil, fmt.Errorf("device authorization request failed: %v", err)
+	}
+	if statusCode < 200 || statusCode > 299 {
+		log.Debugf("invalid device authorization response: %v, %v", statusCode, string(body))
+		return nil, fmt.Errorf("device authorization request failed: %v", responseError(body, statusCode))
+	}
+
+	authorization := &deviceAuthorizationResponse{}
+	if err = json.Unmarshal(body, authorization); err != nil {
+		return nil, fmt.Errorf("invalid device authorization response: %v", err)
+	}
+	if authorization.DeviceCode == "" || authorization.UserCode == "" {
+		return nil, fmt.Errorf("invalid device authorization response: device_code and user_code are missing")
+	}
+	return authorization, nil
+}
+
+// pollToken polls the token endpoint at the interval of the identity provider, until the user approves the login,
+// denies it, or the device code expires
+func (f *deviceCodeFlow) pollToken(ctx context.Context, tokenURL, clientId string, authorization *deviceAuthorizationResponse) (*oauth2.Token, error) {
+	interval := defaultPollInterval
+	if authorization.Interval > 0 {
+		interval = time.Duration(authorization.Interval) * time.Second
+	}
+	var deadline time.Time
+	if authorization.ExpiresIn > 0 {
+		deadline = f.now().Add(time.Duration(authorization.ExpiresIn) * time.Second)
+	}
+
+	requestParams := url.Values{
+		"grant_type":  {deviceCodeGrantType},
+		"device_code": {authorization.DeviceCode},
+		"client_id":   {clientId},
+	}
+	for {
+		if !deadline.IsZero() && f.now().After(deadline) {
+			return nil, fmt.Errorf("the code expired before the login was approved")
+		}
+		f.sleep(interval)
+
+		body, statusCode, err := postForm(ctx, tokenURL, requestParams)
+		if err != nil {
+			return nil, fmt.Errorf("oauth2: cannot fetch token: %v", err)
+		}
+		response := &tokenResponse{}
+		if err = json.Unmarshal(body, response); err != nil {
+			log.Debugf("invalid token response: %v, %v", statusCode, string(body))
+			return nil, fmt.Errorf("oauth2: cannot parse token response: %v", err)
+		}
+
+		switch response.Error {
+		case "":
+			if statusCode < 200 || statusCode > 299 {
+				return nil, fmt.Errorf("oauth2: cannot fetch token: %v", responseError(body, statusCode))
+			}
+			return convertTokenResponseToOauth2Token(response, body, f.now())
+		case authorizationPendingError:
+			log.Debug("The login was not approved yet")
+		case slowDownError:
+			interval += slowDownInterval
+			log.Debugf("Polling the identity provider every %v", interval)
+		case accessDeniedError:
+			return nil, fmt.Errorf("the login was denied")
+		case expiredTokenError:
+			return nil, fmt.Errorf("the code expired before the login was approved")
+		default:
+			return nil, fmt.Errorf("oauth2: cannot fetch token: %v", responseError(body, statusCode))
+		}
+	}
+}
+
+func convertTokenResponseToOauth2Token(response *tokenResponse, body []byte, now time.Time) (*oauth2.Token, error) {
+	// The id token and any other field are kept as the extra fields of the token
+	extra := make(map[string]interface{})
+	if err := json.Unmarshal(body, &extra); err != nil {
+		return nil, err
+	}
+	token := &oauth2.Token{
+		AccessToken:  response.AccessToken,
+		TokenType:    response.TokenType,
+		RefreshToken: response.RefreshToken,
+	}
+	if response.ExpiresIn > 0 {
+		token.Expiry = now.Add(time.Duration(response.ExpiresIn) * time.Second)
+	}
+	return token.WithExtra(extra), nil
+}
+
+func postForm(ctx context.Context, endpoint string, params url.Values) ([]byte, int, error) {
+	req, err := http.NewRequest("POST", endpoint, strings.NewReader(params.Encode()))
+	if err != nil {
+		return nil, 0, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	res, err := http.DefaultClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, 0, err
+	}
+	defer res.Body.Close()
+
+	body, err := ioutil.ReadAll(res.Body)
+	return body, res.StatusCode, err
+}
+
+func responseError(body []byte, statusCode int) string {
+	response := &tokenResponse{}
+	if err := json.Unmarshal(body, response); err == nil && response.Error != "" {
+		if response.ErrorDescription != "" {
+			return fmt.Sprintf("%v: %v", response.Error, response.ErrorDescription)
+		}
+		return response.Error
+	}
+	return fmt.Sprintf("%v %v", statusCode, http.StatusText(statusCode))
+}
@@ -0,0 +1,141 @@
+package device_code
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/run-ai/runai-cli/pkg/authentication/kubeconfig"
+	"github.com/run-ai/runai-cli/pkg/authentication/types"
+	"gotest.tools/assert"
+)
+
+// fakeIssuer is an identity provider which supports the device authorization flow. The token endpoint answers with the
+// given errors, one for every poll, before it returns the tokens
+type fakeIssuer struct {
+	server      *httptest.Server
+	pollErrors  []string
+	tokenPolls  int
+	deviceCodes []string
+}
+
+func newFakeIssuer(t *testing.T, pollErrors ...string) *fakeIssuer {
+	issuer := &fakeIssuer{pollErrors: pollErrors}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]string{
+			"issuer":                        issuer.server.URL,
+			"authorization_endpoint":        issuer.server.URL + "/auth",
+			"token_endpoint":                issuer.server.URL + "/token",
+			"device_authorization_endpoint": issuer.server.URL + "/device",
+			"jwks_uri":                      issuer.server.URL + "/keys",
+		})
+	})
+	mux.HandleFunc("/device", func(w http.ResponseWriter, r *http.Request) {
+		assert.NilError(t, r.ParseForm())
+		assert.Equal(t, r.Form.Get("client_id"), "runai-cli")
+		assert.Equal(t, r.Form.Get("scope"), "email openid offline_access")
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"device_code":               "device-code",
+			"user_code":                 "ABCD-EFGH",
+			"verification_uri":          issuer.server.URL + "/activate",
+			"verification_uri_complete": issuer.server.URL + "/activate?user_code=ABCD-EFGH",
+			"expires_in":                600,
+			"interval":                  1,
+		})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		assert.NilError(t, r.ParseForm())
+		assert.Equal(t, r.Form.Get("grant_type"), deviceCodeGrantType)
+		issuer.deviceCodes = append(issuer.deviceCodes, r.Form.Get("device_code"))
+		issuer.tokenPolls++
+		if issuer.tokenPolls <= len(issuer.pollErrors) {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": issuer.pollErrors[issuer.tokenPolls-1]})
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"access_token":  "access-token",
+			"token_type":    "Bearer",
+			"expires_in":    3600,
+			"id_token":      "id-token",
+			"refresh_token": "refresh-token",
+		})
+	})
+	issuer.server = httptest.NewServer(mux)
+	return issuer
+}
+
+func writeJSON(w http.ResponseWriter, statusCode int, content interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(content)
+}
+
+func newTestFlow(sleeps *[]time.Duration) (*deviceCodeFlow, *bytes.Buffer) {
+	out := &bytes.Buffer{}
+	return &deviceCodeFlow{
+		out:   out,
+		sleep: func(d time.Duration) { *sleeps = append(*sleeps, d) },
+		now:   time.Now,
+	}, out
+}
+
+func TestDeviceCodeFlow(t *testing.T) {
+	issuer := newFakeIssuer(t, authorizationPendingError, slowDownError)
+	defer issuer.server.Close()
+	sleeps := []time.Duration{}
+	flow, out := newTestFlow(&sleeps)
+
+	token, err := flow.authenticate(context.Background(), &types.AuthenticationParams{IssuerURL: issuer.server.URL, ClientId: "runai-cli"})
+
+	assert.NilError(t, err)
+	assert.Equal(t, token.RefreshToken, "refresh-token")
+	assert.Equal(t, token.Extra(kubeconfig.IdTokenRawTokenName).(string), "id-token")
+	assert.DeepEqual(t, issuer.deviceCodes, []string{"device-code", "device-code", "device-code"})
+	// The identity provider asked to slow down after the second poll
+	assert.DeepEqual(t, sleeps, []time.Duration{time.Second, time.Second, 6 * time.Second})
+	assert.Assert(t, bytes.Contains(out.Bytes(), []byte("ABCD-EFGH")))
+}
+
+func TestDeviceCodeFlowDenied(t *testing.T) {
+	issuer := newFakeIssuer(t, authorizationPendingError, accessDeniedError)
+	defer issuer.server.Close()
+	sleeps := []time.Duration{}
+	flow, _ := newTestFlow(&sleeps)
+
+	_, err := flow.authenticate(context.Background(), &types.AuthenticationParams{IssuerURL: issuer.server.URL, ClientId: "runai-cli"})
+
+	assert.ErrorContains(t, err, "the login was denied")
+}
+
+func TestDeviceCodeFlowExpired(t *testing.T) {
+	issuer := newFakeIssuer(t, expiredTokenError)
+	defer issuer.server.Close()
+	sleeps := []time.Duration{}
+	flow, _ := newTestFlow(&sleeps)
+
+	_, err := flow.authenticate(context.Background(), &types.AuthenticationParams{IssuerURL: issuer.server.URL, ClientId: "runai-cli"})
+
+	assert.ErrorContains(t, err, "the code expired")
+}
+
+func TestDeviceCodeFlowNotSupported(t *testing.T) {
+	issuer := httptest.NewServer(nil)
+	defer issuer.Close()
+	issuer.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]string{
+			"issuer":         issuer.URL,
+			"token_endpoint": issuer.URL + "/token",
+		})
+	})
+	sleeps := []time.Duration{}
+	flow, _ := newTestFlow(&sleeps)
+
+	_, err := flow.authenticate(context.Background(), &types.AuthenticationParams{IssuerURL: issuer.URL, ClientId: "runai-cli"})
+
+	assert.ErrorContains(t, err, "does not support the device authorization flow")
+}
@@ -6,14 +6,36 @@ import (
 	"encoding/json"
 	"fmt"
 	"strings"
+	"time"
 )
 
 // Can be potentially expanded to deserialize any field from the token.
 type Token struct {
-	Subject string `json:"sub,omitempty"`
-	Email   string `json:"email,omitempty"`
-	Uid     string `json:"uid,omitempty"`
-	Gid     string `json:"gid,omitempty"`
+	Subject  string   `json:"sub,omitempty"`
+	Email    string   `json:"email,omitempty"`
+	Name     string   `json:"name,omitempty"`
+	Uid      string   `json:"uid,omitempty"`
+	Gid      string   `json:"gid,omitempty"`
+	Groups   []string `json:"groups,omitempty"`
+	Issuer   string   `json:"iss,omitempty"`
+	IssuedAt int64    `json:"iat,omitempty"`
+	Expiry   int64    `json:"exp,omitempty"`
+}
+
+// IssuedAtTime returns when the token was issued, or the zero time if it is unknown
+func (token Token) IssuedAtTime() time.Time {
+	if token.IssuedAt == 0 {
+		return time.Time{}
+	}
+	return time.Unix(token.IssuedAt, 0)
+}
+
+// ExpiresAt returns when the token expires, or the zero time if it has no expiry
+func (token Token) ExpiresAt() time.Time {
+	if token.Expiry == 0 {
+		return time.Time{}
+	}
+	return time.Unix(token.Expiry, 0)
 }
 
 // Decode does not verify signatures!! it is used for viewing purposes only
@@ -20,8 +20,13 @@ const (
 	authenticationFlowFieldName = "auth-flow"
 	auth0RealmFieldName         = "realm"
 	redirectUriFieldName        = "redirect-uri"
+
+	execCredentialAPIVersion = "client.authentication.k8s.io/v1beta1"
 )
 
+// The arguments of runai which make it an exec credential plugin of kubectl
+var execCredentialCommandArgs = []string{"auth", "exec-credential"}
+
 func GetCurrentUserIdToken() (string, error) {
 	kubeConfig, err := readKubeConfig()
 	if err != nil {
@@ -77,6 +82,19 @@ func GetCurrentContextDefaultNamespace() (string, error) {
 	return kubeConfig.Contexts[kubeConfig.CurrentContext].Namespace, nil
 }
 
+// GetCurrentUser returns the name of the user of the current context
+func GetCurrentUser() (string, error) {
+	kubeConfig, err := readKubeConfig()
+	if err != nil {
+		return "", err
+	}
+	currentContext, exists := kubeConfig.Contexts[kubeConfig.CurrentContext]
+	if !exists {
+		return "", getInvalidKubeConfigError("current context does not exists")
+	}
+	return currentContext.AuthInfo, nil
+}
+
 func GetCurrentUserAuthenticationParams() (*types.AuthenticationParams, error) {
 	kubeConfig, err := readKubeConfig()
 	if err != nil {
@@ -109,6 +127,28 @@ func SetTokenToUser(user, authenticationFlow string, token *oauth2.Token) error
 	return setTokenToUser(user, authenticationFlow, token, kubeConfig)
 }
 
+// SetExecCredentialToUser makes the user run 'runai auth exec-credential' to get its tokens, instead of keeping them in
+// kubeconfig. The command is the path of the runai executable
+func SetExecCredentialToUser(user, command string, params *types.AuthenticationParams) error {
+	kubeConfig, err := readKubeConfig()
+	if err != nil {
+		return err
+	}
+	kubeConfigUser, exists := kubeConfig.AuthInfos[user]
+	if !exists {
+		return fmt.Errorf("user %v does not exists in kubeconfig", user)
+	}
+
+	// kubectl does not allow a user to have both an auth provider and an exec plugin
+	kubeConfigUser.AuthProvider = nil
+	kubeConfigUser.Exec = &api.ExecConfig{
+		APIVersion: execCredentialAPIVersion,
+		Command:    command,
+		Args:       append(append([]string{}, execCredentialCommandArgs...), params.ExecCredentialArgs()...),
+	}
+	return writeKubeConfig(kubeConfig)
+}
+
 func DeleteTokenToCurrentUser() error {
 	kubeConfig, err := readKubeConfig()
 	if err != nil {
@@ -133,6 +173,15 @@ func getUserAuthenticationParams(user string, kubeConfig *api.Config) (*types.Au
 	if len(kubeConfigUser.ClientCertificateData) != 0 {
 		return nil, fmt.Errorf("you currently connected with certificate. Login aborted")
 	}
+	if isExecCredentialUser(kubeConfigUser) {
+		params, err := types.ParseExecCredentialArgs(kubeConfigUser.Exec.Args[len(execCredentialCommandArgs):])
+		if err != nil {
+			return nil, getInvalidKubeConfigError(fmt.Sprintf("invalid exec credential arguments: %v", err))
+		}
+		params.User = user
+		params.UseExecCredential = true
+		return params, nil
+	}
 	if kubeConfigUser.AuthProvider == nil {
 		return &types.AuthenticationParams{}, nil
 	}
@@ -177,6 +226,10 @@ func deleteTokenToUser(user string, kubeConfig *api.Config) error {
 	if !exists {
 		return fmt.Errorf("user %v does not exists in kubeconfig", user)
 	}
+	if isExecCredentialUser(kubeConfigUser) {
+		// The tokens are not in kubeconfig, but in the token cache
+		return nil
+	}
 	if kubeConfigUser.AuthProvider == nil {
 		return fmt.Errorf("User does not authenticated")
 	}
@@ -190,6 +243,18 @@ func deleteTokenToUser(user string, kubeConfig *api.Config) error {
 	return writeKubeConfig(kubeConfig)
 }
 
+func isExecCredentialUser(kubeConfigUser *api.AuthInfo) bool {
+	if kubeConfigUser.Exec == nil || len(kubeConfigUser.Exec.Args) < len(execCredentialCommandArgs) {
+		return false
+	}
+	for i, arg := range execCredentialCommandArgs {
+		if kubeConfigUser.Exec.Args[i] != arg {
+			return false
+		}
+	}
+	return true
+}
+
 func readKubeConfig() (*api.Config, error) {
 	configAccess := clientcmd.DefaultClientConfig.ConfigAccess()
 	kubeConfig, err := configAccess.GetStartingConfig()
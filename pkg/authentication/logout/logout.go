@@ -27,13 +27,20 @@ func Logout(user string) error {
 	}
 	log.Debug("Tokens deleted")
 
-	var emptyParams types.AuthenticationParams
-	params, err := authentication.GetFinalAuthenticationParams(&emptyParams)
+	userParams := types.AuthenticationParams{User: user}
+	params, err := authentication.GetFinalAuthenticationParams(&userParams)
 	if err != nil {
 		return err
 	}
 	log.Debugf("Final authentication params: %v", params)
 
+	if params.UseExecCredential {
+		if err = authentication.DeleteExecCredentialTokens(params); err != nil {
+			return err
+		}
+		log.Debug("Cached tokens deleted")
+	}
+
 	switch params.AuthenticationFlow {
 	case types.CodePkceBrowser:
 		err = logoutUserSSOCookie(params)
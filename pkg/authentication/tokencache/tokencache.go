@@ -0,0 +1,98 @@
+package tokencache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/run-ai/runai-cli/pkg/util"
+)
+
+const cacheDirName = "tokens"
+
+// Tokens are the tokens of a user, as returned by the identity provider
+type Tokens struct {
+	IdToken      string `json:"id_token"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+}
+
+// Cache stores the tokens of the users which log in with the exec credential plugin, one file for every user, so
+// kubectl and runai use the same tokens
+type Cache struct {
+	dir string
+}
+
+func NewCache() (*Cache, error) {
+	configDir, err := util.GetRunaiConfigDir()
+	if err != nil {
+		return nil, err
+	}
+	return NewCacheInDir(filepath.Join(configDir, cacheDirName)), nil
+}
+
+func NewCacheInDir(dir string) *Cache {
+	return &Cache{dir: dir}
+}
+
+// Key identifies the tokens of a kubeconfig user at an identity provider
+func Key(user, issuerURL, clientId string) string {
+	hash := sha256.Sum256([]byte(user + "\n" + issuerURL + "\n" + clientId))
+	return hex.EncodeToString(hash[:16])
+}
+
+// Load returns the cached tokens, or nil if there are none
+func (c *Cache) Load(key string) (*Tokens, error) {
+	content, err := ioutil.ReadFile(c.path(key))
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	tokens := &Tokens{}
+	if err := json.Unmarshal(content, tokens); err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
+
+// Save replaces the cached tokens. The file is readable only by the user, and is replaced at once so a concurrent
+// kubectl never reads half of it
+func (c *Cache) Save(key string, tokens *Tokens) error {
+	if err := os.MkdirAll(c.dir, 0700); err != nil {
+		return err
+	}
+	content, err := json.Marshal(tokens)
+	if err != nil {
+		return err
+	}
+
+	tempFile, err := ioutil.TempFile(c.dir, key+"-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tempFile.Name())
+	_, err = tempFile.Write(content)
+	if closeErr := tempFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	return os.Rename(tempFile.Name(), c.path(key))
+}
+
+func (c *Cache) Delete(key string) error {
+	err := os.Remove(c.path(key))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+func (c *Cache) path(key string) string {
+	return filepath.Join(c.dir, key+".json")
+}
@@ -8,6 +8,7 @@ import (
 const (
 	CodePkceBrowser           = "browser"
 	CodePkceRemoteBrowser	  = "remote-browser"
+	DeviceCode                = "device-code"
 	Auth0PasswordRealm        = "cli"
 	defaultRedirectServer     = "localhost:8000"
 	defaultAirgappedFlag      = false
@@ -23,6 +24,10 @@ type AuthenticationParams struct {
 	AuthenticationFlow string
 	User               string
 	IsAirgapped        *bool
+
+	// Whether the user is written in kubeconfig as an exec credential plugin, which gets the tokens from the cache of
+	// runai, rather than with the tokens themselves
+	UseExecCredential bool
 }
 
 func (a *AuthenticationParams) GetRedirectUrl() string {
@@ -48,6 +53,7 @@ func (a *AuthenticationParams) MergeAuthenticationParams(patch *AuthenticationPa
 	if a.IsAirgapped == nil {
 		a.IsAirgapped = patch.IsAirgapped
 	}
+	a.UseExecCredential = a.UseExecCredential || patch.UseExecCredential
 	return a
 }
 
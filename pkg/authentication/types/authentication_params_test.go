@@ -94,3 +94,19 @@ func TestValidateAndSetDefaultAuthenticationParams_noClientId(t *testing.T) {
 		t.FailNow()
 	}
 }
+
+func TestExecCredentialArgs(t *testing.T) {
+	airgapped := true
+	params := &AuthenticationParams{
+		ClientId:           "runai-cli",
+		IssuerURL:          "https://runai.example.com/auth/realms/runai",
+		AuthenticationFlow: CodePkceRemoteBrowser,
+		User:               "runai-authenticated-user",
+		IsAirgapped:        &airgapped,
+	}
+
+	result, err := ParseExecCredentialArgs(params.ExecCredentialArgs())
+
+	assert.NilError(t, err)
+	assert.DeepEqual(t, result, params)
+}
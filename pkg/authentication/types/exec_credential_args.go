@@ -0,0 +1,68 @@
+package types
+
+import (
+	"io/ioutil"
+	"strconv"
+
+	"github.com/run-ai/runai-cli/cmd/util"
+	"github.com/spf13/pflag"
+)
+
+const (
+	clientIdFlag           = "client-id"
+	issuerUrlFlag          = "idp-issuer-url"
+	authenticationFlowFlag = "auth-flow"
+	auth0RealmFlag         = "realm"
+	airgappedFlag          = "airgapped"
+	redirectServerFlag     = "redirect-server"
+	userFlag               = "user"
+)
+
+// AddExecCredentialFlags adds the flags of 'runai auth exec-credential', which identify the user and its identity provider
+func (a *AuthenticationParams) AddExecCredentialFlags(flagSet *pflag.FlagSet) {
+	if a.IsAirgapped == nil {
+		a.IsAirgapped = new(bool)
+	}
+	flagSet.StringVar(&a.ClientId, clientIdFlag, "", "Client id to connect")
+	flagSet.StringVar(&a.IssuerURL, issuerUrlFlag, "", "issuer url")
+	flagSet.StringVar(&a.AuthenticationFlow, authenticationFlowFlag, "", "the authentication flow the user logged in with")
+	flagSet.StringVar(&a.Auth0Realm, auth0RealmFlag, "", "auth0 realm")
+	flagSet.BoolVar(a.IsAirgapped, airgappedFlag, false, "whether the identity provider is keycloak")
+	flagSet.StringVar(&a.ListenAddress, redirectServerFlag, "", "listen address")
+	flagSet.StringVar(&a.User, userFlag, "", "the kubeconfig user the credentials are of")
+}
+
+// ExecCredentialArgs returns the flags of 'runai auth exec-credential' for the params, to be written in kubeconfig
+func (a *AuthenticationParams) ExecCredentialArgs() []string {
+	args := []string{
+		"--" + clientIdFlag + "=" + a.ClientId,
+		"--" + issuerUrlFlag + "=" + a.IssuerURL,
+		"--" + userFlag + "=" + a.User,
+	}
+	if a.AuthenticationFlow != "" {
+		args = append(args, "--"+authenticationFlowFlag+"="+a.AuthenticationFlow)
+	}
+	if a.Auth0Realm != "" {
+		args = append(args, "--"+auth0RealmFlag+"="+a.Auth0Realm)
+	}
+	if util.IsBoolPTrue(a.IsAirgapped) {
+		args = append(args, "--"+airgappedFlag+"="+strconv.FormatBool(true))
+	}
+	if a.ListenAddress != "" {
+		args = append(args, "--"+redirectServerFlag+"="+a.ListenAddress)
+	}
+	return args
+}
+
+// ParseExecCredentialArgs returns the params written in kubeconfig by ExecCredentialArgs
+func ParseExecCredentialArgs(args []string) (*AuthenticationParams, error) {
+	params := &AuthenticationParams{}
+	flagSet := pflag.NewFlagSet("exec-credential", pflag.ContinueOnError)
+	flagSet.ParseErrorsWhitelist.UnknownFlags = true
+	flagSet.SetOutput(ioutil.Discard)
+	params.AddExecCredentialFlags(flagSet)
+	if err := flagSet.Parse(args); err != nil {
+		return nil, err
+	}
+	return params, nil
+}
@@ -0,0 +1,49 @@
+package nodes
+
+import (
+	"math"
+	"time"
+
+	"github.com/run-ai/runai-cli/cmd/util"
+	v1 "k8s.io/api/core/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+)
+
+// GetGPUsIdleTimeByPod returns how long the GPUs held by every running pod of the node have been idle, by the UID of
+// the pod. A pod which holds a fraction of a GPU is matched to the GPU by its annotation. As Kubernetes does not tell
+// which GPUs of the node a pod with whole GPUs holds, such a pod is only as idle as the least idle whole GPU of the node
+func (ni *NodeInfo) GetGPUsIdleTimeByPod() map[k8stypes.UID]time.Duration {
+	idleTimeByPod := map[k8stypes.UID]time.Duration{}
+	gpus := ni.GetResourcesStatus().NodeGPUs
+	if len(gpus) == 0 {
+		return idleTimeByPod
+	}
+
+	sharedGPUs := util.GetSharedGPUsIndexUsedInPods(ni.Pods)
+	idleTimeByGPU := map[string]float64{}
+	wholeGPUsIdleTime := math.Inf(1)
+	for _, gpu := range gpus {
+		idleTimeByGPU[gpu.IndexID] = gpu.IdleTime
+		if _, isShared := sharedGPUs[gpu.IndexID]; !isShared && gpu.Allocated > 0 {
+			wholeGPUsIdleTime = math.Min(wholeGPUsIdleTime, gpu.IdleTime)
+		}
+	}
+
+	for _, pod := range ni.Pods {
+		if pod.Status.Phase != v1.PodRunning {
+			continue
+		}
+		if gpuIndex, isShared := pod.Annotations[util.RunaiGPUIndex]; isShared {
+			if idleTime, found := idleTimeByGPU[gpuIndex]; found {
+				idleTimeByPod[pod.UID] = secondsToDuration(idleTime)
+			}
+		} else if util.GpuInPod(pod) > 0 && !math.IsInf(wholeGPUsIdleTime, 1) {
+			idleTimeByPod[pod.UID] = secondsToDuration(wholeGPUsIdleTime)
+		}
+	}
+	return idleTimeByPod
+}
+
+func secondsToDuration(seconds float64) time.Duration {
+	return time.Duration(math.Max(seconds, 0) * float64(time.Second)).Round(time.Second)
+}
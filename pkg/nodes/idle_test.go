@@ -0,0 +1,67 @@
+package nodes
+
+import (
+	"testing"
+	"time"
+
+	"github.com/run-ai/runai-cli/cmd/util"
+	prom "github.com/run-ai/runai-cli/pkg/prometheus"
+	"gotest.tools/assert"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+)
+
+func newTestGPUMetrics(valuesByGPU map[string]string) *[]prom.MetricResult {
+	metrics := []prom.MetricResult{}
+	for gpu, value := range valuesByGPU {
+		metrics = append(metrics, prom.MetricResult{
+			Metric: map[string]string{"node": "node-1", "gpu": gpu},
+			Value:  []prom.MetricValue{1598918400.0, value},
+		})
+	}
+	return &metrics
+}
+
+func newTestPod(uid string, phase v1.PodPhase, gpus int64, annotations map[string]string) v1.Pod {
+	container := v1.Container{Name: "main"}
+	if gpus > 0 {
+		container.Resources.Limits = v1.ResourceList{util.NVIDIAGPUResourceName: *resource.NewQuantity(gpus, resource.DecimalSI)}
+	}
+	return v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: uid, UID: k8stypes.UID(uid), Annotations: annotations},
+		Spec:       v1.PodSpec{NodeName: "node-1", Containers: []v1.Container{container}},
+		Status:     v1.PodStatus{Phase: phase},
+	}
+}
+
+func TestGetGPUsIdleTimeByPod(t *testing.T) {
+	nodeInfo := NodeInfo{
+		Node: v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}},
+		Pods: []v1.Pod{
+			newTestPod("whole", v1.PodRunning, 2, nil),
+			newTestPod("shared", v1.PodRunning, 0, map[string]string{util.RunaiGPUIndex: "2", util.RunaiGPUFraction: "0.5"}),
+			newTestPod("pending", v1.PodPending, 1, nil),
+			newTestPod("cpu", v1.PodRunning, 0, nil),
+		},
+		PrometheusData: prom.MetricResultsByQueryName{
+			GpuIdleTimePQ: newTestGPUMetrics(map[string]string{"0": "7200", "1": "600", "2": "3600", "3": "9000"}),
+			GpuUsedByPod:  newTestGPUMetrics(map[string]string{"0": "100", "1": "100", "2": "0", "3": "0"}),
+		},
+	}
+
+	assert.DeepEqual(t, nodeInfo.GetGPUsIdleTimeByPod(), map[k8stypes.UID]time.Duration{
+		"whole":  10 * time.Minute,
+		"shared": time.Hour,
+	})
+}
+
+func TestGetGPUsIdleTimeByPodWithoutMetrics(t *testing.T) {
+	nodeInfo := NodeInfo{
+		Node: v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}},
+		Pods: []v1.Pod{newTestPod("whole", v1.PodRunning, 1, nil)},
+	}
+
+	assert.Equal(t, len(nodeInfo.GetGPUsIdleTimeByPod()), 0)
+}
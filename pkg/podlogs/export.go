@@ -0,0 +1,251 @@
+package podlogs
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+// LogExporter saves the logs of all the containers of the pods of a job, so they can be attached to a bug report.
+// Every container has its own file, which starts with the metadata of its pod, and the logs of the previous instance
+// of a container which restarted are saved as well
+type LogExporter struct {
+	Args *PodLogArgs
+
+	openStream func(podName string, options *v1.PodLogOptions) (io.ReadCloser, error)
+	now        func() time.Time
+}
+
+// logArchive is where the log files are exported to, a directory or a tar.gz file
+type logArchive interface {
+	add(name string, content io.Reader) error
+	close() error
+}
+
+func NewLogExporter(args *OuterRequestArgs) (*LogExporter, error) {
+	podLogArgs, err := checkAndTransferArgs(args)
+	if err != nil {
+		return nil, err
+	}
+	// The exported logs are complete, and every line has its time so the logs of the pods can be correlated
+	podLogArgs.Follow = false
+	podLogArgs.Timestamps = true
+
+	e := &LogExporter{
+		Args: podLogArgs,
+		now:  time.Now,
+	}
+	e.openStream = func(podName string, options *v1.PodLogOptions) (io.ReadCloser, error) {
+		return e.Args.KubeClient.CoreV1().Pods(e.Args.Namespace).GetLogs(podName, options).Stream()
+	}
+	return e, nil
+}
+
+// IsArchivePath returns whether the logs are exported to a tar.gz file rather than to a directory
+func IsArchivePath(path string) bool {
+	return strings.HasSuffix(path, ".tar.gz") || strings.HasSuffix(path, ".tgz")
+}
+
+// Export saves the logs of the pods to the path, and returns the names of the exported files
+func (e *LogExporter) Export(pods []v1.Pod, path string) ([]string, error) {
+	var archive logArchive
+	var err error
+	if IsArchivePath(path) {
+		archive, err = newTarGzArchive(path)
+	} else {
+		archive, err = newDirectoryArchive(path)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	exported, err := e.exportPods(pods, archive)
+	closeErr := archive.close()
+	if err != nil {
+		return exported, err
+	}
+	return exported, closeErr
+}
+
+func (e *LogExporter) exportPods(pods []v1.Pod, archive logArchive) ([]string, error) {
+	exported := []string{}
+	for _, pod := range pods {
+		for _, container := range pod.Spec.Containers {
+			if e.Args.Container != "" && container.Name != e.Args.Container {
+				continue
+			}
+
+			status := getContainerStatus(pod, container.Name)
+			instances := []bool{}
+			if containerLogsAvailable(status, false) {
+				instances = append(instances, false)
+			}
+			if containerLogsAvailable(status, true) {
+				instances = append(instances, true)
+			}
+
+			for _, previous := range instances {
+				name := fmt.Sprintf("%s/%s.log", pod.Name, container.Name)
+				if previous {
+					name = fmt.Sprintf("%s/%s.previous.log", pod.Name, container.Name)
+				}
+				if err := e.exportContainer(pod, container.Name, previous, name, archive); err != nil {
+					return exported, fmt.Errorf("failed to export the logs of %s/%s: %v", pod.Name, container.Name, err)
+				}
+				exported = append(exported, name)
+			}
+		}
+	}
+	return exported, nil
+}
+
+func (e *LogExporter) exportContainer(pod v1.Pod, containerName string, previous bool, name string, archive logArchive) error {
+	options := e.Args.logOptions(containerName)
+	options.Previous = previous
+	reader, err := e.openStream(pod.Name, options)
+	if err != nil {
+		return err
+	}
+	reader = e.Args.filter(reader)
+	defer reader.Close()
+
+	header := e.logFileHeader(pod, containerName, previous)
+	return archive.add(name, io.MultiReader(strings.NewReader(header), reader))
+}
+
+// logFileHeader describes where the logs of a file came from, as comment lines at the top of the file
+func (e *LogExporter) logFileHeader(pod v1.Pod, containerName string, previous bool) string {
+	fields := [][2]string{
+		{"Pod", pod.Name},
+		{"Namespace", pod.Namespace},
+		{"Node", pod.Spec.NodeName},
+		{"Container", containerName},
+		{"Phase", string(pod.Status.Phase)},
+	}
+	if pod.Status.StartTime != nil {
+		fields = append(fields, [2]string{"Started", pod.Status.StartTime.UTC().Format(time.RFC3339)})
+	}
+	if status := getContainerStatus(pod, containerName); status != nil {
+		fields = append(fields, [2]string{"Restarts", fmt.Sprintf("%d", status.RestartCount)})
+	}
+	if previous {
+		fields = append(fields, [2]string{"Instance", "previous"})
+	}
+	if e.Args.SinceTime != nil {
+		fields = append(fields, [2]string{"Since", e.Args.SinceTime.UTC().Format(time.RFC3339)})
+	} else if e.Args.SinceSeconds != nil {
+		fields = append(fields, [2]string{"Since", (time.Duration(*e.Args.SinceSeconds) * time.Second).String() + " before export"})
+	}
+	if e.Args.Grep != nil {
+		fields = append(fields, [2]string{"Grep", e.Args.Grep.String()})
+	}
+	fields = append(fields, [2]string{"Exported", e.now().UTC().Format(time.RFC3339)})
+
+	var header strings.Builder
+	for _, field := range fields {
+		fmt.Fprintf(&header, "# %s: %s\n", field[0], field[1])
+	}
+	header.WriteString("\n")
+	return header.String()
+}
+
+type directoryArchive struct {
+	path string
+}
+
+func newDirectoryArchive(path string) (*directoryArchive, error) {
+	if err := os.MkdirAll(path, 0755); err != nil {
+		return nil, err
+	}
+	return &directoryArchive{path: path}, nil
+}
+
+func (a *directoryArchive) add(name string, content io.Reader) error {
+	path := filepath.Join(a.path, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(file, content)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+func (a *directoryArchive) close() error {
+	return nil
+}
+
+type tarGzArchive struct {
+	file       *os.File
+	gzipWriter *gzip.Writer
+	tarWriter  *tar.Writer
+	modTime    time.Time
+}
+
+func newTarGzArchive(path string) (*tarGzArchive, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	gzipWriter := gzip.NewWriter(file)
+	return &tarGzArchive{
+		file:       file,
+		gzipWriter: gzipWriter,
+		tarWriter:  tar.NewWriter(gzipWriter),
+		modTime:    time.Now(),
+	}, nil
+}
+
+// add saves the content to a temporary file first, since the size of a file in a tar must be known before its content
+func (a *tarGzArchive) add(name string, content io.Reader) error {
+	tempFile, err := ioutil.TempFile("", "runai-logs-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tempFile.Name())
+	defer tempFile.Close()
+
+	size, err := io.Copy(tempFile, content)
+	if err != nil {
+		return err
+	}
+	if _, err = tempFile.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	err = a.tarWriter.WriteHeader(&tar.Header{
+		Name:    name,
+		Mode:    0644,
+		Size:    size,
+		ModTime: a.modTime,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(a.tarWriter, tempFile)
+	return err
+}
+
+func (a *tarGzArchive) close() error {
+	err := a.tarWriter.Close()
+	if gzipErr := a.gzipWriter.Close(); err == nil {
+		err = gzipErr
+	}
+	if fileErr := a.file.Close(); err == nil {
+		err = fileErr
+	}
+	return err
+}
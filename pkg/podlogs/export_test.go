@@ -0,0 +1,103 @@
+package podlogs
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/magiconair/properties/assert"
+	v1 "k8s.io/api/core/v1"
+)
+
+func newTestLogExporter(t *testing.T, args *OuterRequestArgs) *LogExporter {
+	exporter, err := NewLogExporter(args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	exporter.now = func() time.Time { return time.Date(2020, 11, 1, 10, 0, 0, 0, time.UTC) }
+	exporter.openStream = func(podName string, options *v1.PodLogOptions) (io.ReadCloser, error) {
+		assert.Equal(t, options.Timestamps, true)
+		assert.Equal(t, options.Follow, false)
+		instance := "current"
+		if options.Previous {
+			instance = "previous"
+		}
+		return ioutil.NopCloser(strings.NewReader(fmt.Sprintf("2020-11-01T09:00:00Z %s %s\n", options.Container, instance))), nil
+	}
+	return exporter
+}
+
+func newTestExportedPods() []v1.Pod {
+	restarted := newTestPod("job-0", v1.PodRunning, "main")
+	restarted.Namespace = "runai-team"
+	restarted.Spec.NodeName = "node-a"
+	restarted.Status.ContainerStatuses[0].RestartCount = 1
+	restarted.Status.ContainerStatuses[0].LastTerminationState.Terminated = &v1.ContainerStateTerminated{ExitCode: 137}
+	return []v1.Pod{restarted, newTestPod("job-1", v1.PodPending, "main")}
+}
+
+func TestExportLogsToDirectory(t *testing.T) {
+	directory, _ := ioutil.TempDir("", "runai-logs-test")
+	defer os.RemoveAll(directory)
+
+	exported, err := newTestLogExporter(t, &OuterRequestArgs{Follow: true}).Export(newTestExportedPods(), directory)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, exported, []string{"job-0/main.log", "job-0/main.previous.log"})
+
+	content, _ := ioutil.ReadFile(filepath.Join(directory, "job-0", "main.previous.log"))
+	assert.Equal(t, string(content), `# Pod: job-0
+# Namespace: runai-team
+# Node: node-a
+# Container: main
+# Phase: Running
+# Restarts: 1
+# Instance: previous
+# Exported: 2020-11-01T10:00:00Z
+
+2020-11-01T09:00:00Z main previous
+`)
+}
+
+func TestExportLogsToTarGz(t *testing.T) {
+	directory, _ := ioutil.TempDir("", "runai-logs-test")
+	defer os.RemoveAll(directory)
+	path := filepath.Join(directory, "logs.tar.gz")
+
+	exported, err := newTestLogExporter(t, &OuterRequestArgs{Grep: "current"}).Export(newTestExportedPods(), path)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, len(exported), 2)
+
+	file, _ := os.Open(path)
+	defer file.Close()
+	gzipReader, err := gzip.NewReader(file)
+	assert.Equal(t, err, nil)
+	tarReader := tar.NewReader(gzipReader)
+
+	contents := map[string]string{}
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		assert.Equal(t, err, nil)
+		content, _ := ioutil.ReadAll(tarReader)
+		contents[header.Name] = string(content)
+	}
+
+	names := []string{}
+	for name := range contents {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	assert.Equal(t, names, []string{"job-0/main.log", "job-0/main.previous.log"})
+	assert.Equal(t, strings.HasSuffix(contents["job-0/main.log"], "# Grep: current\n# Exported: 2020-11-01T10:00:00Z\n\n2020-11-01T09:00:00Z main current\n"), true)
+	assert.Equal(t, strings.HasSuffix(contents["job-0/main.previous.log"], "\n\n"), true)
+}
@@ -0,0 +1,93 @@
+package podlogs
+
+import (
+	"bufio"
+	"io"
+	"regexp"
+)
+
+// The separator between groups of lines which are not adjacent in the logs, the same as grep prints
+const grepGroupSeparator = "--\n"
+
+// lineGrep selects the lines which match a pattern, along with the lines of context around them
+type lineGrep struct {
+	pattern *regexp.Regexp
+	context int
+
+	before  []string
+	after   int
+	printed bool
+	skipped bool
+}
+
+func (g *lineGrep) filter(line string) []string {
+	if g.pattern.MatchString(line) {
+		lines := []string{}
+		if g.printed && g.skipped && g.context > 0 {
+			lines = append(lines, grepGroupSeparator)
+		}
+		lines = append(append(lines, g.before...), line)
+		g.before = nil
+		g.after = g.context
+		g.printed = true
+		g.skipped = false
+		return lines
+	}
+
+	if g.after > 0 {
+		g.after--
+		return []string{line}
+	}
+
+	g.before = append(g.before, line)
+	if len(g.before) > g.context {
+		g.before = g.before[1:]
+		g.skipped = true
+	}
+	return nil
+}
+
+// grepReader reads only the lines of a log stream which match a pattern, as they are streamed
+type grepReader struct {
+	lines   *bufio.Reader
+	closer  io.Closer
+	grep    *lineGrep
+	pending []byte
+	err     error
+}
+
+func newGrepReader(reader io.ReadCloser, pattern *regexp.Regexp, context int) io.ReadCloser {
+	return &grepReader{
+		lines:  bufio.NewReader(reader),
+		closer: reader,
+		grep:   &lineGrep{pattern: pattern, context: context},
+	}
+}
+
+func (r *grepReader) Read(p []byte) (int, error) {
+	for len(r.pending) == 0 {
+		if r.err != nil {
+			return 0, r.err
+		}
+
+		var line string
+		line, r.err = r.lines.ReadString('\n')
+		if len(line) == 0 {
+			continue
+		}
+		if line[len(line)-1] != '\n' {
+			line += "\n"
+		}
+		for _, selected := range r.grep.filter(line) {
+			r.pending = append(r.pending, selected...)
+		}
+	}
+
+	n := copy(p, r.pending)
+	r.pending = r.pending[n:]
+	return n, nil
+}
+
+func (r *grepReader) Close() error {
+	return r.closer.Close()
+}
@@ -0,0 +1,41 @@
+package podlogs
+
+import (
+	"io/ioutil"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/magiconair/properties/assert"
+)
+
+func grepLines(lines []string, pattern string, context int) string {
+	reader := newGrepReader(ioutil.NopCloser(strings.NewReader(strings.Join(lines, "\n"))), regexp.MustCompile(pattern), context)
+	filtered, _ := ioutil.ReadAll(reader)
+	return string(filtered)
+}
+
+func TestGrepWithoutContext(t *testing.T) {
+	lines := []string{"epoch 1", "loss 0.5", "epoch 2", "loss 0.4", "ERROR nan"}
+	assert.Equal(t, grepLines(lines, "loss", 0), "loss 0.5\nloss 0.4\n")
+	assert.Equal(t, grepLines(lines, "^ERROR", 0), "ERROR nan\n")
+	assert.Equal(t, grepLines(lines, "warning", 0), "")
+}
+
+func TestGrepWithContext(t *testing.T) {
+	lines := []string{"1", "2", "match a", "4", "5", "6", "7", "match b", "match c", "10", "11"}
+	assert.Equal(t, grepLines(lines, "match", 1), "2\nmatch a\n4\n--\n7\nmatch b\nmatch c\n10\n")
+
+	// Groups whose context lines touch are not separated
+	assert.Equal(t, grepLines(lines, "match", 2), "1\n2\nmatch a\n4\n5\n6\n7\nmatch b\nmatch c\n10\n11\n")
+}
+
+func TestInvalidGrepPattern(t *testing.T) {
+	_, err := checkAndTransferArgs(&OuterRequestArgs{Grep: "epoch ("})
+	assert.Equal(t, strings.HasPrefix(err.Error(), "invalid --grep pattern"), true)
+}
+
+func TestContextWithoutGrep(t *testing.T) {
+	_, err := checkAndTransferArgs(&OuterRequestArgs{GrepContext: 2})
+	assert.Equal(t, err.Error(), "the flag --context can only be used with --grep")
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"regexp"
 	"time"
 
 	servejob "github.com/run-ai/runai-cli/pkg/jobs/serving"
@@ -21,11 +22,15 @@ var (
 type PodLogArgs struct {
 	Namespace    string
 	PodName      string
+	Container    string
+	Previous     bool
 	Follow       bool
 	SinceSeconds *int64
 	SinceTime    *metav1.Time
 	Tail         *int64
 	Timestamps   bool
+	Grep         *regexp.Regexp
+	GrepContext  int
 	RetryCnt     int
 	RetryTimeout time.Duration
 	KubeClient   kubernetes.Interface
@@ -50,24 +55,42 @@ func (pl *PodLog) GetPodLogEntry(accept func(io.ReadCloser)) error {
 	if err != nil {
 		return err
 	}
-	readCloser, err := pl.Args.KubeClient.CoreV1().Pods(pl.Args.Namespace).GetLogs(pl.Args.PodName, &v1.PodLogOptions{
-		// Container:    p.container,
-		Follow:       pl.Args.Follow,
-		Timestamps:   pl.Args.Timestamps,
-		SinceSeconds: pl.Args.SinceSeconds,
-		SinceTime:    pl.Args.SinceTime,
-		TailLines:    pl.Args.Tail,
-	}).Stream()
+	readCloser, err := pl.Args.KubeClient.CoreV1().Pods(pl.Args.Namespace).GetLogs(pl.Args.PodName, pl.Args.logOptions(pl.Args.Container)).Stream()
 
 	if err != nil {
 		return err
 	}
+	readCloser = pl.Args.filter(readCloser)
 	// warning: readCloser should execute readCloser.Close() in accept function.
 	go accept(readCloser)
 	return nil
 }
 
+func (args *PodLogArgs) logOptions(container string) *v1.PodLogOptions {
+	return &v1.PodLogOptions{
+		Container:    container,
+		Follow:       args.Follow,
+		Previous:     args.Previous,
+		Timestamps:   args.Timestamps,
+		SinceSeconds: args.SinceSeconds,
+		SinceTime:    args.SinceTime,
+		TailLines:    args.Tail,
+	}
+}
+
+// filter returns the lines of the logs which match --grep, or all of them
+func (args *PodLogArgs) filter(reader io.ReadCloser) io.ReadCloser {
+	if args.Grep == nil {
+		return reader
+	}
+	return newGrepReader(reader, args.Grep, args.GrepContext)
+}
+
 func (pl *PodLog) ensureContainerStarted() error {
+	// The logs of a previous instance of a container are available even when its current instance is not running
+	if pl.Args.Previous {
+		return nil
+	}
 	for pl.Args.RetryCnt > 0 {
 		pod, err := pl.Args.KubeClient.CoreV1().Pods(pl.Args.Namespace).Get(pl.Args.PodName, metav1.GetOptions{})
 		if err != nil {
@@ -89,12 +112,25 @@ func (pl *PodLog) ensureContainerStarted() error {
 func checkAndTransferArgs(out *OuterRequestArgs) (*PodLogArgs, error) {
 	podLogArgs := &PodLogArgs{
 		PodName:    out.PodName,
+		Container:  out.Container,
+		Previous:   out.Previous,
 		Namespace:  out.Namespace,
 		KubeClient: out.KubeClient,
 		Follow:     out.Follow,
 		RetryCnt:   out.RetryCount,
 		Timestamps: out.Timestamps,
 	}
+	if out.GrepContext != 0 && out.Grep == "" {
+		return nil, fmt.Errorf("the flag --context can only be used with --grep")
+	}
+	if out.Grep != "" {
+		grep, err := regexp.Compile(out.Grep)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --grep pattern: %v", err)
+		}
+		podLogArgs.Grep = grep
+		podLogArgs.GrepContext = out.GrepContext
+	}
 	if out.Tail > 0 {
 		t := int64(out.Tail)
 		podLogArgs.Tail = &t
@@ -0,0 +1,236 @@
+package podlogs
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	v1 "k8s.io/api/core/v1"
+)
+
+const (
+	// How often the pods of the job are listed while following, to stream the logs of new pods
+	DefaultPodsPollInterval = 2 * time.Second
+
+	resetColor = "\x1b[0m"
+)
+
+// The colors of the prefixes, a different color for every stream, the same as kubectl plugins like stern do
+var prefixColors = []string{"\x1b[32m", "\x1b[33m", "\x1b[34m", "\x1b[35m", "\x1b[36m", "\x1b[92m", "\x1b[93m", "\x1b[94m", "\x1b[95m", "\x1b[96m"}
+
+// MultiPodLog streams the logs of all the containers of all the pods of a job concurrently. Every line is prefixed
+// with the pod it came from, and with the container when the pod has several containers
+type MultiPodLog struct {
+	Args         *PodLogArgs
+	PollInterval time.Duration
+	Color        bool
+
+	listPods   func() ([]v1.Pod, error)
+	openStream func(podName string, options *v1.PodLogOptions) (io.ReadCloser, error)
+
+	out       io.Writer
+	outLock   sync.Mutex
+	streamed  map[string]bool
+	active    int32
+	waitGroup sync.WaitGroup
+	failures  chan error
+}
+
+func NewMultiPodLog(args *OuterRequestArgs, listPods func() ([]v1.Pod, error), out io.Writer) (*MultiPodLog, error) {
+	podLogArgs, err := checkAndTransferArgs(args)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &MultiPodLog{
+		Args:         podLogArgs,
+		PollInterval: DefaultPodsPollInterval,
+		listPods:     listPods,
+		out:          out,
+	}
+	m.openStream = func(podName string, options *v1.PodLogOptions) (io.ReadCloser, error) {
+		return m.Args.KubeClient.CoreV1().Pods(m.Args.Namespace).GetLogs(podName, options).Stream()
+	}
+	return m, nil
+}
+
+// Stream prints the logs of all the pods. When following, new pods and restarted containers are streamed as they
+// appear, until all the pods finished or were deleted
+func (m *MultiPodLog) Stream() error {
+	m.streamed = map[string]bool{}
+	m.failures = make(chan error, 1)
+	foundPods := false
+
+	for {
+		pods, err := m.listPods()
+		if err != nil {
+			return err
+		}
+		if len(pods) == 0 && !m.Args.Follow {
+			return fmt.Errorf("no pods were found for the job")
+		}
+
+		// The containers of the pods do not change, so following a container which none of the pods has would never end
+		if m.Args.Follow && len(pods) > 0 && m.Args.Container != "" && !anyPodHasContainer(pods, m.Args.Container) {
+			return fmt.Errorf("none of the pods of the job has a container named %s", m.Args.Container)
+		}
+
+		sort.Slice(pods, func(i, j int) bool { return pods[i].Name < pods[j].Name })
+		for _, pod := range pods {
+			m.streamPod(pod)
+		}
+
+		if !m.Args.Follow {
+			if len(m.streamed) == 0 && m.Args.Container != "" {
+				return fmt.Errorf("none of the pods of the job has logs of a container named %s", m.Args.Container)
+			} else if len(m.streamed) == 0 {
+				return fmt.Errorf("none of the pods of the job has logs yet")
+			}
+			m.waitGroup.Wait()
+			return m.firstFailure()
+		}
+		if len(pods) > 0 && allPodsFinished(pods) {
+			// A finished pod may still have logs to print
+			m.waitGroup.Wait()
+			return m.firstFailure()
+		}
+		// The pods were deleted, e.g. with the job, and there are no more logs to print
+		if len(pods) == 0 && foundPods && atomic.LoadInt32(&m.active) == 0 {
+			return m.firstFailure()
+		}
+		foundPods = foundPods || len(pods) > 0
+		time.Sleep(m.PollInterval)
+	}
+}
+
+func (m *MultiPodLog) streamPod(pod v1.Pod) {
+	containers := pod.Spec.Containers
+	for _, container := range containers {
+		if m.Args.Container != "" && container.Name != m.Args.Container {
+			continue
+		}
+
+		status := getContainerStatus(pod, container.Name)
+		if !containerLogsAvailable(status, m.Args.Previous) {
+			continue
+		}
+
+		// A container which restarted while following has new logs, which are streamed as a new stream
+		key := fmt.Sprintf("%s/%s", pod.Name, container.Name)
+		if m.Args.Follow && status != nil {
+			key = fmt.Sprintf("%s/%d", key, status.RestartCount)
+		}
+		if m.streamed[key] {
+			continue
+		}
+		m.streamed[key] = true
+
+		prefix := pod.Name
+		if len(containers) > 1 {
+			prefix = fmt.Sprintf("%s/%s", pod.Name, container.Name)
+		}
+		if m.Color {
+			prefix = prefixColors[(len(m.streamed)-1)%len(prefixColors)] + "[" + prefix + "]" + resetColor + " "
+		} else {
+			prefix = "[" + prefix + "] "
+		}
+
+		m.waitGroup.Add(1)
+		atomic.AddInt32(&m.active, 1)
+		go m.streamContainer(pod.Name, container.Name, prefix)
+	}
+}
+
+func (m *MultiPodLog) streamContainer(podName, containerName, prefix string) {
+	defer m.waitGroup.Done()
+	defer atomic.AddInt32(&m.active, -1)
+
+	reader, err := m.openStream(podName, m.Args.logOptions(containerName))
+	if err != nil {
+		m.fail(fmt.Errorf("failed to get the logs of %s/%s: %v", podName, containerName, err))
+		return
+	}
+	reader = m.Args.filter(reader)
+	defer reader.Close()
+
+	lines := bufio.NewReader(reader)
+	for {
+		line, err := lines.ReadString('\n')
+		if len(line) > 0 {
+			if line[len(line)-1] != '\n' {
+				line += "\n"
+			}
+			m.outLock.Lock()
+			_, _ = io.WriteString(m.out, prefix+line)
+			m.outLock.Unlock()
+		}
+		if err == io.EOF {
+			return
+		} else if err != nil {
+			m.fail(fmt.Errorf("failed to read the logs of %s/%s: %v", podName, containerName, err))
+			return
+		}
+	}
+}
+
+// fail keeps the first failure to be returned, the others are only logged, so one pod does not stop the others
+func (m *MultiPodLog) fail(err error) {
+	log.Debug(err)
+	select {
+	case m.failures <- err:
+	default:
+	}
+}
+
+func (m *MultiPodLog) firstFailure() error {
+	select {
+	case err := <-m.failures:
+		return err
+	default:
+		return nil
+	}
+}
+
+func getContainerStatus(pod v1.Pod, containerName string) *v1.ContainerStatus {
+	for i, status := range pod.Status.ContainerStatuses {
+		if status.Name == containerName {
+			return &pod.Status.ContainerStatuses[i]
+		}
+	}
+	return nil
+}
+
+func anyPodHasContainer(pods []v1.Pod, containerName string) bool {
+	for _, pod := range pods {
+		for _, container := range pod.Spec.Containers {
+			if container.Name == containerName {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func containerLogsAvailable(status *v1.ContainerStatus, previous bool) bool {
+	if status == nil {
+		return false
+	}
+	if previous {
+		return status.LastTerminationState.Terminated != nil
+	}
+	return status.State.Running != nil || status.State.Terminated != nil
+}
+
+func allPodsFinished(pods []v1.Pod) bool {
+	for _, pod := range pods {
+		if pod.Status.Phase != v1.PodSucceeded && pod.Status.Phase != v1.PodFailed {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,153 @@
+package podlogs
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/magiconair/properties/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newTestPod(name string, phase v1.PodPhase, containers ...string) v1.Pod {
+	pod := v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Status:     v1.PodStatus{Phase: phase},
+	}
+	for _, container := range containers {
+		pod.Spec.Containers = append(pod.Spec.Containers, v1.Container{Name: container})
+		status := v1.ContainerStatus{Name: container}
+		switch phase {
+		case v1.PodRunning:
+			status.State.Running = &v1.ContainerStateRunning{}
+		case v1.PodSucceeded, v1.PodFailed:
+			status.State.Terminated = &v1.ContainerStateTerminated{}
+		}
+		pod.Status.ContainerStatuses = append(pod.Status.ContainerStatuses, status)
+	}
+	return pod
+}
+
+func newTestMultiPodLog(args *OuterRequestArgs, listPods func() ([]v1.Pod, error)) (*MultiPodLog, *bytes.Buffer) {
+	var out bytes.Buffer
+	multiPodLog, _ := NewMultiPodLog(args, listPods, &out)
+	multiPodLog.PollInterval = time.Millisecond
+	multiPodLog.openStream = func(podName string, options *v1.PodLogOptions) (io.ReadCloser, error) {
+		if options.Previous {
+			return ioutil.NopCloser(strings.NewReader(fmt.Sprintf("%s %s crashed\n", podName, options.Container))), nil
+		}
+		return ioutil.NopCloser(strings.NewReader(fmt.Sprintf("%s %s line 1\n%s %s line 2", podName, options.Container, podName, options.Container))), nil
+	}
+	return multiPodLog, &out
+}
+
+func sortedLines(out *bytes.Buffer) []string {
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	sort.Strings(lines)
+	return lines
+}
+
+func TestMultiPodLogPrefixesLines(t *testing.T) {
+	pods := []v1.Pod{
+		newTestPod("job-worker-0", v1.PodRunning, "main"),
+		newTestPod("job-launcher", v1.PodRunning, "main", "sidecar"),
+		newTestPod("job-worker-1", v1.PodPending, "main"),
+	}
+	multiPodLog, out := newTestMultiPodLog(&OuterRequestArgs{}, func() ([]v1.Pod, error) { return pods, nil })
+
+	err := multiPodLog.Stream()
+	assert.Equal(t, err, nil)
+	assert.Equal(t, sortedLines(out), []string{
+		"[job-launcher/main] job-launcher main line 1",
+		"[job-launcher/main] job-launcher main line 2",
+		"[job-launcher/sidecar] job-launcher sidecar line 1",
+		"[job-launcher/sidecar] job-launcher sidecar line 2",
+		"[job-worker-0] job-worker-0 main line 1",
+		"[job-worker-0] job-worker-0 main line 2",
+	})
+}
+
+func TestMultiPodLogContainerAndPrevious(t *testing.T) {
+	crashed := newTestPod("job-worker-0", v1.PodRunning, "main", "sidecar")
+	crashed.Status.ContainerStatuses[0].LastTerminationState.Terminated = &v1.ContainerStateTerminated{ExitCode: 1}
+	pods := []v1.Pod{crashed, newTestPod("job-worker-1", v1.PodRunning, "main", "sidecar")}
+
+	multiPodLog, out := newTestMultiPodLog(&OuterRequestArgs{Container: "main", Previous: true}, func() ([]v1.Pod, error) { return pods, nil })
+	err := multiPodLog.Stream()
+	assert.Equal(t, err, nil)
+	assert.Equal(t, out.String(), "[job-worker-0/main] job-worker-0 main crashed\n")
+
+	multiPodLog, _ = newTestMultiPodLog(&OuterRequestArgs{Container: "other"}, func() ([]v1.Pod, error) { return pods, nil })
+	err = multiPodLog.Stream()
+	assert.Equal(t, err.Error(), "none of the pods of the job has logs of a container named other")
+}
+
+func TestMultiPodLogFollowsNewPods(t *testing.T) {
+	// Every time the pods are listed, another pod was created, until the job finished
+	listed := 0
+	listPods := func() ([]v1.Pod, error) {
+		listed++
+		switch listed {
+		case 1:
+			return []v1.Pod{}, nil
+		case 2:
+			return []v1.Pod{newTestPod("job-0", v1.PodRunning, "main")}, nil
+		case 3:
+			return []v1.Pod{newTestPod("job-0", v1.PodRunning, "main"), newTestPod("job-1", v1.PodRunning, "main")}, nil
+		}
+		return []v1.Pod{newTestPod("job-0", v1.PodSucceeded, "main"), newTestPod("job-1", v1.PodSucceeded, "main")}, nil
+	}
+
+	multiPodLog, out := newTestMultiPodLog(&OuterRequestArgs{Follow: true}, listPods)
+	err := multiPodLog.Stream()
+	assert.Equal(t, err, nil)
+	assert.Equal(t, listed, 4)
+	assert.Equal(t, sortedLines(out), []string{
+		"[job-0] job-0 main line 1",
+		"[job-0] job-0 main line 2",
+		"[job-1] job-1 main line 1",
+		"[job-1] job-1 main line 2",
+	})
+}
+
+func TestMultiPodLogFollowStopsWhenThePodsAreDeleted(t *testing.T) {
+	listed := 0
+	listPods := func() ([]v1.Pod, error) {
+		listed++
+		if listed == 1 {
+			return []v1.Pod{newTestPod("job-0", v1.PodRunning, "main")}, nil
+		}
+		return []v1.Pod{}, nil
+	}
+
+	multiPodLog, out := newTestMultiPodLog(&OuterRequestArgs{Follow: true}, listPods)
+	err := multiPodLog.Stream()
+	assert.Equal(t, err, nil)
+	assert.Equal(t, sortedLines(out), []string{
+		"[job-0] job-0 main line 1",
+		"[job-0] job-0 main line 2",
+	})
+}
+
+func TestMultiPodLogFollowFailsForAMissingContainer(t *testing.T) {
+	listed := 0
+	listPods := func() ([]v1.Pod, error) {
+		listed++
+		if listed == 1 {
+			return []v1.Pod{}, nil
+		}
+		return []v1.Pod{newTestPod("job-0", v1.PodPending, "main")}, nil
+	}
+
+	multiPodLog, out := newTestMultiPodLog(&OuterRequestArgs{Follow: true, Container: "other"}, listPods)
+	err := multiPodLog.Stream()
+	assert.Equal(t, err.Error(), "none of the pods of the job has a container named other")
+	assert.Equal(t, listed, 2)
+	assert.Equal(t, out.String(), "")
+}
@@ -9,6 +9,8 @@ import (
 
 type OuterRequestArgs struct {
 	PodName      string
+	Container    string
+	Previous     bool
 	Namespace    string
 	Follow       bool
 	Tail         int
@@ -17,6 +19,8 @@ type OuterRequestArgs struct {
 	SinceSeconds time.Duration
 	SinceTime    string
 	Timestamps   bool
+	Grep         string
+	GrepContext  int
 	KubeClient   kubernetes.Interface
 }
 
@@ -0,0 +1,218 @@
+package portforward
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/httpstream"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+const (
+	localAddress = "localhost"
+
+	// reconnectInterval is the time between attempts to reconnect to the pod, e.g. while a preempted job is pending
+	reconnectInterval = 5 * time.Second
+)
+
+// PortMapping is a local port which is forwarded to a port of the pod. A local port 0 is any free port
+type PortMapping struct {
+	Local  int
+	Remote int
+}
+
+// URL is the local URL of the forwarded port
+func (pm PortMapping) URL() string {
+	return fmt.Sprintf("http://%s:%d", localAddress, pm.Local)
+}
+
+// ParsePortMappings parses ports given as LOCAL:REMOTE, :REMOTE for any free local port, or PORT for the same port
+func ParsePortMappings(ports []string) ([]PortMapping, error) {
+	mappings := []PortMapping{}
+	for _, port := range ports {
+		parts := strings.Split(port, ":")
+		if len(parts) > 2 {
+			return nil, fmt.Errorf("invalid port '%s', expected LOCAL:REMOTE or PORT", port)
+		}
+
+		remote, err := strconv.Atoi(parts[len(parts)-1])
+		if err != nil || remote <= 0 || remote > 65535 {
+			return nil, fmt.Errorf("invalid port '%s', expected LOCAL:REMOTE or PORT", port)
+		}
+		local := remote
+		if len(parts) == 2 {
+			if parts[0] == "" {
+				local = 0
+			} else if local, err = strconv.Atoi(parts[0]); err != nil || local < 0 || local > 65535 {
+				return nil, fmt.Errorf("invalid port '%s', expected LOCAL:REMOTE or PORT", port)
+			}
+		}
+		mappings = append(mappings, PortMapping{Local: local, Remote: remote})
+	}
+	return mappings, nil
+}
+
+// PodResolver returns the pod to forward to. It is called again whenever the connection to the pod is lost, so that
+// the forwarder follows the job to its new pod, e.g. after it was preempted
+type PodResolver func() (*v1.Pod, error)
+
+// Forwarder forwards local ports to the ports of a pod through the API server, and reconnects to the pod that the
+// resolver returns when the connection is lost
+type Forwarder struct {
+	restConfig *rest.Config
+	clientset  kubernetes.Interface
+	resolvePod PodResolver
+	mappings   []PortMapping
+	out        io.Writer
+
+	listeners []net.Listener
+	requestID int64
+
+	mutex sync.Mutex
+	conn  httpstream.Connection
+	pod   *v1.Pod
+}
+
+func NewForwarder(restConfig *rest.Config, clientset kubernetes.Interface, resolvePod PodResolver, mappings []PortMapping, out io.Writer) *Forwarder {
+	return &Forwarder{
+		restConfig: restConfig,
+		clientset:  clientset,
+		resolvePod: resolvePod,
+		mappings:   mappings,
+		out:        out,
+	}
+}
+
+// Listen listens on the local ports, or on free ports instead of the ports which are in use, and returns the mappings
+// of the ports which it listens on
+func (f *Forwarder) Listen() ([]PortMapping, error) {
+	for i, mapping := range f.mappings {
+		listener, err := net.Listen("tcp", net.JoinHostPort(localAddress, strconv.Itoa(mapping.Local)))
+		if err != nil && mapping.Local != 0 {
+			listener, err = net.Listen("tcp", net.JoinHostPort(localAddress, "0"))
+			if err == nil {
+				fmt.Fprintf(f.out, "Local port %d is in use, using port %d instead\n", mapping.Local, listener.Addr().(*net.TCPAddr).Port)
+			}
+		}
+		if err != nil {
+			f.close()
+			return nil, fmt.Errorf("failed to listen on a local port for port %d: %v", mapping.Remote, err)
+		}
+		f.mappings[i].Local = listener.Addr().(*net.TCPAddr).Port
+		f.listeners = append(f.listeners, listener)
+	}
+	return f.mappings, nil
+}
+
+// Run connects to the pod and forwards the connections to the local ports until stop is closed. Listen should be
+// called first
+func (f *Forwarder) Run(stop <-chan struct{}) error {
+	defer f.close()
+	if !f.connect(stop) {
+		return nil
+	}
+
+	for i, listener := range f.listeners {
+		go f.accept(listener, f.mappings[i])
+	}
+
+	for {
+		f.mutex.Lock()
+		conn, pod := f.conn, f.pod
+		f.mutex.Unlock()
+
+		select {
+		case <-stop:
+			return nil
+		case <-conn.CloseChan():
+			fmt.Fprintf(f.out, "Lost the connection to pod %s, reconnecting...\n", pod.Name)
+			if !f.connect(stop) {
+				return nil
+			}
+		}
+	}
+}
+
+// connect resolves the pod and connects to it, retrying until it succeeds. It returns false if stopped before
+func (f *Forwarder) connect(stop <-chan struct{}) bool {
+	for {
+		pod, err := f.resolvePod()
+		if err == nil {
+			var conn httpstream.Connection
+			if conn, err = DialPod(f.restConfig, f.clientset, pod); err == nil {
+				f.mutex.Lock()
+				f.conn, f.pod = conn, pod
+				f.mutex.Unlock()
+				fmt.Fprintf(f.out, "Forwarding to pod %s\n", pod.Name)
+				return true
+			}
+		}
+		log.Warnf("Failed to connect to the pod, retrying in %s: %v", reconnectInterval, err)
+
+		select {
+		case <-stop:
+			return false
+		case <-time.After(reconnectInterval):
+		}
+	}
+}
+
+func (f *Forwarder) accept(listener net.Listener, mapping PortMapping) {
+	for {
+		localConn, err := listener.Accept()
+		if err != nil {
+			// The listener is closed when the forwarder stops
+			return
+		}
+		go f.handleConnection(localConn, mapping)
+	}
+}
+
+func (f *Forwarder) handleConnection(localConn net.Conn, mapping PortMapping) {
+	defer localConn.Close()
+
+	f.mutex.Lock()
+	conn := f.conn
+	f.mutex.Unlock()
+
+	requestID := int(atomic.AddInt64(&f.requestID, 1))
+	if err := ForwardStream(conn, mapping.Remote, requestID, localConn, localConn); err != nil {
+		log.Debugf("Failed to forward a connection from port %d to port %d: %v", mapping.Local, mapping.Remote, err)
+	}
+}
+
+func (f *Forwarder) close() {
+	for _, listener := range f.listeners {
+		listener.Close()
+	}
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if f.conn != nil {
+		f.conn.Close()
+	}
+}
+
+// StopOnInterrupt returns a channel which is closed when the process is interrupted
+func StopOnInterrupt() <-chan struct{} {
+	stop := make(chan struct{})
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-signals
+		signal.Stop(signals)
+		close(stop)
+	}()
+	return stop
+}
@@ -0,0 +1,39 @@
+package portforward
+
+import (
+	"io/ioutil"
+	"net"
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+func TestParsePortMappings(t *testing.T) {
+	mappings, err := ParsePortMappings([]string{"8888", "8080:80", ":6006"})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, mappings, []PortMapping{{Local: 8888, Remote: 8888}, {Local: 8080, Remote: 80}, {Local: 0, Remote: 6006}})
+
+	for _, port := range []string{"http", "80:", "1:2:3", "-1", "70000", "x:80"} {
+		_, err = ParsePortMappings([]string{port})
+		assert.ErrorContains(t, err, "expected LOCAL:REMOTE or PORT", port)
+	}
+}
+
+func TestListenOnFreePortWhenInUse(t *testing.T) {
+	busy, err := net.Listen("tcp", "localhost:0")
+	assert.NilError(t, err)
+	defer busy.Close()
+	busyPort := busy.Addr().(*net.TCPAddr).Port
+
+	forwarder := NewForwarder(nil, nil, nil, []PortMapping{{Local: busyPort, Remote: 8888}, {Local: 0, Remote: 6006}}, ioutil.Discard)
+	mappings, err := forwarder.Listen()
+	assert.NilError(t, err)
+	defer forwarder.close()
+
+	assert.Equal(t, len(mappings), 2)
+	assert.Assert(t, mappings[0].Local != busyPort)
+	assert.Assert(t, mappings[0].Local != 0)
+	assert.Equal(t, mappings[0].Remote, 8888)
+	assert.Assert(t, mappings[1].Local != 0)
+	assert.Equal(t, mappings[1].Remote, 6006)
+}
@@ -0,0 +1,94 @@
+package portforward
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/httpstream"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/portforward"
+	"k8s.io/client-go/transport/spdy"
+)
+
+// NewDialer returns a dialer of the portforward subresource of the pod, through the API server
+func NewDialer(restConfig *rest.Config, clientset kubernetes.Interface, pod *v1.Pod) (httpstream.Dialer, error) {
+	transport, upgrader, err := spdy.RoundTripperFor(restConfig)
+	if err != nil {
+		return nil, err
+	}
+	url := clientset.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(pod.Namespace).
+		Name(pod.Name).
+		SubResource("portforward").
+		URL()
+	return spdy.NewDialer(upgrader, &http.Client{Transport: transport}, http.MethodPost, url), nil
+}
+
+// DialPod opens a port forwarding connection to the pod, on which ForwardStream forwards streams to ports of the pod
+func DialPod(restConfig *rest.Config, clientset kubernetes.Interface, pod *v1.Pod) (httpstream.Connection, error) {
+	dialer, err := NewDialer(restConfig, clientset, pod)
+	if err != nil {
+		return nil, err
+	}
+	conn, _, err := dialer.Dial(portforward.PortForwardProtocolV1Name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to pod %s: %v", pod.Name, err)
+	}
+	return conn, nil
+}
+
+// ForwardStream forwards the input to the port of the pod and the output of the port to the output, until the port
+// closes its side of the stream. Every stream on the same connection should have a different request ID
+func ForwardStream(conn httpstream.Connection, port int, requestID int, in io.Reader, out io.Writer) error {
+	headers := http.Header{}
+	headers.Set(v1.StreamType, v1.StreamTypeError)
+	headers.Set(v1.PortHeader, strconv.Itoa(port))
+	headers.Set(v1.PortForwardRequestIDHeader, strconv.Itoa(requestID))
+	errorStream, err := conn.CreateStream(headers)
+	if err != nil {
+		return fmt.Errorf("failed to create the error stream of port %d: %v", port, err)
+	}
+	// The error stream is only read
+	errorStream.Close()
+
+	errorChan := make(chan error)
+	go func() {
+		message, err := ioutil.ReadAll(errorStream)
+		switch {
+		case err != nil:
+			errorChan <- fmt.Errorf("failed to read the error stream of port %d: %v", port, err)
+		case len(message) > 0:
+			errorChan <- fmt.Errorf("failed to forward port %d: %s", port, string(message))
+		}
+		close(errorChan)
+	}()
+
+	headers.Set(v1.StreamType, v1.StreamTypeData)
+	dataStream, err := conn.CreateStream(headers)
+	if err != nil {
+		return fmt.Errorf("failed to create the data stream of port %d: %v", port, err)
+	}
+
+	remoteDone := make(chan error, 1)
+	go func() {
+		_, err := io.Copy(out, dataStream)
+		remoteDone <- err
+	}()
+	go func() {
+		// Closing the data stream tells the pod that there is no more input
+		defer dataStream.Close()
+		_, _ = io.Copy(dataStream, in)
+	}()
+
+	copyErr := <-remoteDone
+	if err = <-errorChan; err != nil {
+		return err
+	}
+	return copyErr
+}
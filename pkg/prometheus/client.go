@@ -28,6 +28,8 @@ const (
 	openshiftMonitoringNamespace                    = "openshift-monitoring"
 	thanosRouteName                                 = "thanos-querier"
 	promLabel                                       = "kube-prometheus-stack-prometheus"
+	instantQueryPath                                = "api/v1/query"
+	rangeQueryPath                                  = "api/v1/query_range"
 	SuccessStatus                MetricStatusResult = "success"
 )
 
@@ -52,6 +54,8 @@ type (
 	MetricResult struct {
 		Metric map[string]string `json:"metric"`
 		Value  []MetricValue     `json:"value"`
+		// Values are the [timestamp, value] pairs of a range query
+		Values [][]MetricValue `json:"values,omitempty"`
 	}
 
 	queryResult struct {
@@ -80,6 +84,12 @@ type (
 		// GroupMultiQueriesToItems queries prometheus for multiple queries from `queryMap` and groups the results by the `labelId` values
 		GroupMultiQueriesToItems(queryMap QueryNameToQuery, labelID string) (MetricResultsByItems, error)
 	}
+
+	// RangeQueryClient is interface to query prometheus over a range of time
+	RangeQueryClient interface {
+		// QueryRange evaluates `query` from `start` to `end`, with a value every `step` (see RangeStep)
+		QueryRange(query string, start, end time.Time, step time.Duration) (*MetricData, error)
+	}
 )
 
 func BuildMetricsClient(c *client.Client) (*Client, error) {
@@ -147,11 +157,9 @@ func (ps *Client) getThanosRouteService() (*ThanosRouteService, error) {
 	return &ThanosRouteService{url: thanosUrl, authorizationToken: fmt.Sprintf("bearer %s", userOcToken)}, nil
 }
 
-func (ps *Client) queryPrometheus(query string) (*MetricData, error) {
-	queryResponse := ps.client.CoreV1().Services(ps.prometheusService.Namespace).ProxyGet(prometheusSchema, ps.prometheusService.Name, "9090", "api/v1/query", map[string]string{
-		"query": query,
-		"time":  strconv.FormatInt(time.Now().Unix(), 10),
-	})
+func (ps *Client) queryPrometheus(path string, params map[string]string) (*MetricData, error) {
+	query := params["query"]
+	queryResponse := ps.client.CoreV1().Services(ps.prometheusService.Namespace).ProxyGet(prometheusSchema, ps.prometheusService.Name, "9090", path, params)
 
 	log.Debugf("Query prometheus for by %s in ns %s", query, ps.prometheusService.Namespace)
 	rawMetrics, err := queryResponse.DoRaw()
@@ -163,19 +171,21 @@ func (ps *Client) queryPrometheus(query string) (*MetricData, error) {
 	return handleQueryResponse(rawMetrics, query)
 }
 
-func (ps *Client) queryThanos(query string) (*MetricData, error) {
+func (ps *Client) queryThanos(path string, params map[string]string) (*MetricData, error) {
+	query := params["query"]
 	http.DefaultTransport.(*http.Transport).TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
 	client := http.Client{}
 
-	requestUrl := fmt.Sprintf("%s%s", ps.thanosRouteService.url, "api/v1/query")
+	requestUrl := fmt.Sprintf("%s%s", ps.thanosRouteService.url, path)
 	request, err := http.NewRequest("GET", requestUrl, nil)
 	if err != nil {
 		return nil, err
 	}
 
 	q := url.Values{}
-	q.Add("query", query)
-	q.Add("time", strconv.FormatInt(time.Now().Unix(), 10))
+	for key, value := range params {
+		q.Add(key, value)
+	}
 	request.URL.RawQuery = q.Encode()
 
 	request.Header.Set("Authorization", ps.thanosRouteService.authorizationToken)
@@ -196,6 +206,25 @@ func (ps *Client) queryThanos(query string) (*MetricData, error) {
 	return handleQueryResponse(rawMetrics, query)
 }
 
+func (ps *Client) query(path string, params map[string]string) (*MetricData, error) {
+	if ps.isOpenshift {
+		return ps.queryThanos(path, params)
+	}
+	return ps.queryPrometheus(path, params)
+}
+
+// QueryRange evaluates a query over a range of time, returning a matrix with a value every step for every series. When
+// the step is not set, it is the finest step which Prometheus allows for the range
+func (ps *Client) QueryRange(query string, start, end time.Time, step time.Duration) (*MetricData, error) {
+	step = RangeStep(start, end, step)
+	return ps.query(rangeQueryPath, map[string]string{
+		"query": query,
+		"start": strconv.FormatInt(start.Unix(), 10),
+		"end":   strconv.FormatInt(end.Unix(), 10),
+		"step":  strconv.FormatFloat(step.Seconds(), 'f', -1, 64),
+	})
+}
+
 func handleQueryResponse(rawMetric []byte, query string) (*MetricData, error) {
 	var err error
 	metricResponse := &Metric{}
@@ -250,13 +279,10 @@ func (ps *Client) queryAndGetResponse(queryMap QueryNameToQuery) (map[string]Met
 	var prometheusResultChanel = make(chan queryResult)
 	for queryName, query := range queryMap {
 		go (func(query, name string) {
-			var err error
-			var metric *MetricData
-			if ps.isOpenshift {
-				metric, err = ps.queryThanos(query)
-			} else {
-				metric, err = ps.queryPrometheus(query)
-			}
+			metric, err := ps.query(instantQueryPath, map[string]string{
+				"query": query,
+				"time":  strconv.FormatInt(time.Now().Unix(), 10),
+			})
 			prometheusResultChanel <- queryResult{name, metric, err}
 		})(query, queryName)
 	}
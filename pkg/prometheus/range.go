@@ -0,0 +1,153 @@
+package prometheus
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+)
+
+const (
+	MatrixResultType = "matrix"
+
+	// Prometheus rejects range queries of more than 11,000 points per series
+	maxRangeQueryPoints = 10000
+	minRangeQueryStep   = time.Minute
+)
+
+type (
+	// Sample is a value of a series at a point in time
+	Sample struct {
+		Time  time.Time
+		Value float64
+	}
+
+	// SampleStream is a series of a range query, with its labels and its samples ordered by time
+	SampleStream struct {
+		Metric  map[string]string
+		Samples []Sample
+	}
+)
+
+// RangeStep returns the given step, or the finest step which Prometheus allows for the range when it is not set
+func RangeStep(start, end time.Time, step time.Duration) time.Duration {
+	if step > 0 {
+		return step
+	}
+	step = end.Sub(start) / maxRangeQueryPoints
+	if step < minRangeQueryStep {
+		return minRangeQueryStep
+	}
+	return step.Round(time.Second)
+}
+
+// Matrix parses the result of a range query. Samples which are not a number (e.g. of a division by zero) are skipped
+func (data *MetricData) Matrix() ([]SampleStream, error) {
+	if data.ResultType != "" && data.ResultType != MatrixResultType {
+		return nil, fmt.Errorf("[Prometheus] Expected a %s result, got %s", MatrixResultType, data.ResultType)
+	}
+
+	streams := []SampleStream{}
+	for _, result := range data.Result {
+		stream := SampleStream{Metric: result.Metric, Samples: []Sample{}}
+		for _, value := range result.Values {
+			sample, err := parseSample(value)
+			if err != nil {
+				return nil, err
+			}
+			if math.IsNaN(sample.Value) || math.IsInf(sample.Value, 0) {
+				continue
+			}
+			stream.Samples = append(stream.Samples, sample)
+		}
+		streams = append(streams, stream)
+	}
+	return streams, nil
+}
+
+func parseSample(value []MetricValue) (Sample, error) {
+	if len(value) != 2 {
+		return Sample{}, fmt.Errorf("[Prometheus] Expected a [timestamp, value] pair, got %v", value)
+	}
+	timestamp, ok := value[0].(float64)
+	if !ok {
+		return Sample{}, fmt.Errorf("[Prometheus] Invalid timestamp: %v", value[0])
+	}
+	valueString, ok := value[1].(string)
+	if !ok {
+		return Sample{}, fmt.Errorf("[Prometheus] Invalid value: %v", value[1])
+	}
+	n, err := strconv.ParseFloat(valueString, 64)
+	if err != nil {
+		return Sample{}, err
+	}
+	seconds, fraction := math.Modf(timestamp)
+	return Sample{Time: time.Unix(int64(seconds), int64(fraction*float64(time.Second))), Value: n}, nil
+}
+
+// Window returns the samples from start (inclusive) to end (exclusive)
+func Window(samples []Sample, start, end time.Time) []Sample {
+	window := []Sample{}
+	for _, sample := range samples {
+		if !sample.Time.Before(start) && sample.Time.Before(end) {
+			window = append(window, sample)
+		}
+	}
+	return window
+}
+
+// Avg returns the average value of the samples, or 0 when there are none
+func Avg(samples []Sample) float64 {
+	if len(samples) == 0 {
+		return 0
+	}
+	return Sum(samples) / float64(len(samples))
+}
+
+// Sum returns the sum of the values of the samples
+func Sum(samples []Sample) float64 {
+	sum := 0.0
+	for _, sample := range samples {
+		sum += sample.Value
+	}
+	return sum
+}
+
+// Max returns the maximal value of the samples, or 0 when there are none
+func Max(samples []Sample) float64 {
+	if len(samples) == 0 {
+		return 0
+	}
+	max := samples[0].Value
+	for _, sample := range samples[1:] {
+		max = math.Max(max, sample.Value)
+	}
+	return max
+}
+
+// Quantile returns the φ-quantile (0 ≤ φ ≤ 1) of the values of the samples, interpolating between the two nearest
+// values as quantile_over_time does, or 0 when there are none. For example, Quantile(samples, 0.95) is the p95
+func Quantile(samples []Sample, phi float64) float64 {
+	if len(samples) == 0 {
+		return 0
+	}
+	values := make([]float64, 0, len(samples))
+	for _, sample := range samples {
+		values = append(values, sample.Value)
+	}
+	sort.Float64s(values)
+
+	phi = math.Min(math.Max(phi, 0), 1)
+	rank := phi * float64(len(values)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+	weight := rank - float64(lower)
+	return values[lower]*(1-weight) + values[upper]*weight
+}
+
+// Integral returns the integral of the samples over time, each sample holding its value for a step. For example, the
+// integral of allocated GPUs is in GPU-seconds
+func Integral(samples []Sample, step time.Duration) float64 {
+	return Sum(samples) * step.Seconds()
+}
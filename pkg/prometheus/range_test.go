@@ -0,0 +1,108 @@
+package prometheus
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+)
+
+const testMatrixResponse = `{
+	"status": "success",
+	"data": {
+		"resultType": "matrix",
+		"result": [
+			{
+				"metric": {"pod_group_uuid": "uuid-1"},
+				"values": [[1598918400, "1"], [1598918460, "NaN"], [1598918520, "3"], [1598918580.5, "4"]]
+			},
+			{
+				"metric": {"pod_group_uuid": "uuid-2"},
+				"values": []
+			}
+		]
+	}
+}`
+
+func parseTestMatrix(t *testing.T) []SampleStream {
+	data, err := handleQueryResponse([]byte(testMatrixResponse), "test")
+	assert.NilError(t, err)
+	streams, err := data.Matrix()
+	assert.NilError(t, err)
+	return streams
+}
+
+func newTestSamples(values ...float64) []Sample {
+	start := time.Unix(1598918400, 0)
+	samples := []Sample{}
+	for i, value := range values {
+		samples = append(samples, Sample{Time: start.Add(time.Duration(i) * time.Minute), Value: value})
+	}
+	return samples
+}
+
+func TestMatrix(t *testing.T) {
+	streams := parseTestMatrix(t)
+
+	assert.Equal(t, len(streams), 2)
+	assert.DeepEqual(t, streams[0].Metric, map[string]string{"pod_group_uuid": "uuid-1"})
+	assert.DeepEqual(t, streams[0].Samples, []Sample{
+		{Time: time.Unix(1598918400, 0), Value: 1},
+		{Time: time.Unix(1598918520, 0), Value: 3},
+		{Time: time.Unix(1598918580, int64(500*time.Millisecond)), Value: 4},
+	})
+	assert.Equal(t, len(streams[1].Samples), 0)
+}
+
+func TestMatrixOfVector(t *testing.T) {
+	data := &MetricData{ResultType: "vector", Result: []MetricResult{{Value: []MetricValue{1598918400.0, "1"}}}}
+
+	_, err := data.Matrix()
+	assert.ErrorContains(t, err, "Expected a matrix result, got vector")
+}
+
+func TestMatrixInvalidValue(t *testing.T) {
+	raw := `{"resultType": "matrix", "result": [{"metric": {}, "values": [[1598918400, 1]]}]}`
+	data := &MetricData{}
+	assert.NilError(t, json.Unmarshal([]byte(raw), data))
+
+	_, err := data.Matrix()
+	assert.ErrorContains(t, err, "Invalid value")
+}
+
+func TestAggregations(t *testing.T) {
+	samples := newTestSamples(4, 1, 3, 2, 10)
+
+	assert.Equal(t, Sum(samples), 20.0)
+	assert.Equal(t, Avg(samples), 4.0)
+	assert.Equal(t, Max(samples), 10.0)
+	assert.Equal(t, Quantile(samples, 0.5), 3.0)
+	assert.Assert(t, math.Abs(Quantile(samples, 0.95)-8.8) < 1e-9)
+	assert.Equal(t, Quantile(samples, 1), 10.0)
+	assert.Equal(t, Integral(samples, time.Minute), 1200.0)
+}
+
+func TestAggregationsOfNoSamples(t *testing.T) {
+	assert.Equal(t, Avg(nil), 0.0)
+	assert.Equal(t, Max(nil), 0.0)
+	assert.Equal(t, Quantile(nil, 0.95), 0.0)
+}
+
+func TestWindow(t *testing.T) {
+	samples := newTestSamples(1, 2, 3, 4)
+	start := samples[1].Time
+
+	window := Window(samples, start, start.Add(2*time.Minute))
+	assert.DeepEqual(t, window, samples[1:3])
+	assert.Equal(t, Max(window), 3.0)
+}
+
+func TestRangeStep(t *testing.T) {
+	start := time.Date(2020, 9, 1, 0, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, RangeStep(start, start.Add(24*time.Hour), 0), minRangeQueryStep)
+	assert.Equal(t, RangeStep(start, start.AddDate(0, 1, 0), 0), 259*time.Second)
+	assert.Equal(t, RangeStep(start, start.AddDate(0, 1, 0), time.Hour), time.Hour)
+}
@@ -56,13 +56,15 @@ type SubmitTemplate struct {
 }
 
 type GitSyncTemplate struct {
-	Repository *TemplateField `yaml:"source,omitempty"`
-	Branch     *TemplateField `yaml:"branch,omitempty"`
-	Revision   *TemplateField `yaml:"rev,omitempty"`
-	Username   *TemplateField `yaml:"username,omitempty"`
-	Password   *TemplateField `yaml:"password,omitempty"`
-	Image      *TemplateField `yaml:"image,omitempty"`
-	Directory  *TemplateField `yaml:"target,omitempty"`
+	Repository     *TemplateField `yaml:"source,omitempty"`
+	Branch         *TemplateField `yaml:"branch,omitempty"`
+	Revision       *TemplateField `yaml:"rev,omitempty"`
+	Username       *TemplateField `yaml:"username,omitempty"`
+	Password       *TemplateField `yaml:"password,omitempty"`
+	PasswordSecret *TemplateField `yaml:"passwordSecret,omitempty"`
+	SSHKeySecret   *TemplateField `yaml:"sshKeySecret,omitempty"`
+	Image          *TemplateField `yaml:"image,omitempty"`
+	Directory      *TemplateField `yaml:"target,omitempty"`
 }
 
 func GetSubmitTemplateFromYaml(templateYaml string) (*SubmitTemplate, error) {
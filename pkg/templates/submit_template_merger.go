@@ -94,6 +94,12 @@ func mergeGitSyncTemplate(base, patch *GitSyncTemplate) *GitSyncTemplate {
 	if patch.Password != nil {
 		base.Password = patch.Password
 	}
+	if patch.PasswordSecret != nil {
+		base.PasswordSecret = patch.PasswordSecret
+	}
+	if patch.SSHKeySecret != nil {
+		base.SSHKeySecret = patch.SSHKeySecret
+	}
 	if patch.Image != nil {
 		base.Image = patch.Image
 	}
@@ -1,12 +1,12 @@
 package types
 
 type GPU struct {
-	IndexID                          string  `title:"GPU"`
-	Allocated                        float64 `title:"ALLOCATED"`
-	Utilization                      float64 `title:"UTILIZATION" format:"%"`
-	Memory                           float64 `title:"MEMORY" format:"memory"`
-	MemoryUsage                      float64 `title:"MEMORY USAGE" format:"memory"`
-	MemoryUtilization				 float64 `title:"MEMORY UTILIZATION" format:"%"`
-	MemoryUsageAndUtilization		 string  `title:"MEMORY USAGE"`
-	IdleTime                         float64 `title:"IDLE TIME" format:"time"`
+	IndexID                          string  `title:"GPU" json:"index" yaml:"index"`
+	Allocated                        float64 `title:"ALLOCATED" json:"allocated" yaml:"allocated"`
+	Utilization                      float64 `title:"UTILIZATION" format:"%" json:"utilization" yaml:"utilization"`
+	Memory                           float64 `title:"MEMORY" format:"memory" json:"memory" yaml:"memory"`
+	MemoryUsage                      float64 `title:"MEMORY USAGE" format:"memory" json:"memoryUsage" yaml:"memoryUsage"`
+	MemoryUtilization				 float64 `title:"MEMORY UTILIZATION" format:"%" json:"memoryUtilization" yaml:"memoryUtilization"`
+	MemoryUsageAndUtilization		 string  `title:"MEMORY USAGE" json:"-" yaml:"-"`
+	IdleTime                         float64 `title:"IDLE TIME" format:"time" json:"idleTime" yaml:"idleTime"`
 }
@@ -5,47 +5,47 @@ type NodeStatus string
 
 
 type NodeCPUResource struct {
-	Capacity    int     `title:"CAPACITY" def:"0"`
-	Allocatable float64 `title:"ALLOCATABLE"`
-	Allocated   float64 `title:"ALLOCATED"`
-	Utilization float64 `title:"UTILIZATION" format:"%"`
-	Usage       float64 `title:"USAGE"`
+	Capacity    int     `title:"CAPACITY" def:"0" json:"capacity" yaml:"capacity"`
+	Allocatable float64 `title:"ALLOCATABLE" json:"allocatable" yaml:"allocatable"`
+	Allocated   float64 `title:"ALLOCATED" json:"allocated" yaml:"allocated"`
+	Utilization float64 `title:"UTILIZATION" format:"%" json:"utilization" yaml:"utilization"`
+	Usage       float64 `title:"USAGE" json:"usage" yaml:"usage"`
 }
 
 type NodeGPUResource struct {
-	GpuType     string  `title:"TYPE" def:"-"`
-	Capacity    int     `title:"CAPACITY" def:"0"`
-	Allocatable float64 `title:"ALLOCATABLE" def:"0"`
-	Allocated   float64 `title:"ALLOCATED"`
-	InUse       int     `title:"IN USE"`
-	Free        int     `title:"FREE"`
-	Utilization float64 `title:"UTILIZATION" format:"%"`
-	Usage       float64 `title:"USAGE"`
-	Unhealthy   int     `title:"UNHEALTHY"`
+	GpuType     string  `title:"TYPE" def:"-" json:"type" yaml:"type"`
+	Capacity    int     `title:"CAPACITY" def:"0" json:"capacity" yaml:"capacity"`
+	Allocatable float64 `title:"ALLOCATABLE" def:"0" json:"allocatable" yaml:"allocatable"`
+	Allocated   float64 `title:"ALLOCATED" json:"allocated" yaml:"allocated"`
+	InUse       int     `title:"IN USE" json:"inUse" yaml:"inUse"`
+	Free        int     `title:"FREE" json:"free" yaml:"free"`
+	Utilization float64 `title:"UTILIZATION" format:"%" json:"utilization" yaml:"utilization"`
+	Usage       float64 `title:"USAGE" json:"usage" yaml:"usage"`
+	Unhealthy   int     `title:"UNHEALTHY" json:"unhealthy" yaml:"unhealthy"`
 }
 
 type NodeMemoryResource struct {
-	Capacity            float64 `title:"CAPACITY" format:"memory" def:"0"`
-	Allocatable         float64 `title:"ALLOCATABLE" format:"memory"`
-	Allocated           float64 `title:"ALLOCATED" format:"memory"`
-	Utilization         float64 `title:"UTILIZATION" format:"%"`
-	Usage               float64 `title:"USAGE" format:"memory"`
-	UsageAndUtilization string  `title:"USAGE"`
+	Capacity            float64 `title:"CAPACITY" format:"memory" def:"0" json:"capacity" yaml:"capacity"`
+	Allocatable         float64 `title:"ALLOCATABLE" format:"memory" json:"allocatable" yaml:"allocatable"`
+	Allocated           float64 `title:"ALLOCATED" format:"memory" json:"allocated" yaml:"allocated"`
+	Utilization         float64 `title:"UTILIZATION" format:"%" json:"utilization" yaml:"utilization"`
+	Usage               float64 `title:"USAGE" format:"memory" json:"usage" yaml:"usage"`
+	UsageAndUtilization string  `title:"USAGE" json:"-" yaml:"-"`
 }
 
 type NodeGeneralInfo struct {
-	Name      string     `title:"NAME"`
-	Status    string 	 `title:"STATUS"`
-	IPAddress string     `title:"IP Address"`
-	Role      string     `title:"ROLE" def:"<none>"`
+	Name      string     `title:"NAME" json:"name" yaml:"name"`
+	Status    string 	 `title:"STATUS" json:"status" yaml:"status"`
+	IPAddress string     `title:"IP Address" json:"ipAddress" yaml:"ipAddress"`
+	Role      string     `title:"ROLE" def:"<none>" json:"role" yaml:"role"`
 }
 
 type NodeView struct {
-	Info   NodeGeneralInfo     `group:"GENERAL,flatten"`
-	CPUs   *NodeCPUResource    `group:"CPU"`
-	Mem    *NodeMemoryResource `group:"MEMORY"`
-	GPUs   *NodeGPUResource    `group:"GPU" def:"<none>"`
-	GPUMem *NodeMemoryResource `group:"GPU MEMORY" def:"<none>"`
+	Info   NodeGeneralInfo     `group:"GENERAL,flatten" json:"info" yaml:"info"`
+	CPUs   *NodeCPUResource    `group:"CPU" json:"cpus" yaml:"cpus"`
+	Mem    *NodeMemoryResource `group:"MEMORY" json:"memory" yaml:"memory"`
+	GPUs   *NodeGPUResource    `group:"GPU" def:"<none>" json:"gpus" yaml:"gpus"`
+	GPUMem *NodeMemoryResource `group:"GPU MEMORY" def:"<none>" json:"gpuMemory" yaml:"gpuMemory"`
 }
 
 type ClusterNodesView struct {
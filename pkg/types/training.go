@@ -5,6 +5,16 @@ type JobInfo struct {
 	Name string `json:"name"`
 	// The namespace of the training job
 	Namespace string `json:"namespace"`
+	// The project of the training job
+	Project string `json:"project"`
+	// The user who submitted the training job
+	User string `json:"user"`
+	// The image of the training job
+	Image string `json:"image"`
+	// The node of the chief instance, or <multiple> for distributed jobs
+	Node string `json:"node"`
+	// The age of the training job
+	Age string `json:"age"`
 	// The time of the training job
 	Duration string `json:"duration"`
 	// The status of the training Job
@@ -26,6 +36,17 @@ type JobInfo struct {
 
 	// The command line that created the job
 	CommandLine string `json:"commandLine" yaml:"commandLine"`
+
+	// The GPUs requested by the training job
+	RequestedGPUs string `json:"requestedGPUs" yaml:"requestedGPUs"`
+	// The GPUs currently allocated to the training job
+	AllocatedGPUs float64 `json:"allocatedGPUs" yaml:"allocatedGPUs"`
+	// The number of running pods
+	RunningPods int32 `json:"runningPods" yaml:"runningPods"`
+	// The number of pending pods
+	PendingPods int32 `json:"pendingPods" yaml:"pendingPods"`
+	// The URLs of the services exposed by the training job
+	ServiceURLs []string `json:"serviceUrls,omitempty" yaml:"serviceUrls,omitempty"`
 }
 
 // all the kinds of JobStatus
@@ -36,6 +57,8 @@ const (
 	JobSucceeded JobStatus = "SUCCEEDED"
 	// JobFailed means the job is failed
 	JobFailed JobStatus = "FAILED"
+	// JobInvalid means the job has a config map but no workload
+	JobInvalid JobStatus = "INVALID"
 )
 
 type Instance struct {
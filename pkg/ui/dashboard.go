@@ -0,0 +1,216 @@
+package ui
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/crypto/ssh/terminal"
+)
+
+type Key string
+
+const (
+	KeyUp       Key = "up"
+	KeyDown     Key = "down"
+	KeyPageUp   Key = "pageup"
+	KeyPageDown Key = "pagedown"
+	KeyEnter    Key = "enter"
+	KeyBack     Key = "back"
+	KeyQuit     Key = "quit"
+
+	// The size of the terminal is polled, since there is no portable resize signal
+	resizePollInterval = 500 * time.Millisecond
+)
+
+// Dashboard is a full-screen view which is refreshed on an interval and reacts to the keys the user presses.
+// Fetch runs in the background, while all the other methods run on the same goroutine and may share state freely.
+type Dashboard interface {
+	// Fetch gets the data to display, it should not modify the state of the dashboard
+	Fetch() (interface{}, error)
+	// Update sets the data returned by Fetch
+	Update(data interface{}, err error)
+	// HandleKey updates the dashboard for a key, and returns false to quit
+	HandleKey(key Key) bool
+	// Render returns the lines of the screen
+	Render(width, height int) []string
+}
+
+type fetchResult struct {
+	data interface{}
+	err  error
+}
+
+// RunDashboard shows the dashboard on the whole terminal until the user quits
+func RunDashboard(dashboard Dashboard, interval time.Duration) error {
+	inFd, outFd := int(os.Stdin.Fd()), int(os.Stdout.Fd())
+	if !terminal.IsTerminal(inFd) || !terminal.IsTerminal(outFd) {
+		return fmt.Errorf("watching requires a terminal")
+	}
+
+	state, err := terminal.MakeRaw(inFd)
+	if err != nil {
+		return err
+	}
+	defer terminal.Restore(inFd, state)
+
+	// Log messages would be written over the screen
+	logOutput := log.StandardLogger().Out
+	log.SetOutput(ioutil.Discard)
+	defer log.SetOutput(logOutput)
+
+	screen := NewScreen(os.Stdout)
+	screen.Open()
+	defer screen.Close()
+
+	keys := make(chan Key)
+	go readKeys(os.Stdin, keys)
+
+	results := make(chan fetchResult, 1)
+	fetch := func() {
+		data, err := dashboard.Fetch()
+		results <- fetchResult{data: data, err: err}
+	}
+	go fetch()
+
+	refreshTicker := time.NewTicker(interval)
+	defer refreshTicker.Stop()
+	resizeTicker := time.NewTicker(resizePollInterval)
+	defer resizeTicker.Stop()
+
+	fetching := true
+	for {
+		width, height, err := terminal.GetSize(outFd)
+		if err != nil {
+			return err
+		}
+		if err = screen.Draw(dashboard.Render(width, height), width, height); err != nil {
+			return err
+		}
+
+		select {
+		case key, open := <-keys:
+			if !open || !dashboard.HandleKey(key) {
+				return nil
+			}
+		case result := <-results:
+			fetching = false
+			dashboard.Update(result.data, result.err)
+		case <-refreshTicker.C:
+			// Skip a refresh while the previous one is still running, so a slow cluster is not queried in parallel
+			if !fetching {
+				fetching = true
+				go fetch()
+			}
+		case <-resizeTicker.C:
+		}
+	}
+}
+
+// readKeys sends the keys read from a terminal in raw mode, until it fails to read
+func readKeys(in *os.File, keys chan<- Key) {
+	defer close(keys)
+	buffer := make([]byte, 16)
+	for {
+		n, err := in.Read(buffer)
+		if err != nil {
+			return
+		}
+		if key, known := parseKey(buffer[:n]); known {
+			keys <- key
+		}
+	}
+}
+
+func parseKey(input []byte) (Key, bool) {
+	switch string(input) {
+	case "\x1b[A", "k":
+		return KeyUp, true
+	case "\x1b[B", "j":
+		return KeyDown, true
+	case "\x1b[5~":
+		return KeyPageUp, true
+	case "\x1b[6~", " ":
+		return KeyPageDown, true
+	case "\r", "\n":
+		return KeyEnter, true
+	case "\x1b", "\x7f", "\b":
+		return KeyBack, true
+	case "q", "\x03":
+		return KeyQuit, true
+	}
+	if len(input) == 1 {
+		return Key(input), true
+	}
+	return "", false
+}
+
+// Selection is the selected row of a list which is longer than the screen, and the first row shown on the screen
+type Selection struct {
+	Index  int
+	Offset int
+}
+
+// HandleKey moves the selection for the navigation keys, and returns false for any other key
+func (s *Selection) HandleKey(key Key, count, pageSize int) bool {
+	switch key {
+	case KeyUp:
+		s.Index--
+	case KeyDown:
+		s.Index++
+	case KeyPageUp:
+		s.Index -= pageSize
+	case KeyPageDown:
+		s.Index += pageSize
+	default:
+		return false
+	}
+	s.Fit(count, pageSize)
+	return true
+}
+
+// Fit keeps the selection within the list, and scrolls the list so the selected row is on the screen
+func (s *Selection) Fit(count, pageSize int) {
+	if s.Index >= count {
+		s.Index = count - 1
+	}
+	if s.Index < 0 {
+		s.Index = 0
+	}
+	if pageSize < 1 {
+		pageSize = 1
+	}
+	if s.Index < s.Offset {
+		s.Offset = s.Index
+	}
+	if s.Index >= s.Offset+pageSize {
+		s.Offset = s.Index - pageSize + 1
+	}
+	if s.Offset > count-pageSize {
+		s.Offset = count - pageSize
+	}
+	if s.Offset < 0 {
+		s.Offset = 0
+	}
+}
+
+// RenderList returns the header line and the rows of a list which fit on the screen, with the selected row highlighted
+func RenderList(header string, rows []string, selection *Selection, width, pageSize int) []string {
+	selection.Fit(len(rows), pageSize)
+	end := selection.Offset + pageSize
+	if end > len(rows) {
+		end = len(rows)
+	}
+
+	lines := []string{header}
+	for i := selection.Offset; i < end; i++ {
+		if i == selection.Index {
+			lines = append(lines, Highlight(rows[i], width))
+		} else {
+			lines = append(lines, rows[i])
+		}
+	}
+	return lines
+}
@@ -0,0 +1,62 @@
+package ui
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Output formats supported by the read commands (list, describe, top)
+const (
+	DefaultOutput = ""
+	JsonOutput    = "json"
+	YamlOutput    = "yaml"
+	WideOutput    = "wide"
+	NameOutput    = "name"
+)
+
+var OutputFormats = []string{JsonOutput, YamlOutput, WideOutput, NameOutput}
+
+// ValidateOutputFormat returns an error if the given format is not one of OutputFormats
+func ValidateOutputFormat(format string) error {
+	return ValidateOutputFormatOf(format, OutputFormats)
+}
+
+// ValidateOutputFormatOf returns an error if the given format is not one of the formats supported by a command
+func ValidateOutputFormatOf(format string, formats []string) error {
+	if format == DefaultOutput || Contains(formats, format) {
+		return nil
+	}
+	return fmt.Errorf("unknown output format: %s. One of: %s", format, strings.Join(formats, "|"))
+}
+
+// PrintStructuredOutput serializes the object as json or yaml to the writer
+func PrintStructuredOutput(w io.Writer, format string, obj interface{}) error {
+	var outBytes []byte
+	var err error
+
+	switch format {
+	case JsonOutput:
+		outBytes, err = json.MarshalIndent(obj, "", "    ")
+	case YamlOutput:
+		outBytes, err = yaml.Marshal(obj)
+	default:
+		return fmt.Errorf("output format %s is not a structured format", format)
+	}
+
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(w, strings.TrimSuffix(string(outBytes), "\n"))
+	return err
+}
+
+// PrintNames prints one name per line, to be piped into other commands
+func PrintNames(w io.Writer, names []string) {
+	for _, name := range names {
+		fmt.Fprintln(w, name)
+	}
+}
@@ -0,0 +1,61 @@
+package ui
+
+import (
+	"bytes"
+	"testing"
+)
+
+type outputItem struct {
+	Name  string `json:"name" yaml:"name"`
+	Count int    `json:"count" yaml:"count"`
+}
+
+func TestValidateOutputFormat(t *testing.T) {
+	for _, format := range []string{DefaultOutput, JsonOutput, YamlOutput, WideOutput, NameOutput} {
+		if err := ValidateOutputFormat(format); err != nil {
+			t.Errorf("Expected format '%s' to be valid, got %v", format, err)
+		}
+	}
+
+	if err := ValidateOutputFormat("xml"); err == nil {
+		t.Errorf("Expected format 'xml' to be invalid")
+	}
+
+	if err := ValidateOutputFormatOf(WideOutput, []string{JsonOutput, YamlOutput, NameOutput}); err == nil {
+		t.Errorf("Expected format 'wide' to be invalid when it is not supported")
+	}
+}
+
+func TestPrintStructuredOutput(t *testing.T) {
+	items := []outputItem{{Name: "job-1", Count: 2}}
+
+	t.Run("Json", func(t *testing.T) {
+		b := new(bytes.Buffer)
+		if err := PrintStructuredOutput(b, JsonOutput, items); err != nil {
+			t.Fatalf("Failed to print json, %s", err)
+		}
+
+		expected := "[\n    {\n        \"name\": \"job-1\",\n        \"count\": 2\n    }\n]\n"
+		if got := b.String(); got != expected {
+			t.Errorf("Strings dont match expected:\n\n%s\nresult: \n\n%s", expected, got)
+		}
+	})
+
+	t.Run("Yaml", func(t *testing.T) {
+		b := new(bytes.Buffer)
+		if err := PrintStructuredOutput(b, YamlOutput, items); err != nil {
+			t.Fatalf("Failed to print yaml, %s", err)
+		}
+
+		expected := "- name: job-1\n  count: 2\n"
+		if got := b.String(); got != expected {
+			t.Errorf("Strings dont match expected:\n\n%s\nresult: \n\n%s", expected, got)
+		}
+	})
+
+	t.Run("Not structured", func(t *testing.T) {
+		if err := PrintStructuredOutput(new(bytes.Buffer), WideOutput, items); err == nil {
+			t.Errorf("Expected an error for a non structured format")
+		}
+	})
+}
@@ -0,0 +1,100 @@
+package ui
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+const (
+	enterAlternateScreen = "\x1b[?1049h\x1b[?25l"
+	exitAlternateScreen  = "\x1b[?25h\x1b[?1049l"
+	clearScreen          = "\x1b[2J"
+	clearToEndOfLine     = "\x1b[K"
+	reverseVideo         = "\x1b[7m"
+	resetVideo           = "\x1b[0m"
+)
+
+// Screen draws full-screen frames on a terminal. Only the lines which changed since the previous frame are written,
+// so refreshing a large view costs about as much as the number of lines which changed in it.
+type Screen struct {
+	out    io.Writer
+	lines  []string
+	width  int
+	height int
+}
+
+func NewScreen(out io.Writer) *Screen {
+	return &Screen{out: out}
+}
+
+func (s *Screen) Open() {
+	fmt.Fprint(s.out, enterAlternateScreen+clearScreen)
+	s.lines = nil
+}
+
+func (s *Screen) Close() {
+	fmt.Fprint(s.out, exitAlternateScreen)
+}
+
+// Draw writes the frame, cut to the size of the screen. Changing the size redraws the whole frame.
+func (s *Screen) Draw(lines []string, width, height int) error {
+	if width != s.width || height != s.height {
+		s.width, s.height = width, height
+		s.lines = nil
+	}
+
+	var buffer bytes.Buffer
+	if s.lines == nil {
+		buffer.WriteString(clearScreen)
+	}
+
+	frame := make([]string, height)
+	for row := 0; row < height; row++ {
+		if row < len(lines) {
+			frame[row] = cutLine(lines[row], width)
+		}
+		if s.lines != nil && s.lines[row] == frame[row] {
+			continue
+		}
+		fmt.Fprintf(&buffer, "\x1b[%d;1H%s%s", row+1, frame[row], clearToEndOfLine)
+	}
+	s.lines = frame
+
+	if buffer.Len() == 0 {
+		return nil
+	}
+	_, err := s.out.Write(buffer.Bytes())
+	return err
+}
+
+// Highlight marks a line of a frame as selected
+func Highlight(line string, width int) string {
+	line = cutLine(line, width)
+	return reverseVideo + line + strings.Repeat(" ", width-len([]rune(line))) + resetVideo
+}
+
+// FormatColumns aligns the cells of the rows into columns, the same as the tables of the CLI are aligned
+func FormatColumns(rows [][]string) []string {
+	var buffer bytes.Buffer
+	w := tabwriter.NewWriter(&buffer, 0, 0, 2, ' ', 0)
+	for _, row := range rows {
+		Line(w, row...)
+	}
+	_ = w.Flush()
+	return strings.Split(strings.TrimSuffix(buffer.String(), "\n"), "\n")
+}
+
+// cutLine cuts the line to the width of the screen, keeping a highlighted line intact
+func cutLine(line string, width int) string {
+	if strings.HasPrefix(line, reverseVideo) {
+		return line
+	}
+	runes := []rune(line)
+	if len(runes) <= width {
+		return line
+	}
+	return string(runes[:width])
+}
@@ -0,0 +1,92 @@
+package ui
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/magiconair/properties/assert"
+)
+
+func TestScreenDrawsOnlyChangedLines(t *testing.T) {
+	var out bytes.Buffer
+	screen := NewScreen(&out)
+
+	err := screen.Draw([]string{"first", "second", "third"}, 80, 4)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, strings.HasPrefix(out.String(), clearScreen), true)
+	assert.Equal(t, strings.Contains(out.String(), "\x1b[3;1Hthird"+clearToEndOfLine), true)
+
+	out.Reset()
+	err = screen.Draw([]string{"first", "changed", "third"}, 80, 4)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, out.String(), "\x1b[2;1Hchanged"+clearToEndOfLine)
+
+	out.Reset()
+	err = screen.Draw([]string{"first", "changed", "third"}, 80, 4)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, out.String(), "")
+
+	// Lines which are no longer drawn are cleared
+	out.Reset()
+	err = screen.Draw([]string{"first"}, 80, 4)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, out.String(), "\x1b[2;1H"+clearToEndOfLine+"\x1b[3;1H"+clearToEndOfLine)
+}
+
+func TestScreenRedrawsOnResize(t *testing.T) {
+	var out bytes.Buffer
+	screen := NewScreen(&out)
+
+	_ = screen.Draw([]string{"a long line"}, 80, 2)
+	out.Reset()
+	_ = screen.Draw([]string{"a long line"}, 6, 2)
+	assert.Equal(t, out.String(), clearScreen+"\x1b[1;1Ha long"+clearToEndOfLine+"\x1b[2;1H"+clearToEndOfLine)
+}
+
+func TestSelectionScrollsToSelectedRow(t *testing.T) {
+	selection := Selection{}
+
+	assert.Equal(t, selection.HandleKey(KeyUp, 10, 3), true)
+	assert.Equal(t, selection, Selection{Index: 0, Offset: 0})
+
+	selection.HandleKey(KeyDown, 10, 3)
+	selection.HandleKey(KeyDown, 10, 3)
+	selection.HandleKey(KeyDown, 10, 3)
+	assert.Equal(t, selection, Selection{Index: 3, Offset: 1})
+
+	selection.HandleKey(KeyPageDown, 10, 3)
+	selection.HandleKey(KeyPageDown, 10, 3)
+	assert.Equal(t, selection, Selection{Index: 9, Offset: 7})
+
+	// The list got shorter
+	selection.Fit(5, 3)
+	assert.Equal(t, selection, Selection{Index: 4, Offset: 2})
+
+	assert.Equal(t, selection.HandleKey("x", 5, 3), false)
+}
+
+func TestRenderList(t *testing.T) {
+	selection := Selection{Index: 2}
+	lines := RenderList("HEADER", []string{"a", "b", "c", "d"}, &selection, 3, 2)
+	assert.Equal(t, lines, []string{"HEADER", "b", Highlight("c", 3)})
+	assert.Equal(t, Highlight("c", 3), reverseVideo+"c  "+resetVideo)
+}
+
+func TestParseKey(t *testing.T) {
+	for input, expected := range map[string]Key{
+		"\x1b[A": KeyUp,
+		"j":      KeyDown,
+		"\r":     KeyEnter,
+		"\x1b":   KeyBack,
+		"\x03":   KeyQuit,
+		"u":      "u",
+	} {
+		key, known := parseKey([]byte(input))
+		assert.Equal(t, known, true)
+		assert.Equal(t, key, expected)
+	}
+
+	_, known := parseKey([]byte("\x1b[1;5C"))
+	assert.Equal(t, known, false)
+}
@@ -0,0 +1,57 @@
+package util
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+func TestUnifiedDiffEqual(t *testing.T) {
+	assert.Equal(t, UnifiedDiff("a", "b", "x\ny\n", "x\ny\n"), "")
+}
+
+func TestUnifiedDiff(t *testing.T) {
+	from := "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n13\n14\n15\n"
+	to := "1\n2\nthree\n4\n5\n6\n7\n8\n9\n10\n11\n12\n13\n15\n16\n"
+
+	expected := `--- from
++++ to
+@@ -1,6 +1,6 @@
+ 1
+ 2
+-3
++three
+ 4
+ 5
+ 6
+@@ -11,5 +11,5 @@
+ 11
+ 12
+ 13
+-14
+ 15
++16
+`
+	assert.Equal(t, UnifiedDiff("from", "to", from, to), expected)
+}
+
+func TestUnifiedDiffMergesCloseChanges(t *testing.T) {
+	from := "a\nb\nc\nd\ne\nf\ng\nh\n"
+	to := "A\nb\nc\nd\ne\nf\ng\nH\n"
+
+	expected := `--- from
++++ to
+@@ -1,8 +1,8 @@
+-a
++A
+ b
+ c
+ d
+ e
+ f
+ g
+-h
++H
+`
+	assert.Equal(t, UnifiedDiff("from", "to", from, to), expected)
+}
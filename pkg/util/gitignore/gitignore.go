@@ -0,0 +1,158 @@
+package gitignore
+
+import (
+	"bufio"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// FileName is the name of the files of the ignored patterns, which apply to the paths under their directory
+const FileName = ".gitignore"
+
+type pattern struct {
+	// base is the directory of the .gitignore file of the pattern, relative to the root and empty for the root
+	base     string
+	regexp   *regexp.Regexp
+	negate   bool
+	dirOnly  bool
+	anchored bool
+}
+
+// Matcher matches slash-separated paths, relative to a root directory, against the patterns of the .gitignore files
+// of the root and its sub directories. As git, the last pattern which matches a path decides if it is ignored
+type Matcher struct {
+	patterns []pattern
+}
+
+func New() *Matcher {
+	return &Matcher{}
+}
+
+// ReadFile adds the patterns of the .gitignore file of a directory, given relative to the root. A directory without
+// a .gitignore file has no patterns
+func (m *Matcher) ReadFile(base, filePath string) error {
+	file, err := os.Open(filePath)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	lines := []string{}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	if err = scanner.Err(); err != nil {
+		return err
+	}
+	m.AddPatterns(base, lines)
+	return nil
+}
+
+// AddPatterns adds the lines of a .gitignore file of a directory, given relative to the root
+func (m *Matcher) AddPatterns(base string, lines []string) {
+	for _, line := range lines {
+		line = strings.TrimRight(line, " \t\r")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		p := pattern{base: strings.Trim(base, "/")}
+		if strings.HasPrefix(line, "!") {
+			p.negate = true
+			line = line[1:]
+		} else if strings.HasPrefix(line, `\!`) || strings.HasPrefix(line, `\#`) {
+			line = line[1:]
+		}
+		if strings.HasSuffix(line, "/") {
+			p.dirOnly = true
+			line = strings.TrimRight(line, "/")
+		}
+		// A pattern with a slash other than a trailing one is relative to its directory, and one without a slash
+		// matches the name of a path in any level under it
+		p.anchored = strings.Contains(line, "/")
+		line = strings.TrimPrefix(line, "/")
+		if line == "" {
+			continue
+		}
+
+		compiled, err := regexp.Compile(globToRegexp(line))
+		if err != nil {
+			continue
+		}
+		p.regexp = compiled
+		m.patterns = append(m.patterns, p)
+	}
+}
+
+// Match returns whether the path, relative to the root, is ignored
+func (m *Matcher) Match(relPath string, isDir bool) bool {
+	relPath = strings.Trim(relPath, "/")
+	ignored := false
+	for _, p := range m.patterns {
+		if p.dirOnly && !isDir {
+			continue
+		}
+
+		target := relPath
+		if p.base != "" {
+			if !strings.HasPrefix(relPath, p.base+"/") {
+				continue
+			}
+			target = strings.TrimPrefix(relPath, p.base+"/")
+		}
+		if !p.anchored {
+			target = path.Base(target)
+		}
+
+		if p.regexp.MatchString(target) {
+			ignored = !p.negate
+		}
+	}
+	return ignored
+}
+
+// globToRegexp converts a gitignore glob, where * and ? do not match a slash and ** matches any number of
+// directories, to a regular expression
+func globToRegexp(glob string) string {
+	builder := strings.Builder{}
+	builder.WriteString("^")
+	for i := 0; i < len(glob); i++ {
+		c := glob[i]
+		switch {
+		case strings.HasPrefix(glob[i:], "**/"):
+			builder.WriteString("(.*/)?")
+			i += 2
+		case strings.HasPrefix(glob[i:], "**"):
+			builder.WriteString(".*")
+			i++
+		case c == '*':
+			builder.WriteString("[^/]*")
+		case c == '?':
+			builder.WriteString("[^/]")
+		case c == '[':
+			end := strings.IndexByte(glob[i+1:], ']')
+			if end < 0 {
+				builder.WriteString(`\[`)
+				continue
+			}
+			class := glob[i+1 : i+1+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			builder.WriteString("[" + class + "]")
+			i += end + 1
+		case c == '\\' && i+1 < len(glob):
+			i++
+			builder.WriteString(regexp.QuoteMeta(string(glob[i])))
+		default:
+			builder.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	builder.WriteString("$")
+	return builder.String()
+}
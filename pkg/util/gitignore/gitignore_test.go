@@ -0,0 +1,58 @@
+package gitignore
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+func TestMatch(t *testing.T) {
+	matcher := New()
+	matcher.AddPatterns("", []string{
+		"# comment",
+		"*.pyc",
+		"__pycache__/",
+		"/build",
+		"logs/**/*.log",
+		"data/raw",
+		"*.ckpt",
+		"!best.ckpt",
+		"",
+	})
+	matcher.AddPatterns("models", []string{"*.pt", "/local"})
+
+	tests := []struct {
+		path    string
+		isDir   bool
+		ignored bool
+	}{
+		{"train.py", false, false},
+		{"train.pyc", false, true},
+		{"src/utils/helpers.pyc", false, true},
+		{"src/__pycache__", true, true},
+		{"src/__pycache__", false, false},
+		{"build", true, true},
+		{"src/build", true, false},
+		{"logs/run1/train.log", false, true},
+		{"logs/train.log", false, true},
+		{"src/logs/train.log", false, false},
+		{"data/raw", true, true},
+		{"src/data/raw", true, false},
+		{"epoch1.ckpt", false, true},
+		{"out/best.ckpt", false, false},
+		{"models/resnet.pt", false, true},
+		{"resnet.pt", false, false},
+		{"models/local", true, true},
+		{"models/sub/local", true, false},
+	}
+	for _, test := range tests {
+		assert.Equal(t, matcher.Match(test.path, test.isDir), test.ignored, test.path)
+	}
+}
+
+func TestGlobToRegexp(t *testing.T) {
+	assert.Equal(t, globToRegexp("*.py"), `^[^/]*\.py$`)
+	assert.Equal(t, globToRegexp("a/**/b"), `^a/(.*/)?b$`)
+	assert.Equal(t, globToRegexp("file[0-9]?"), `^file[0-9][^/]$`)
+	assert.Equal(t, globToRegexp("[!a]"), `^[^a]$`)
+}
@@ -15,213 +15,141 @@
 package helm
 
 import (
+	"errors"
 	"fmt"
-	"io/ioutil"
-	"os"
-	"os/exec"
 	"path/filepath"
 	"regexp"
 	"strings"
 
-	util "github.com/run-ai/runai-cli/pkg/util"
 	log "github.com/sirupsen/logrus"
+	yaml "gopkg.in/yaml.v2"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/engine"
+	"helm.sh/helm/v3/pkg/releaseutil"
 )
 
-type HELM_VERSION string
-
 const (
-	HELM_3              HELM_VERSION = "helm-3"
-	HELM_2              HELM_VERSION = "helm-2"
-	versionCaptureRegex              = "(?:.*\\n)*(v(\\d+)\\.\\d*.\\d*).*"
-)
-
-var (
-	helmVersion HELM_VERSION
+	localRepositoryPrefix = "file://"
+	renderErrorRegex      = `fail "(.*?)"`
 )
 
-func init() {
-}
-
-func getHelmVersion() (HELM_VERSION, error) {
-	binary, err := exec.LookPath(helmCmd[0])
+/*
+* Generate the values of a chart, as they would be written to a values file
+ */
+func GenerateValues(values interface{}) ([]byte, error) {
+	log.Debugf("values: %+v", values)
+	data, err := yaml.Marshal(values)
 	if err != nil {
-		return "", err
+		log.Errorf("Failed to marshal value %v due to %v", values, err)
+		return nil, err
 	}
-
-	args := []string{binary, "version", "--client", "--short"}
-
-	cmd := exec.Command("bash", "-c", strings.Join(args, " "))
-	out, err := cmd.CombinedOutput()
-
-	output := string(out)
-	return getHelmVersionFromOutput(output)
+	return data, nil
 }
 
-func getHelmVersionFromOutput(output string) (HELM_VERSION, error) {
-	re, err := regexp.Compile(versionCaptureRegex)
+/**
+* Load a chart directory or archive, including its dependencies from local repositories
+* which were not packaged into the chart (the same as helm dependency update does)
+ */
+func LoadChart(chartPath string) (*chart.Chart, error) {
+	loadedChart, err := loader.Load(chartPath)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	res := re.FindStringSubmatch(output)
-	if len(res) < 3 {
-		return "", fmt.Errorf("Could not find helm command version")
+	loadedDependencies := map[string]bool{}
+	for _, dependency := range loadedChart.Dependencies() {
+		loadedDependencies[dependency.Name()] = true
 	}
 
-	majorVersion := res[2]
-	if majorVersion == "3" {
-		return HELM_3, nil
-	} else if majorVersion == "2" {
-		return HELM_2, nil
-	} else {
-		return "", fmt.Errorf("Could not identify helm version")
-	}
-}
+	for _, dependency := range loadedChart.Metadata.Dependencies {
+		if loadedDependencies[dependency.Name] || !strings.HasPrefix(dependency.Repository, localRepositoryPrefix) {
+			continue
+		}
 
-/*
-* Generate value file
- */
-func GenerateValueFile(values interface{}) (valueFileName string, err error) {
-	// 1. generate the template file
-	valueFile, err := ioutil.TempFile(os.TempDir(), "values")
-	if err != nil {
-		log.Errorf("Failed to create tmp file %v due to %v", valueFile.Name(), err)
-		return "", err
+		dependencyPath := strings.TrimPrefix(dependency.Repository, localRepositoryPrefix)
+		if !filepath.IsAbs(dependencyPath) {
+			dependencyPath = filepath.Join(chartPath, dependencyPath)
+		}
+		dependencyChart, err := LoadChart(dependencyPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load the dependency %s of chart %s: %v", dependency.Name, loadedChart.Name(), err)
+		}
+		loadedChart.AddDependency(dependencyChart)
 	}
 
-	valueFileName = valueFile.Name()
-	log.Debugf("Save the values file %s", valueFileName)
-
-	// 2. dump the object into the template file
-	err = toYaml(values, valueFile)
-	return valueFileName, err
+	return loadedChart, nil
 }
 
 /**
-* generate helm template without tiller: helm template -f values.yaml chart_name
-* Exec /usr/local/bin/helm, [template -f /tmp/values313606961 --namespace default --name hj /charts/tf-horovod]
-* returns generated template file: templateFileName
+* Render the chart in-process, the same as: helm template name chart -f values.yaml --namespace namespace
+* returns the generated manifest, with the objects sorted in install order
  */
-func GenerateHelmTemplate(name string, namespace string, valueFileName string, chartName string) (templateFileName string, err error) {
-	tempName := fmt.Sprintf("%s.yaml", name)
-	templateFile, err := ioutil.TempFile("", tempName)
+func RenderChart(name string, namespace string, values []byte, chartPath string) (manifest string, err error) {
+	loadedChart, err := LoadChart(chartPath)
 	if err != nil {
-		return templateFileName, err
+		return "", err
 	}
-	templateFileName = templateFile.Name()
 
-	binary, err := exec.LookPath(helmCmd[0])
+	chartValues, err := chartutil.ReadValues(values)
 	if err != nil {
-		return templateFileName, err
+		return "", err
 	}
 
-	// 3. check if the chart file exists
-	// if strings.HasPrefix(chartName, "/") {
-
-	if _, err = os.Stat(chartName); err != nil {
-		return templateFileName, err
+	if err = chartutil.ProcessDependencies(loadedChart, chartValues); err != nil {
+		return "", err
 	}
-	// }
-
-	helmVersion, err := getHelmVersion()
 
+	options := chartutil.ReleaseOptions{
+		Name:      name,
+		Namespace: namespace,
+		Revision:  1,
+		IsInstall: true,
+	}
+	renderValues, err := chartutil.ToRenderValues(loadedChart, chartValues, options, chartutil.DefaultCapabilities)
 	if err != nil {
 		return "", err
 	}
 
-	args := []string{binary, "template"}
-
-	if helmVersion == HELM_3 {
-		args = append(args, name)
-	} else {
-		args = append(args, "--name", name)
-	}
-	if log.GetLevel() == log.DebugLevel {
-		args = append(args, "--debug")
+	log.Debugf("Rendering chart %s as %s", chartPath, name)
+	files, err := engine.Render(loadedChart, renderValues)
+	if err != nil {
+		return "", getRenderError(err)
 	}
 
-	args = util.AddNamespaceToArgs(args, namespace)
-	args = append(args, chartName, "-f", valueFileName, ">", templateFileName)
-
-	log.Debugf("Exec bash -c %v", args)
-
-	// return syscall.Exec(cmd, args, env)
-	// 5. execute the command
-	log.Debugf("Generating template  %v", args)
-	cmd := exec.Command("bash", "-c", strings.Join(args, " "))
-	// cmd.Env = env
-	out, err := cmd.CombinedOutput()
+	_, manifests, err := releaseutil.SortManifests(files, chartutil.DefaultCapabilities.APIVersions, releaseutil.InstallOrder)
 	if err != nil {
-		failReason, _ := GetHelmTemplateError(string(out))
-		return templateFileName, fmt.Errorf("Failed to execute %s, %v with:\n%v", binary, args, failReason)
-	} else {
-		fmt.Printf("%s", string(out))
+		return "", err
 	}
 
-	// // 6. clean up the value file if needed
-	// if log.GetLevel() != log.DebugLevel {
-	// 	err = os.Remove(valueFileName)
-	// 	if err != nil {
-	// 		log.Warnf("Failed to delete %s due to %v", valueFileName, err)
-	// 	}
-	// }
-
-	return templateFileName, nil
-}
-
-func GetHelmTemplateError(output string) (string, error) {
-	re, err := regexp.Compile(`fail "(.*?)"`)
-	if err != nil {
-		return output, err
+	var builder strings.Builder
+	for _, manifest := range manifests {
+		fmt.Fprintf(&builder, "---\n# Source: %s\n%s\n", manifest.Name, manifest.Content)
 	}
+	return builder.String(), nil
+}
 
-	res := re.FindStringSubmatch(output)
+// getRenderError returns the message of a template that failed on purpose, without the location in the chart
+func getRenderError(err error) error {
+	re := regexp.MustCompile(renderErrorRegex)
+	res := re.FindStringSubmatch(err.Error())
 	if len(res) < 2 {
-		return output, nil
+		return err
 	}
-	return res[1], nil
+	return errors.New(res[1])
 }
 
 /**
 * Check the chart version by given the chart directory
-* helm inspect chart /charts/tf-horovod
  */
 
-func GetChartVersion(chart string) (version string, err error) {
-	binary, err := exec.LookPath(helmCmd[0])
+func GetChartVersion(chartPath string) (version string, err error) {
+	loadedChart, err := loader.Load(chartPath)
 	if err != nil {
 		return "", err
 	}
-
-	// 1. check if the chart file exists, if it's it's unix path, then check if it's exist
-	// if strings.HasPrefix(chart, "/") {
-	if _, err = os.Stat(chart); err != nil {
-		return "", err
-	}
-	// }
-
-	// 2. prepare the arguments
-	args := []string{binary, "inspect", "chart", chart,
-		"|", "grep", "version:"}
-	log.Debugf("Exec bash -c %v", args)
-
-	cmd := exec.Command("bash", "-c", strings.Join(args, " "))
-	out, err := cmd.Output()
-	if err != nil {
-		return "", err
-	}
-
-	lines := strings.Split(string(out), "\n")
-	if len(lines) == 0 {
-		return "", fmt.Errorf("Failed to find version when executing %s, result is %s", args, out)
-	}
-	fields := strings.Split(lines[0], ":")
-	if len(fields) != 2 {
-		return "", fmt.Errorf("Failed to find version when executing %s, result is %s", args, out)
-	}
-
-	version = strings.TrimSpace(fields[1])
-	return version, nil
+	return loadedChart.Metadata.Version, nil
 }
 
 func GetChartName(chart string) string {
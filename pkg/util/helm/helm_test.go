@@ -1,34 +1,130 @@
 package helm
 
 import (
-	"gotest.tools/assert"
+	"strings"
 	"testing"
+
+	"gotest.tools/assert"
 )
 
-func TestGetHelmVersionFromOutputOnlyVersion(t *testing.T) {
-	output := "v3.3.4+ga61ce56"
-	version, _ := getHelmVersionFromOutput(output)
-	assert.Equal(t, version, HELM_3)
+const runaiChartPath = "../../../charts/runai"
+
+func TestGetChartVersion(t *testing.T) {
+	version, err := GetChartVersion(runaiChartPath)
+	assert.NilError(t, err)
+	assert.Equal(t, version, "1.0.3")
 }
 
-func TestGetHelmVersionFromOutputWithOutputWarnings(t *testing.T) {
-	output := "WARNING: Kubernetes configuration file is group-readable. This is insecure. Location: /home/olegi/.kube/config\nWARNING: Kubernetes configuration file is world-readable. This is insecure. Location: /home/olegi/.kube/config\nv3.3.4+ga61ce56"
-	version, _ := getHelmVersionFromOutput(output)
-	assert.Equal(t, version, HELM_3)
+func TestRenderChart(t *testing.T) {
+	values := []byte("image: ubuntu\nisRunaiJob: true\ngitSync: {}\nlabels:\n  team: vision\n")
+	manifest, err := RenderChart("my-job", "runai-team", values, runaiChartPath)
+	assert.NilError(t, err)
+
+	assert.Assert(t, strings.Contains(manifest, "kind: \"RunaiJob\""))
+	assert.Assert(t, strings.Contains(manifest, "name: my-job"))
+	assert.Assert(t, strings.Contains(manifest, "image: ubuntu"))
+	assert.Assert(t, strings.Contains(manifest, "team: \"vision\""))
 }
 
-func TestGetHelmVersionFromOutputWithStringNoVersion(t *testing.T) {
-	output := "WARNING: Kubernetes configuration file is group-readable. This is insecure. Location: /home/olegi/.kube/config"
-	_, err := getHelmVersionFromOutput(output)
+func TestRenderChartTemplateFailure(t *testing.T) {
+	values := []byte("image: ubuntu\ngitSync: {}\nserviceType: nodeport\n")
+	_, err := RenderChart("my-job", "runai-team", values, runaiChartPath)
 	if err == nil {
 		t.FailNow()
 	}
+	assert.Equal(t, err.Error(), "Ports must be provided when exposing a service.")
 }
 
-func TestGetHelmVersionFromOutputWithEmptyString(t *testing.T) {
-	output := ""
-	_, err := getHelmVersionFromOutput(output)
+func TestRenderChartMissingChart(t *testing.T) {
+	_, err := RenderChart("my-job", "runai-team", []byte{}, "../../../charts/does-not-exist")
 	if err == nil {
 		t.FailNow()
 	}
 }
+
+func TestRenderChartGitSyncSecrets(t *testing.T) {
+	values := []byte(`image: ubuntu
+isRunaiJob: true
+gitSync:
+  sync: true
+  image: git-sync
+  repository: git@github.com:run-ai/docs.git
+  branch: master
+  directory: /code
+  useCredentials: true
+  username: runai
+  passwordSecret:
+    key: password
+  sshKeySecret:
+    name: deploy-keys
+    key: docs
+`)
+	manifest, err := RenderChart("my-job", "runai-team", values, runaiChartPath)
+	assert.NilError(t, err)
+
+	expected := []string{
+		"- name: GIT_SYNC_PASSWORD\n",
+		"secretKeyRef:\n",
+		"name: my-job-git-sync\n",
+		"key: password\n",
+		"- name: GIT_SYNC_SSH\n",
+		"value: /etc/git-secret/ssh\n",
+		"mountPath: /etc/git-secret\n",
+		"secretName: deploy-keys\n",
+		"- key: docs\n",
+		"- key: known_hosts\n",
+		"value: /etc/git-secret/known_hosts\n",
+		"fsGroup: 65533\n",
+	}
+	for _, part := range expected {
+		assert.Assert(t, strings.Contains(manifest, part), "missing %q in:\n%s", part, manifest)
+	}
+	assert.Assert(t, !strings.Contains(manifest, "GIT_KNOWN_HOSTS"))
+}
+
+func TestRenderChartGitSyncSkipHostKeyCheck(t *testing.T) {
+	values := []byte(`image: ubuntu
+isRunaiJob: true
+gitSync:
+  sync: true
+  image: git-sync
+  repository: git@github.com:run-ai/docs.git
+  branch: master
+  directory: /code
+  sshKeySecret:
+    name: deploy-keys
+    key: docs
+  skipHostKeyCheck: true
+`)
+	manifest, err := RenderChart("my-job", "runai-team", values, runaiChartPath)
+	assert.NilError(t, err)
+
+	assert.Assert(t, strings.Contains(manifest, "- name: GIT_KNOWN_HOSTS\n"))
+	assert.Assert(t, !strings.Contains(manifest, "known_hosts"))
+}
+
+func TestRenderChartSSHAuthorizedKeys(t *testing.T) {
+	values := []byte(`image: ubuntu
+isRunaiJob: true
+gitSync: {}
+ssh:
+  authorizedKeysSecret:
+    name: my-keys
+    key: authorized_keys
+  authorizedKeysPath: /root/.ssh/authorized_keys
+`)
+	manifest, err := RenderChart("my-job", "runai-team", values, runaiChartPath)
+	assert.NilError(t, err)
+
+	expected := []string{
+		"- mountPath: /root/.ssh/authorized_keys\n",
+		"name: ssh-authorized-keys\n",
+		"subPath: authorized_keys\n",
+		"secretName: my-keys\n",
+		"- key: authorized_keys\n",
+		"path: authorized_keys\n",
+	}
+	for _, part := range expected {
+		assert.Assert(t, strings.Contains(manifest, part), "missing %q in:\n%s", part, manifest)
+	}
+}
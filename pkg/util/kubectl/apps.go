@@ -0,0 +1,225 @@
+package kubectl
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/restmapper"
+)
+
+// AppsClient installs and uninstalls the objects of an app, the same as kubectl apply and kubectl delete do
+type AppsClient struct {
+	dynamicClient dynamic.Interface
+	mapper        meta.RESTMapper
+}
+
+func NewAppsClient(dynamicClient dynamic.Interface, discoveryClient discovery.DiscoveryInterface) *AppsClient {
+	mapper := restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(discoveryClient))
+	return NewAppsClientWithMapper(dynamicClient, mapper)
+}
+
+func NewAppsClientWithMapper(dynamicClient dynamic.Interface, mapper meta.RESTMapper) *AppsClient {
+	return &AppsClient{
+		dynamicClient: dynamicClient,
+		mapper:        mapper,
+	}
+}
+
+/**
+* parse the objects of a multi document manifest, skipping empty documents
+**/
+func ParseApps(manifest string) ([]*unstructured.Unstructured, error) {
+	apps := []*unstructured.Unstructured{}
+	decoder := utilyaml.NewYAMLOrJSONDecoder(strings.NewReader(manifest), 4096)
+	for {
+		object := map[string]interface{}{}
+		err := decoder.Decode(&object)
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+
+		if len(object) == 0 {
+			continue
+		}
+		apps = append(apps, &unstructured.Unstructured{Object: object})
+	}
+	return apps, nil
+}
+
+/**
+* App info is the list of the objects of an app, saved so they can be uninstalled later.
+* Every line is <kind>.<group>/<name>, the way kubectl prints created objects
+**/
+func GetAppInfo(apps []*unstructured.Unstructured) string {
+	lines := []string{}
+	for _, app := range apps {
+		gvk := app.GroupVersionKind()
+		kind := strings.ToLower(gvk.Kind)
+		if gvk.Group != "" {
+			kind = fmt.Sprintf("%s.%s", kind, gvk.Group)
+		}
+		lines = append(lines, fmt.Sprintf("%s/%s", kind, app.GetName()))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func parseAppInfoLine(line string) (schema.GroupResource, string, error) {
+	fields := strings.SplitN(line, "/", 2)
+	if len(fields) != 2 || fields[0] == "" || fields[1] == "" {
+		return schema.GroupResource{}, "", fmt.Errorf("invalid app info line: %s", line)
+	}
+	return schema.ParseGroupResource(fields[0]), fields[1], nil
+}
+
+func (c *AppsClient) resourceInterface(mapping *meta.RESTMapping, namespace string) dynamic.ResourceInterface {
+	if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+		return c.dynamicClient.Resource(mapping.Resource).Namespace(namespace)
+	}
+	return c.dynamicClient.Resource(mapping.Resource)
+}
+
+/**
+* Create the objects of the app, or update the ones which already exist
+**/
+func (c *AppsClient) InstallApps(apps []*unstructured.Unstructured, namespace string) error {
+	for _, app := range apps {
+		err := c.applyApp(app, namespace, []string{})
+		if err != nil {
+			return fmt.Errorf("failed to apply %s %s: %v", strings.ToLower(app.GetKind()), app.GetName(), err)
+		}
+		log.Debugf("Applied %s %s", strings.ToLower(app.GetKind()), app.GetName())
+	}
+	return nil
+}
+
+/**
+* Send the objects of the app to the API server with dryRun=All, so they go through admission (webhooks, quotas
+* and policies) without being persisted. All the objects are checked, and the rejections of all of them are returned
+**/
+func (c *AppsClient) DryRunApps(apps []*unstructured.Unstructured, namespace string) error {
+	rejections := []string{}
+	for _, app := range apps {
+		// Do not modify the objects of the app, the dry run sets their namespace and resource version
+		err := c.applyApp(app.DeepCopy(), namespace, []string{metav1.DryRunAll})
+		if err != nil {
+			rejections = append(rejections, describeRejection(app, err))
+			continue
+		}
+		log.Debugf("Dry run of %s %s passed", strings.ToLower(app.GetKind()), app.GetName())
+	}
+
+	if len(rejections) > 0 {
+		return fmt.Errorf("the dry run was rejected by the server:\n%s", strings.Join(rejections, "\n"))
+	}
+	return nil
+}
+
+func (c *AppsClient) applyApp(app *unstructured.Unstructured, namespace string, dryRun []string) error {
+	gvk := app.GroupVersionKind()
+	mapping, err := c.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return err
+	}
+
+	if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+		app.SetNamespace(namespace)
+	}
+	resourceInterface := c.resourceInterface(mapping, namespace)
+
+	_, err = resourceInterface.Create(app, metav1.CreateOptions{DryRun: dryRun})
+	if errors.IsAlreadyExists(err) {
+		var existing *unstructured.Unstructured
+		existing, err = resourceInterface.Get(app.GetName(), metav1.GetOptions{})
+		if err == nil {
+			app.SetResourceVersion(existing.GetResourceVersion())
+			_, err = resourceInterface.Update(app, metav1.UpdateOptions{DryRun: dryRun})
+		}
+	}
+	return err
+}
+
+// describeRejection returns why the API server rejected an object, with the offending field of every cause it reported
+func describeRejection(app *unstructured.Unstructured, err error) string {
+	description := fmt.Sprintf("  %s %s: %v", strings.ToLower(app.GetKind()), app.GetName(), err)
+	if reason := errors.ReasonForError(err); reason != metav1.StatusReasonUnknown {
+		description = fmt.Sprintf("  %s %s (%s): %v", strings.ToLower(app.GetKind()), app.GetName(), reason, err)
+	}
+	if status, isStatus := err.(errors.APIStatus); isStatus && status.Status().Details != nil {
+		for _, cause := range status.Status().Details.Causes {
+			field := cause.Field
+			if field == "" {
+				field = "<unknown field>"
+			}
+			description += fmt.Sprintf("\n    %s: %s", field, cause.Message)
+		}
+	}
+	return description
+}
+
+/**
+* Delete the objects listed in the app info. All the objects are deleted even if some of them fail,
+* and the first failure is returned
+**/
+func (c *AppsClient) UninstallApps(appInfo string, namespace string) error {
+	var firstErr error
+	deleted := 0
+	for _, line := range strings.Split(appInfo, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		err := c.uninstallApp(line, namespace)
+		if err != nil {
+			log.Debugf("Failed to delete %s: %v", line, err)
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		deleted++
+	}
+
+	if firstErr != nil {
+		return firstErr
+	}
+	if deleted == 0 {
+		return fmt.Errorf("nothing was deleted")
+	}
+	return nil
+}
+
+func (c *AppsClient) uninstallApp(appInfoLine string, namespace string) error {
+	groupResource, name, err := parseAppInfoLine(appInfoLine)
+	if err != nil {
+		return err
+	}
+
+	gvk, err := c.mapper.KindFor(groupResource.WithVersion(""))
+	if err != nil {
+		return err
+	}
+	mapping, err := c.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return err
+	}
+
+	err = c.resourceInterface(mapping, namespace).Delete(name, &metav1.DeleteOptions{})
+	if err == nil {
+		log.Debugf("Deleted %s", appInfoLine)
+	}
+	return err
+}
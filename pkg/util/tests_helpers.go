@@ -3,6 +3,9 @@
 package util
 
 import (
+	"strconv"
+	"time"
+
 	"github.com/run-ai/runai-cli/cmd/constants"
 	fakeclientset "github.com/run-ai/runai-cli/cmd/mpi/client/clientset/versioned/fake"
 	"github.com/run-ai/runai-cli/cmd/util"
@@ -126,3 +129,35 @@ func (fpqc *FakePrometheusQueryClient) GroupMultiQueriesToItems(queryMap map[str
 func FakePrometheusClient(metrics prom.MetricResultsByItems, err error) prom.QueryClient {
 	return &FakePrometheusQueryClient{metrics: metrics, err: err}
 }
+
+// FakePrometheusRangeQueryClient fake prom.RangeQueryClient
+type FakePrometheusRangeQueryClient struct {
+	results map[string]*prom.MetricData
+	err     error
+}
+
+// QueryRange for the fake client will just return the result stored for the query, or an empty matrix
+func (fprqc *FakePrometheusRangeQueryClient) QueryRange(query string, start, end time.Time, step time.Duration) (*prom.MetricData, error) {
+	if fprqc.err != nil {
+		return nil, fprqc.err
+	}
+	if result, found := fprqc.results[query]; found {
+		return result, nil
+	}
+	return &prom.MetricData{ResultType: prom.MatrixResultType, Result: []prom.MetricResult{}}, nil
+}
+
+// FakePrometheusRangeClient Creates a fake client to query prometheus over a range of time, by the results of every query
+func FakePrometheusRangeClient(results map[string]*prom.MetricData, err error) prom.RangeQueryClient {
+	return &FakePrometheusRangeQueryClient{results: results, err: err}
+}
+
+// FakeMatrixResult returns a series of a range query with the given values, a value every step from start
+func FakeMatrixResult(metric map[string]string, start time.Time, step time.Duration, values ...float64) prom.MetricResult {
+	result := prom.MetricResult{Metric: metric, Values: [][]prom.MetricValue{}}
+	for i, value := range values {
+		timestamp := float64(start.Add(time.Duration(i)*step).UnixNano()) / float64(time.Second)
+		result.Values = append(result.Values, []prom.MetricValue{timestamp, strconv.FormatFloat(value, 'f', -1, 64)})
+	}
+	return result
+}
@@ -0,0 +1,183 @@
+package workflow
+
+import (
+	"fmt"
+
+	"github.com/run-ai/runai-cli/pkg/types"
+	log "github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// Set to "true" on the configmaps of pipelines, and to the pipeline name on the jobs of its stages
+	PipelineLabelSelectorName = "runai-pipeline"
+
+	pipelineConfigMapSuffix  = "pipeline"
+	pipelineConfigMapDataKey = "pipeline"
+)
+
+// The states of a pipeline stage, a submitted stage takes the status of its job
+const (
+	PipelineStageWaiting   = "Waiting"
+	PipelineStageSubmitted = "Submitted"
+	PipelineStageSkipped   = "Skipped"
+)
+
+type Pipeline struct {
+	Name   string          `yaml:"name" json:"name"`
+	Stages []PipelineStage `yaml:"stages" json:"stages"`
+}
+
+type PipelineStage struct {
+	Name      string   `yaml:"name" json:"name"`
+	JobName   string   `yaml:"jobName" json:"jobName"`
+	DependsOn []string `yaml:"dependsOn,omitempty" json:"dependsOn,omitempty"`
+	State     string   `yaml:"state" json:"state"`
+}
+
+func getPipelineConfigMapName(pipelineName string) string {
+	return fmt.Sprintf("%s-%s", pipelineName, pipelineConfigMapSuffix)
+}
+
+// CreatePipeline records the graph of a new pipeline in a configmap. If the pipeline already exists with the same
+// stages, e.g. its submission was interrupted, the recorded pipeline is returned so that its submission is resumed
+func CreatePipeline(pipeline *Pipeline, namespace string, clientset kubernetes.Interface) (*Pipeline, bool, error) {
+	data, err := yaml.Marshal(pipeline)
+	if err != nil {
+		return nil, false, err
+	}
+
+	// The configmap has no base name label, it is not the configmap of a job
+	configMap := corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: getPipelineConfigMapName(pipeline.Name),
+			Labels: map[string]string{
+				PipelineLabelSelectorName: "true",
+			},
+		},
+		Data: map[string]string{
+			pipelineConfigMapDataKey: string(data),
+		},
+	}
+
+	_, err = clientset.CoreV1().ConfigMaps(namespace).Create(&configMap)
+	if !errors.IsAlreadyExists(err) {
+		return pipeline, false, err
+	}
+
+	existingConfigMap, err := clientset.CoreV1().ConfigMaps(namespace).Get(configMap.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, false, err
+	}
+	existingPipeline, err := getPipelineFromConfigMap(existingConfigMap)
+	if err != nil {
+		return nil, false, err
+	}
+	if existingConfigMap.Labels[PipelineLabelSelectorName] != "true" || !sameStages(existingPipeline, pipeline) {
+		return nil, false, fmt.Errorf("the pipeline %s already exists with other stages, please delete it first (use 'runai delete --pipeline %s')", pipeline.Name, pipeline.Name)
+	}
+	return existingPipeline, true, nil
+}
+
+// sameStages returns whether the pipelines have the same stages and dependencies, regardless of their state
+func sameStages(pipeline *Pipeline, otherPipeline *Pipeline) bool {
+	if pipeline.Name != otherPipeline.Name || len(pipeline.Stages) != len(otherPipeline.Stages) {
+		return false
+	}
+	for i, stage := range pipeline.Stages {
+		otherStage := otherPipeline.Stages[i]
+		if stage.Name != otherStage.Name || stage.JobName != otherStage.JobName || len(stage.DependsOn) != len(otherStage.DependsOn) {
+			return false
+		}
+		for j, dependency := range stage.DependsOn {
+			if dependency != otherStage.DependsOn[j] {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// UpdatePipeline saves the current state of the pipeline stages
+func UpdatePipeline(pipeline *Pipeline, namespace string, clientset kubernetes.Interface) error {
+	data, err := yaml.Marshal(pipeline)
+	if err != nil {
+		return err
+	}
+
+	configMap, err := clientset.CoreV1().ConfigMaps(namespace).Get(getPipelineConfigMapName(pipeline.Name), metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	configMap.Data[pipelineConfigMapDataKey] = string(data)
+	_, err = clientset.CoreV1().ConfigMaps(namespace).Update(configMap)
+	return err
+}
+
+func GetPipeline(pipelineName string, namespaceInfo types.NamespaceInfo, clientset kubernetes.Interface) (*Pipeline, error) {
+	configMap, err := clientset.CoreV1().ConfigMaps(namespaceInfo.Namespace).Get(getPipelineConfigMapName(pipelineName), metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return nil, fmt.Errorf("the pipeline %s does not exist in project %s. If the pipeline exists in a different project, use -p <project name>.", pipelineName, namespaceInfo.ProjectName)
+	} else if err != nil {
+		return nil, err
+	}
+
+	return getPipelineFromConfigMap(configMap)
+}
+
+func ListPipelines(namespace string, clientset kubernetes.Interface) ([]Pipeline, error) {
+	configMaps, err := clientset.CoreV1().ConfigMaps(namespace).List(metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=true", PipelineLabelSelectorName),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	pipelines := []Pipeline{}
+	for i := range configMaps.Items {
+		pipeline, err := getPipelineFromConfigMap(&configMaps.Items[i])
+		if err != nil {
+			log.Debugf("Failed to read pipeline from configmap %s due to %v", configMaps.Items[i].Name, err)
+			continue
+		}
+		pipelines = append(pipelines, *pipeline)
+	}
+	return pipelines, nil
+}
+
+func getPipelineFromConfigMap(configMap *corev1.ConfigMap) (*Pipeline, error) {
+	pipeline := &Pipeline{}
+	if err := yaml.Unmarshal([]byte(configMap.Data[pipelineConfigMapDataKey]), pipeline); err != nil {
+		return nil, fmt.Errorf("could not parse the pipeline of configmap %s: %v", configMap.Name, err)
+	}
+	return pipeline, nil
+}
+
+// DeletePipeline deletes the jobs of the submitted stages of the pipeline and then the pipeline itself
+func DeletePipeline(pipelineName string, namespaceInfo types.NamespaceInfo, clientset kubernetes.Interface) error {
+	pipeline, err := GetPipeline(pipelineName, namespaceInfo, clientset)
+	if err != nil {
+		return err
+	}
+
+	for _, stage := range pipeline.Stages {
+		if stage.State != PipelineStageSubmitted {
+			continue
+		}
+		if err = DeleteJob(stage.JobName, namespaceInfo, clientset); err != nil {
+			log.Debugf("Failed to delete job %s of pipeline %s due to %v", stage.JobName, pipelineName, err)
+		}
+	}
+
+	err = clientset.CoreV1().ConfigMaps(namespaceInfo.Namespace).Delete(getPipelineConfigMapName(pipelineName), &metav1.DeleteOptions{})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Successfully deleted pipeline: %s\n", pipelineName)
+	return nil
+}
@@ -0,0 +1,51 @@
+package workflow
+
+import (
+	"testing"
+
+	"github.com/run-ai/runai-cli/pkg/types"
+	"gotest.tools/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func newTestPipeline() *Pipeline {
+	return &Pipeline{Name: "quickstart", Stages: []PipelineStage{
+		{Name: "preprocess", JobName: "quickstart-preprocess", State: PipelineStageWaiting},
+		{Name: "train", JobName: "quickstart-train", DependsOn: []string{"preprocess"}, State: PipelineStageWaiting},
+	}}
+}
+
+func TestCreatePipelineResumesTheSamePipeline(t *testing.T) {
+	clientset := fake.NewSimpleClientset()
+
+	pipeline, resumed, err := CreatePipeline(newTestPipeline(), testNamespace, clientset)
+	assert.NilError(t, err)
+	assert.Equal(t, resumed, false)
+	configMap, err := clientset.CoreV1().ConfigMaps(testNamespace).Get("quickstart-pipeline", metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, configMap.Labels[BaseNameLabelSelectorName], "")
+
+	pipeline.Stages[0].State = PipelineStageSubmitted
+	assert.NilError(t, UpdatePipeline(pipeline, testNamespace, clientset))
+
+	pipeline, resumed, err = CreatePipeline(newTestPipeline(), testNamespace, clientset)
+	assert.NilError(t, err)
+	assert.Equal(t, resumed, true)
+	assert.Equal(t, pipeline.Stages[0].State, PipelineStageSubmitted)
+	assert.Equal(t, pipeline.Stages[1].State, PipelineStageWaiting)
+
+	otherPipeline := newTestPipeline()
+	otherPipeline.Stages[1].DependsOn = nil
+	_, _, err = CreatePipeline(otherPipeline, testNamespace, clientset)
+	assert.ErrorContains(t, err, "already exists with other stages")
+}
+
+func TestGetJobValuesIgnoresPipelines(t *testing.T) {
+	clientset := fake.NewSimpleClientset()
+	_, _, err := CreatePipeline(newTestPipeline(), testNamespace, clientset)
+	assert.NilError(t, err)
+
+	_, err = GetJobValues("quickstart-pipeline", types.NamespaceInfo{Namespace: testNamespace, ProjectName: "team"}, clientset)
+	assert.ErrorContains(t, err, "does not exist")
+}
@@ -0,0 +1,164 @@
+package workflow
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/run-ai/runai-cli/cmd/constants"
+	runaiClient "github.com/run-ai/runai-cli/cmd/mpi/client/clientset/versioned"
+	"github.com/run-ai/runai-cli/pkg/types"
+	log "github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/retry"
+)
+
+// scaleWorkloadFunc changes the number of pods of a workload, given its metadata and its replicas or parallelism
+type scaleWorkloadFunc func(metadata *metav1.ObjectMeta, replicas *int32) error
+
+// SuspendJob scales the workload of a job down to zero pods, which releases its GPUs. Unlike deleting the job, the
+// workload and the configmap of the job are kept, annotated with the replicas or parallelism to restore on resume
+func SuspendJob(jobName string, workloadType string, namespaceInfo types.NamespaceInfo, clientset kubernetes.Interface, runaiClientset runaiClient.Interface) error {
+	var suspendedReplicas string
+	err := scaleWorkload(jobName, workloadType, namespaceInfo.Namespace, clientset, runaiClientset, func(metadata *metav1.ObjectMeta, replicas *int32) error {
+		if err := suspendWorkload(metadata, replicas); err != nil {
+			return err
+		}
+		suspendedReplicas = metadata.Annotations[constants.WorkloadSuspendedReplicas]
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	updateJobConfigMapAnnotations(jobName, namespaceInfo, clientset, func(annotations map[string]string) {
+		annotations[constants.WorkloadSuspendedReplicas] = suspendedReplicas
+	})
+	return nil
+}
+
+// ResumeJob restores the replicas or parallelism which the workload of a suspended job had before it was suspended
+func ResumeJob(jobName string, workloadType string, namespaceInfo types.NamespaceInfo, clientset kubernetes.Interface, runaiClientset runaiClient.Interface) error {
+	err := scaleWorkload(jobName, workloadType, namespaceInfo.Namespace, clientset, runaiClientset, resumeWorkload)
+	if err != nil {
+		return err
+	}
+
+	updateJobConfigMapAnnotations(jobName, namespaceInfo, clientset, func(annotations map[string]string) {
+		delete(annotations, constants.WorkloadSuspendedReplicas)
+	})
+	return nil
+}
+
+// updateJobConfigMapAnnotations annotates the configmap of the job, which holds the spec it was submitted with. Jobs
+// which were not submitted by the CLI have no configmap
+func updateJobConfigMapAnnotations(jobName string, namespaceInfo types.NamespaceInfo, clientset kubernetes.Interface, update func(annotations map[string]string)) {
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		configMap, err := getServerConfigMapByJob(jobName, namespaceInfo, clientset)
+		if err != nil {
+			log.Debugf("Could not find the configmap of job %s: %v", jobName, err)
+			return nil
+		}
+		if configMap.Annotations == nil {
+			configMap.Annotations = map[string]string{}
+		}
+		update(configMap.Annotations)
+		_, err = clientset.CoreV1().ConfigMaps(namespaceInfo.Namespace).Update(configMap)
+		return err
+	})
+	if err != nil {
+		log.Warnf("Failed to annotate the configmap of job %s: %v", jobName, err)
+	}
+}
+
+func suspendWorkload(metadata *metav1.ObjectMeta, replicas *int32) error {
+	if _, isSuspended := metadata.Annotations[constants.WorkloadSuspendedReplicas]; isSuspended {
+		return fmt.Errorf("the job %s is already suspended", metadata.Name)
+	}
+	if metadata.Annotations == nil {
+		metadata.Annotations = map[string]string{}
+	}
+	metadata.Annotations[constants.WorkloadSuspendedReplicas] = strconv.Itoa(int(*replicas))
+	*replicas = 0
+	return nil
+}
+
+func resumeWorkload(metadata *metav1.ObjectMeta, replicas *int32) error {
+	suspendedReplicas, isSuspended := metadata.Annotations[constants.WorkloadSuspendedReplicas]
+	if !isSuspended {
+		return fmt.Errorf("the job %s is not suspended", metadata.Name)
+	}
+	previousReplicas, err := strconv.Atoi(suspendedReplicas)
+	if err != nil {
+		return fmt.Errorf("invalid annotation %s of job %s: %v", constants.WorkloadSuspendedReplicas, metadata.Name, err)
+	}
+	delete(metadata.Annotations, constants.WorkloadSuspendedReplicas)
+	*replicas = int32(previousReplicas)
+	return nil
+}
+
+// scaleWorkload scales the replicas of a deployment or a statefulset, or the parallelism of a job or a runaijob
+func scaleWorkload(jobName string, workloadType string, namespace string, clientset kubernetes.Interface, runaiClientset runaiClient.Interface, scale scaleWorkloadFunc) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		switch workloadType {
+		case string(types.ResourceTypeRunaiJob):
+			job, err := runaiClientset.RunV1().RunaiJobs(namespace).Get(jobName, metav1.GetOptions{})
+			if err != nil {
+				return err
+			}
+			parallelism := replicasOrDefault(job.Spec.Parallelism)
+			if err = scale(&job.ObjectMeta, &parallelism); err != nil {
+				return err
+			}
+			job.Spec.Parallelism = &parallelism
+			_, err = runaiClientset.RunV1().RunaiJobs(namespace).Update(job, metav1.UpdateOptions{})
+			return err
+		case string(types.ResourceTypeJob):
+			job, err := clientset.BatchV1().Jobs(namespace).Get(jobName, metav1.GetOptions{})
+			if err != nil {
+				return err
+			}
+			parallelism := replicasOrDefault(job.Spec.Parallelism)
+			if err = scale(&job.ObjectMeta, &parallelism); err != nil {
+				return err
+			}
+			job.Spec.Parallelism = &parallelism
+			_, err = clientset.BatchV1().Jobs(namespace).Update(job)
+			return err
+		case string(types.ResourceTypeStatefulSet):
+			statefulSet, err := clientset.AppsV1().StatefulSets(namespace).Get(jobName, metav1.GetOptions{})
+			if err != nil {
+				return err
+			}
+			replicas := replicasOrDefault(statefulSet.Spec.Replicas)
+			if err = scale(&statefulSet.ObjectMeta, &replicas); err != nil {
+				return err
+			}
+			statefulSet.Spec.Replicas = &replicas
+			_, err = clientset.AppsV1().StatefulSets(namespace).Update(statefulSet)
+			return err
+		case string(types.ResourceTypeDeployment):
+			deployment, err := clientset.AppsV1().Deployments(namespace).Get(jobName, metav1.GetOptions{})
+			if err != nil {
+				return err
+			}
+			replicas := replicasOrDefault(deployment.Spec.Replicas)
+			if err = scale(&deployment.ObjectMeta, &replicas); err != nil {
+				return err
+			}
+			deployment.Spec.Replicas = &replicas
+			_, err = clientset.AppsV1().Deployments(namespace).Update(deployment)
+			return err
+		default:
+			return fmt.Errorf("the job %s cannot be suspended or resumed, as it is of type %s", jobName, workloadType)
+		}
+	})
+}
+
+// Both the replicas and the parallelism of workloads default to one
+func replicasOrDefault(replicas *int32) int32 {
+	if replicas == nil {
+		return 1
+	}
+	return *replicas
+}
@@ -0,0 +1,105 @@
+package workflow
+
+import (
+	"testing"
+
+	"github.com/run-ai/runai-cli/cmd/constants"
+	runaijobv1 "github.com/run-ai/runai-cli/cmd/mpi/api/runaijob/v1"
+	runaifake "github.com/run-ai/runai-cli/cmd/mpi/client/clientset/versioned/fake"
+	"github.com/run-ai/runai-cli/pkg/types"
+	"gotest.tools/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+var testNamespaceInfo = types.NamespaceInfo{Namespace: testNamespace, ProjectName: "team"}
+
+func int32P(value int32) *int32 {
+	return &value
+}
+
+func newTestObjectMeta(name string) metav1.ObjectMeta {
+	return metav1.ObjectMeta{Name: name, Namespace: testNamespace}
+}
+
+func TestSuspendAndResumeRunaiJob(t *testing.T) {
+	clientset := fake.NewSimpleClientset(&corev1.ConfigMap{ObjectMeta: newTestObjectMeta("my-job")})
+	runaiClientset := runaifake.NewSimpleClientset(&runaijobv1.RunaiJob{
+		ObjectMeta: newTestObjectMeta("my-job"),
+		Spec:       runaijobv1.JobSpec{Parallelism: int32P(3)},
+	})
+
+	assert.NilError(t, SuspendJob("my-job", string(types.ResourceTypeRunaiJob), testNamespaceInfo, clientset, runaiClientset))
+
+	job, err := runaiClientset.RunV1().RunaiJobs(testNamespace).Get("my-job", metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, *job.Spec.Parallelism, int32(0))
+	assert.Equal(t, job.Annotations[constants.WorkloadSuspendedReplicas], "3")
+	configMap, err := clientset.CoreV1().ConfigMaps(testNamespace).Get("my-job", metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, configMap.Annotations[constants.WorkloadSuspendedReplicas], "3")
+
+	err = SuspendJob("my-job", string(types.ResourceTypeRunaiJob), testNamespaceInfo, clientset, runaiClientset)
+	assert.ErrorContains(t, err, "the job my-job is already suspended")
+
+	assert.NilError(t, ResumeJob("my-job", string(types.ResourceTypeRunaiJob), testNamespaceInfo, clientset, runaiClientset))
+
+	job, err = runaiClientset.RunV1().RunaiJobs(testNamespace).Get("my-job", metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, *job.Spec.Parallelism, int32(3))
+	_, isSuspended := job.Annotations[constants.WorkloadSuspendedReplicas]
+	assert.Assert(t, !isSuspended)
+	configMap, err = clientset.CoreV1().ConfigMaps(testNamespace).Get("my-job", metav1.GetOptions{})
+	assert.NilError(t, err)
+	_, isSuspended = configMap.Annotations[constants.WorkloadSuspendedReplicas]
+	assert.Assert(t, !isSuspended)
+
+	err = ResumeJob("my-job", string(types.ResourceTypeRunaiJob), testNamespaceInfo, clientset, runaiClientset)
+	assert.ErrorContains(t, err, "the job my-job is not suspended")
+}
+
+func TestSuspendAndResumeJobWithoutParallelism(t *testing.T) {
+	// A job which was not submitted by the CLI has no configmap
+	clientset := fake.NewSimpleClientset(&batchv1.Job{ObjectMeta: newTestObjectMeta("my-job")})
+
+	assert.NilError(t, SuspendJob("my-job", string(types.ResourceTypeJob), testNamespaceInfo, clientset, runaifake.NewSimpleClientset()))
+
+	job, err := clientset.BatchV1().Jobs(testNamespace).Get("my-job", metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, *job.Spec.Parallelism, int32(0))
+	assert.Equal(t, job.Annotations[constants.WorkloadSuspendedReplicas], "1")
+
+	assert.NilError(t, ResumeJob("my-job", string(types.ResourceTypeJob), testNamespaceInfo, clientset, runaifake.NewSimpleClientset()))
+
+	job, err = clientset.BatchV1().Jobs(testNamespace).Get("my-job", metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, *job.Spec.Parallelism, int32(1))
+}
+
+func TestSuspendAndResumeDeployment(t *testing.T) {
+	clientset := fake.NewSimpleClientset(&appsv1.Deployment{
+		ObjectMeta: newTestObjectMeta("my-job"),
+		Spec:       appsv1.DeploymentSpec{Replicas: int32P(2)},
+	})
+
+	assert.NilError(t, SuspendJob("my-job", string(types.ResourceTypeDeployment), testNamespaceInfo, clientset, runaifake.NewSimpleClientset()))
+
+	deployment, err := clientset.AppsV1().Deployments(testNamespace).Get("my-job", metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, *deployment.Spec.Replicas, int32(0))
+
+	assert.NilError(t, ResumeJob("my-job", string(types.ResourceTypeDeployment), testNamespaceInfo, clientset, runaifake.NewSimpleClientset()))
+
+	deployment, err = clientset.AppsV1().Deployments(testNamespace).Get("my-job", metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, *deployment.Spec.Replicas, int32(2))
+}
+
+func TestSuspendUnsupportedWorkload(t *testing.T) {
+	err := SuspendJob("my-job", string(types.ResourceTypePod), testNamespaceInfo, fake.NewSimpleClientset(), runaifake.NewSimpleClientset())
+
+	assert.ErrorContains(t, err, "the job my-job cannot be suspended or resumed, as it is of type Pod")
+}
@@ -3,7 +3,6 @@ package workflow
 import (
 	"fmt"
 	"io/ioutil"
-	"os"
 	"strconv"
 
 	mpiClient "github.com/run-ai/runai-cli/cmd/mpi/client/clientset/versioned"
@@ -12,7 +11,9 @@ import (
 	cmdUtil "github.com/run-ai/runai-cli/cmd/util"
 	"github.com/run-ai/runai-cli/pkg/client"
 	"github.com/run-ai/runai-cli/pkg/types"
+	"github.com/run-ai/runai-cli/pkg/util"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 
 	"github.com/run-ai/runai-cli/pkg/util/helm"
 	"github.com/run-ai/runai-cli/pkg/util/kubectl"
@@ -28,44 +29,76 @@ const (
 	configMapGenerationRetries = 5
 )
 
-type JobFiles struct {
-	valueFileName   string
-	template        string
-	appInfoFileName string
+// OwnedSecretsProvider is implemented by the values of jobs which hold credentials. The credentials are not part of the
+// values, which are stored in the configmap of the job. Secrets are created for them instead, owned by the configmap so
+// they are garbage-collected when the job is deleted
+type OwnedSecretsProvider interface {
+	OwnedSecrets(jobName string) []*corev1.Secret
+}
+
+type jobApps struct {
+	values   []byte
+	manifest string
+	apps     []*unstructured.Unstructured
 }
 
 /**
 *	delete training job with the job name
 **/
 
-func getServerConfigMapNameByJob(jobName string, namespaceInfo types.NamespaceInfo, clientset kubernetes.Interface) (string, error) {
+func getServerConfigMapByJob(jobName string, namespaceInfo types.NamespaceInfo, clientset kubernetes.Interface) (*corev1.ConfigMap, error) {
 	namespace := namespaceInfo.Namespace
 	maybeConfigMapNames := []string{jobName, fmt.Sprintf("%s-%s", jobName, "runai"), fmt.Sprintf("%s-%s", jobName, "mpijob")}
 	for _, maybeConfigMapName := range maybeConfigMapNames {
 		configMap, err := clientset.CoreV1().ConfigMaps(namespace).Get(maybeConfigMapName, metav1.GetOptions{})
-		if err == nil {
-			return configMap.Name, nil
+		// the configmap of a pipeline named like the job is not the configmap of the job
+		if err == nil && configMap.Labels[PipelineLabelSelectorName] == "" {
+			return configMap, nil
 		}
 	}
-	return "", cmdUtil.GetJobDoesNotExistsInNamespaceError(jobName, namespaceInfo)
+	return nil, cmdUtil.GetJobDoesNotExistsInNamespaceError(jobName, namespaceInfo)
+}
+
+// GetJobValues returns the values file the job was submitted with, as saved in the job's configmap
+func GetJobValues(jobName string, namespaceInfo types.NamespaceInfo, clientset kubernetes.Interface) (string, error) {
+	configMap, err := getServerConfigMapByJob(jobName, namespaceInfo, clientset)
+	if err != nil {
+		return "", err
+	}
+	return configMap.Data["values"], nil
+}
+
+// GetJobChartValues returns the values file of a job, which must have been submitted with the given chart
+func GetJobChartValues(jobName string, namespaceInfo types.NamespaceInfo, chart string, clientset kubernetes.Interface) (string, error) {
+	configMap, err := getServerConfigMapByJob(jobName, namespaceInfo, clientset)
+	if err != nil {
+		return "", err
+	}
+	chartName := helm.GetChartName(chart)
+	if _, found := configMap.Data[chartName]; !found {
+		return "", fmt.Errorf("the job %s is not a %s job", jobName, chartName)
+	}
+	return configMap.Data["values"], nil
 }
 
 func DeleteJob(jobName string, namespaceInfo types.NamespaceInfo, clientset kubernetes.Interface) error {
-	namespace := namespaceInfo.Namespace
-	configMapName, err := getServerConfigMapNameByJob(jobName, namespaceInfo, clientset)
+	appsClient, err := getAppsClient(clientset)
 	if err != nil {
-		log.Debugf("Failed to find configmap for, error: %v\n", err)
-		return deleteJobResourcesWithoutConfigMap(jobName, namespaceInfo, clientset)
+		return err
 	}
+	return deleteJobInternal(jobName, namespaceInfo, clientset, appsClient)
+}
 
-	appInfoFileName, err := kubectl.SaveAppConfigMapToFile(configMapName, "app", namespace)
+func deleteJobInternal(jobName string, namespaceInfo types.NamespaceInfo, clientset kubernetes.Interface, appsClient *kubectl.AppsClient) error {
+	namespace := namespaceInfo.Namespace
+	configMap, err := getServerConfigMapByJob(jobName, namespaceInfo, clientset)
 	if err != nil {
-		log.Debugf("Failed to SaveAppConfigMapToFile due to %v\n", err)
+		log.Debugf("Failed to find configmap for, error: %v\n", err)
 		return deleteJobResourcesWithoutConfigMap(jobName, namespaceInfo, clientset)
 	}
+	configMapName := configMap.Name
 
-	result, err := kubectl.UninstallAppsWithAppInfoFile(appInfoFileName, namespace)
-	log.Debugf("%s", result)
+	err = appsClient.UninstallApps(configMap.Data["app"], namespace)
 	if err != nil {
 		log.Debugf("Failed to remove some of the job's resources, they might have been removed manually and not by using Run:AI CLI.\n")
 		return deleteJobResourcesWithoutConfigMap(jobName, namespaceInfo, clientset)
@@ -142,45 +175,50 @@ func deleteAdditionalJobResources(jobName, namespace string, clientset kubernete
 	}
 }
 
+// getAppsClient returns the client which installs and uninstalls the objects rendered from the job charts
+func getAppsClient(clientset kubernetes.Interface) (*kubectl.AppsClient, error) {
+	kubeClient, err := client.GetClient()
+	if err != nil {
+		return nil, err
+	}
+	return kubectl.NewAppsClient(kubeClient.GetDynamicClient(), clientset.Discovery()), nil
+}
+
 /**
 *	Submit training job
 **/
 
-func generateJobFiles(name string, namespace string, values interface{}, chart string) (*JobFiles, error) {
-	valueFileName, err := helm.GenerateValueFile(values)
+func generateJobApps(name string, namespace string, values interface{}, chart string) (*jobApps, error) {
+	valuesContent, err := helm.GenerateValues(values)
 	if err != nil {
 		return nil, err
 	}
 
-	// 2. Generate Template file
-	template, err := helm.GenerateHelmTemplate(name, namespace, valueFileName, chart)
+	manifest, err := helm.RenderChart(name, namespace, valuesContent, chart)
 	if err != nil {
-		cleanupSingleFile(valueFileName)
 		return nil, err
 	}
 
-	// 3. Generate AppInfo file
-	appInfoFileName, err := kubectl.SaveAppInfo(template, namespace)
+	apps, err := kubectl.ParseApps(manifest)
 	if err != nil {
-		cleanupSingleFile(template)
-		cleanupSingleFile(valueFileName)
 		return nil, err
 	}
 
-	jobFiles := &JobFiles{
-		valueFileName:   valueFileName,
-		template:        template,
-		appInfoFileName: appInfoFileName,
-	}
-
-	return jobFiles, nil
-
+	return &jobApps{
+		values:   valuesContent,
+		manifest: manifest,
+		apps:     apps,
+	}, nil
 }
 
 func getConfigMapLabelSelector(configMapName string) string {
 	return fmt.Sprintf("%s=%s", BaseNameLabelSelectorName, configMapName)
 }
 
+func jobAlreadyExistsError(name string) error {
+	return fmt.Errorf("the job %s already exists, please delete it first (use 'runai delete %s')", name, name)
+}
+
 func getSmallestUnoccupiedIndex(configMaps []corev1.ConfigMap) int {
 	occupationMap := make(map[string]bool)
 	for _, configMap := range configMaps {
@@ -217,7 +255,7 @@ func submitConfigMap(name, namespace string, generateSuffix bool, clientset kube
 
 	if !generateSuffix {
 		log.Debugf("Failed to create job name: <%v>, error: <%v>", name, err)
-		return nil, fmt.Errorf("the job %s already exists, please delete it first (use 'runai delete %s')", name, name)
+		return nil, jobAlreadyExistsError(name)
 	}
 
 	configMapLabelSelector := getConfigMapLabelSelector(name)
@@ -258,52 +296,27 @@ func createEmptyConfigMap(name, baseName, namespace string, index int, clientset
 	return acceptedConfigMap, nil
 }
 
-func populateConfigMap(configMap *corev1.ConfigMap, chartName, chartVersion, valuesFileName, appInfoFileName, namespace string, clientset kubernetes.Interface) error {
+func populateConfigMap(configMap *corev1.ConfigMap, chartName, chartVersion string, jobApps *jobApps, namespace string, clientset kubernetes.Interface) error {
 	data := make(map[string]string)
 	data[chartName] = chartVersion
-	valuesFileContent, err := ioutil.ReadFile(valuesFileName)
-	if err != nil {
-		return err
-	}
-	data["values"] = string(valuesFileContent)
-	appFileContent, err := ioutil.ReadFile(appInfoFileName)
-	if err != nil {
-		return err
-	}
-
-	data["app"] = string(appFileContent)
+	data["values"] = string(jobApps.values)
+	data["app"] = kubectl.GetAppInfo(jobApps.apps)
 
 	configMap.Data = data
-	_, err = clientset.CoreV1().ConfigMaps(namespace).Update(configMap)
+	_, err := clientset.CoreV1().ConfigMaps(namespace).Update(configMap)
 	return err
 }
 
-func cleanupSingleFile(file string) {
-	if _, err := os.Stat(file); err == nil {
-		err = os.Remove(file)
-		if err != nil {
-			log.Warnf("Failed to delete %s due to %v", file, err)
-		}
-	}
-}
-
-func cleanupJobFiles(files *JobFiles) {
-	cleanupSingleFile(files.valueFileName)
-	cleanupSingleFile(files.template)
-	cleanupSingleFile(files.appInfoFileName)
-}
-
-func submitJobInternal(name, namespace string, generateSuffix bool, values interface{}, chart string, clientset kubernetes.Interface) (string, error) {
+func submitJobInternal(name, namespace string, generateSuffix bool, values interface{}, chart string, clientset kubernetes.Interface, appsClient *kubectl.AppsClient) (string, error) {
 	configMap, err := submitConfigMap(name, namespace, generateSuffix, clientset)
 	if err != nil {
 		return "", err
 	}
 	jobName := configMap.Name
-	jobFiles, err := generateJobFiles(jobName, namespace, values, chart)
+	jobApps, err := generateJobApps(jobName, namespace, values, chart)
 	if err != nil {
 		return jobName, err
 	}
-	defer cleanupJobFiles(jobFiles)
 
 	chartName := helm.GetChartName(chart)
 	chartVersion, err := helm.GetChartVersion(chart)
@@ -311,35 +324,165 @@ func submitJobInternal(name, namespace string, generateSuffix bool, values inter
 		return jobName, err
 	}
 
-	err = populateConfigMap(configMap, chartName, chartVersion, jobFiles.valueFileName, jobFiles.appInfoFileName, namespace, clientset)
+	err = populateConfigMap(configMap, chartName, chartVersion, jobApps, namespace, clientset)
+	if err != nil {
+		return jobName, err
+	}
+
+	err = createOwnedSecrets(values, configMap, namespace, clientset)
 	if err != nil {
 		return jobName, err
 	}
 
-	_, err = kubectl.UninstallAppsWithAppInfoFile(jobFiles.appInfoFileName, namespace)
+	err = appsClient.UninstallApps(configMap.Data["app"], namespace)
 	if err != nil {
-		log.Debugf("Failed to UninstallAppsWithAppInfoFile due to %v", err)
+		log.Debugf("Failed to uninstall the previous apps of the job due to %v", err)
 	}
-	_, err = kubectl.InstallApps(jobFiles.template, namespace)
+	err = appsClient.InstallApps(jobApps.apps, namespace)
 	if err != nil {
 		return jobName, err
 	}
 	return jobName, nil
 }
 
-func SubmitJob(name, namespace string, generateSuffix bool, values interface{}, chart string, clientset kubernetes.Interface, dryRun bool) (string, error) {
-	if dryRun {
-		jobFiles, err := generateJobFiles(name, namespace, values, chart)
+// createOwnedSecrets creates the secrets of the credentials of the job, owned by its configmap
+func createOwnedSecrets(values interface{}, configMap *corev1.ConfigMap, namespace string, clientset kubernetes.Interface) error {
+	provider, hasSecrets := values.(OwnedSecretsProvider)
+	if !hasSecrets {
+		return nil
+	}
+
+	for _, secret := range provider.OwnedSecrets(configMap.Name) {
+		secret.Namespace = namespace
+		secret.OwnerReferences = []metav1.OwnerReference{{
+			APIVersion: "v1",
+			Kind:       "ConfigMap",
+			Name:       configMap.Name,
+			UID:        configMap.UID,
+		}}
+
+		_, err := clientset.CoreV1().Secrets(namespace).Create(secret)
+		if errors.IsAlreadyExists(err) {
+			err = updateOwnedSecret(secret, configMap, namespace, clientset)
+		}
 		if err != nil {
-			return "", err
+			return fmt.Errorf("failed to create the secret %s of the job: %v", secret.Name, err)
+		}
+		log.Debugf("Created the secret %s of the job", secret.Name)
+	}
+	return nil
+}
+
+// updateOwnedSecret updates a secret which the job created before, e.g. when it is submitted again. A secret which
+// the job does not own, e.g. of the user, is left as is, as the job would delete it along with the job
+func updateOwnedSecret(secret *corev1.Secret, configMap *corev1.ConfigMap, namespace string, clientset kubernetes.Interface) error {
+	existing, err := clientset.CoreV1().Secrets(namespace).Get(secret.Name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	for _, owner := range existing.OwnerReferences {
+		if owner.Kind == "ConfigMap" && owner.UID == configMap.UID {
+			secret.ResourceVersion = existing.ResourceVersion
+			_, err = clientset.CoreV1().Secrets(namespace).Update(secret)
+			return err
+		}
+	}
+	return fmt.Errorf("a secret of the same name which is not owned by the job already exists")
+}
+
+// writeTemplateFile saves the rendered job so it can be inspected after a dry run
+func writeTemplateFile(name string, manifest string) (string, error) {
+	templateFile, err := ioutil.TempFile("", fmt.Sprintf("%s.yaml", name))
+	if err != nil {
+		return "", err
+	}
+	defer templateFile.Close()
+
+	_, err = templateFile.WriteString(manifest)
+	return templateFile.Name(), err
+}
+
+// dryRunJobInternal checks the job with the API server without submitting it, the same checks as submitting it does
+func dryRunJobInternal(name, namespace string, generateSuffix bool, values interface{}, chart string, clientset kubernetes.Interface, appsClient *kubectl.AppsClient) error {
+	jobApps, err := generateJobApps(name, namespace, values, chart)
+	if err != nil {
+		return err
+	}
+
+	templateFileName, err := writeTemplateFile(name, jobApps.manifest)
+	if err != nil {
+		return err
+	}
+	fmt.Println("Template YAML file can be found at:")
+	fmt.Println(templateFileName)
+
+	if !generateSuffix {
+		if _, err = clientset.CoreV1().ConfigMaps(namespace).Get(name, metav1.GetOptions{}); err == nil {
+			return jobAlreadyExistsError(name)
 		}
-		fmt.Println("Template YAML file can be found at:")
-		fmt.Println(jobFiles.template)
-		return "", nil
 	}
-	jobName, err := submitJobInternal(name, namespace, generateSuffix, values, chart, clientset)
+
+	err = appsClient.DryRunApps(jobApps.apps, namespace)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("The job '%s' passed the server-side dry run, it was not submitted\n", name)
+	return nil
+}
+
+func SubmitJob(name, namespace string, generateSuffix bool, values interface{}, chart string, clientset kubernetes.Interface, dryRun bool) (string, error) {
+	appsClient, err := getAppsClient(clientset)
+	if err != nil {
+		return "", err
+	}
+	if dryRun {
+		return name, dryRunJobInternal(name, namespace, generateSuffix, values, chart, clientset, appsClient)
+	}
+	jobName, err := submitJobInternal(name, namespace, generateSuffix, values, chart, clientset, appsClient)
 	if err != nil {
 		return "", err
 	}
 	return jobName, nil
 }
+
+/**
+*	Diff a job with the existing job of the same name
+**/
+
+// DiffJob returns the differences between the objects of the job and the ones of the existing job of the same name.
+// The existing job is rendered again from the values it was submitted with, so only changes of the submission show up.
+func DiffJob(name string, namespaceInfo types.NamespaceInfo, values interface{}, chart string, clientset kubernetes.Interface) (string, error) {
+	configMap, err := getServerConfigMapByJob(name, namespaceInfo, clientset)
+	if err != nil {
+		return "", err
+	}
+	return diffJobWithConfigMap(name, configMap, namespaceInfo.Namespace, values, chart)
+}
+
+func diffJobWithConfigMap(name string, configMap *corev1.ConfigMap, namespace string, values interface{}, chart string) (string, error) {
+	chartName := helm.GetChartName(chart)
+	existingChartVersion, found := configMap.Data[chartName]
+	if !found {
+		return "", fmt.Errorf("the job %s is not a %s job, it cannot be compared", name, chartName)
+	}
+
+	chartVersion, err := helm.GetChartVersion(chart)
+	if err != nil {
+		return "", err
+	}
+	if existingChartVersion != chartVersion {
+		log.Warnf("The job %s was submitted with version %s of the chart, and is compared using version %s", name, existingChartVersion, chartVersion)
+	}
+
+	existingManifest, err := helm.RenderChart(name, namespace, []byte(configMap.Data["values"]), chart)
+	if err != nil {
+		return "", fmt.Errorf("failed to render the existing job %s: %v", name, err)
+	}
+
+	jobApps, err := generateJobApps(name, namespace, values, chart)
+	if err != nil {
+		return "", err
+	}
+
+	return util.UnifiedDiff(fmt.Sprintf("%s (existing)", name), fmt.Sprintf("%s (new)", name), existingManifest, jobApps.manifest), nil
+}
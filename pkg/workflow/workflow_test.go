@@ -0,0 +1,218 @@
+package workflow
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/run-ai/runai-cli/pkg/types"
+	"github.com/run-ai/runai-cli/pkg/util/kubectl"
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+const (
+	testNamespace  = "runai-team"
+	runaiChartPath = "../../charts/runai"
+)
+
+var runaiJobResource = schema.GroupVersionResource{Group: "run.ai", Version: "v1", Resource: "runaijobs"}
+
+func newFakeAppsClient() (*kubectl.AppsClient, *dynamicfake.FakeDynamicClient) {
+	mapper := meta.NewDefaultRESTMapper([]schema.GroupVersion{})
+	mapper.Add(schema.GroupVersionKind{Group: "run.ai", Version: "v1", Kind: "RunaiJob"}, meta.RESTScopeNamespace)
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "Service"}, meta.RESTScopeNamespace)
+
+	dynamicClient := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme())
+	return kubectl.NewAppsClientWithMapper(dynamicClient, mapper), dynamicClient
+}
+
+func newTestJobValues(image string) map[string]interface{} {
+	return map[string]interface{}{
+		"image":      image,
+		"isRunaiJob": true,
+		"gitSync":    map[string]interface{}{},
+	}
+}
+
+func TestSubmitAndDeleteJob(t *testing.T) {
+	clientset := fake.NewSimpleClientset()
+	appsClient, dynamicClient := newFakeAppsClient()
+	values := newTestJobValues("ubuntu")
+
+	jobName, err := submitJobInternal("my-job", testNamespace, false, values, runaiChartPath, clientset, appsClient)
+	assert.NilError(t, err)
+	assert.Equal(t, jobName, "my-job")
+
+	configMap, err := clientset.CoreV1().ConfigMaps(testNamespace).Get(jobName, metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, configMap.Data["app"], "runaijob.run.ai/my-job")
+	assert.Equal(t, configMap.Data["runai"], "1.0.3")
+	assert.Assert(t, configMap.Data["values"] != "")
+
+	job, err := dynamicClient.Resource(runaiJobResource).Namespace(testNamespace).Get(jobName, metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, job.GetNamespace(), testNamespace)
+
+	// Submitting a job with the same name fails, and does not touch the existing job
+	_, err = submitJobInternal("my-job", testNamespace, false, values, runaiChartPath, clientset, appsClient)
+	assert.ErrorContains(t, err, "already exists")
+
+	namespaceInfo := types.NamespaceInfo{Namespace: testNamespace, ProjectName: "team"}
+	assert.NilError(t, deleteJobInternal(jobName, namespaceInfo, clientset, appsClient))
+
+	_, err = dynamicClient.Resource(runaiJobResource).Namespace(testNamespace).Get(jobName, metav1.GetOptions{})
+	assert.Assert(t, err != nil)
+	_, err = clientset.CoreV1().ConfigMaps(testNamespace).Get(jobName, metav1.GetOptions{})
+	assert.Assert(t, err != nil)
+}
+
+func TestUninstallAppsWithUnknownResource(t *testing.T) {
+	appsClient, _ := newFakeAppsClient()
+
+	err := appsClient.UninstallApps("service/my-job\nunknown.example.com/my-job", testNamespace)
+	assert.Assert(t, err != nil)
+}
+
+func TestDryRunJobRejected(t *testing.T) {
+	clientset := fake.NewSimpleClientset()
+	appsClient, dynamicClient := newFakeAppsClient()
+	dynamicClient.PrependReactor("create", "runaijobs", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, errors.NewInvalid(schema.GroupKind{Group: "run.ai", Kind: "RunaiJob"}, "my-job", field.ErrorList{
+			field.Invalid(field.NewPath("spec", "template", "spec", "containers").Index(0).Child("resources"), "8", "exceeds the quota of the project"),
+		})
+	})
+
+	err := dryRunJobInternal("my-job", testNamespace, false, newTestJobValues("ubuntu"), runaiChartPath, clientset, appsClient)
+	assert.ErrorContains(t, err, "the dry run was rejected by the server")
+	assert.ErrorContains(t, err, "runaijob my-job (Invalid)")
+	assert.ErrorContains(t, err, "\n    spec.template.spec.containers[0].resources: Invalid value")
+
+	// The job configmap is not created by a dry run
+	_, err = clientset.CoreV1().ConfigMaps(testNamespace).Get("my-job", metav1.GetOptions{})
+	assert.Assert(t, errors.IsNotFound(err))
+}
+
+func TestDryRunExistingJob(t *testing.T) {
+	clientset := fake.NewSimpleClientset()
+	appsClient, _ := newFakeAppsClient()
+	_, err := submitJobInternal("my-job", testNamespace, false, newTestJobValues("ubuntu"), runaiChartPath, clientset, appsClient)
+	assert.NilError(t, err)
+
+	err = dryRunJobInternal("my-job", testNamespace, false, newTestJobValues("ubuntu"), runaiChartPath, clientset, appsClient)
+	assert.ErrorContains(t, err, "already exists")
+}
+
+func TestDiffJob(t *testing.T) {
+	clientset := fake.NewSimpleClientset()
+	appsClient, _ := newFakeAppsClient()
+	_, err := submitJobInternal("my-job", testNamespace, false, newTestJobValues("ubuntu"), runaiChartPath, clientset, appsClient)
+	assert.NilError(t, err)
+
+	namespaceInfo := types.NamespaceInfo{Namespace: testNamespace, ProjectName: "team"}
+	diff, err := DiffJob("my-job", namespaceInfo, newTestJobValues("ubuntu"), runaiChartPath, clientset)
+	assert.NilError(t, err)
+	assert.Equal(t, diff, "")
+
+	diff, err = DiffJob("my-job", namespaceInfo, newTestJobValues("ubuntu:20.04"), runaiChartPath, clientset)
+	assert.NilError(t, err)
+	assert.Assert(t, strings.HasPrefix(diff, "--- my-job (existing)\n+++ my-job (new)\n"))
+	assert.Assert(t, strings.Contains(diff, "\n-          image: ubuntu\n+          image: ubuntu:20.04\n"), diff)
+
+	_, err = DiffJob("other-job", namespaceInfo, newTestJobValues("ubuntu"), runaiChartPath, clientset)
+	assert.Assert(t, err != nil)
+}
+
+func TestGetJobChartValues(t *testing.T) {
+	clientset := fake.NewSimpleClientset()
+	appsClient, _ := newFakeAppsClient()
+	_, err := submitJobInternal("my-job", testNamespace, false, newTestJobValues("ubuntu"), runaiChartPath, clientset, appsClient)
+	assert.NilError(t, err)
+
+	namespaceInfo := types.NamespaceInfo{Namespace: testNamespace, ProjectName: "team"}
+	values, err := GetJobChartValues("my-job", namespaceInfo, runaiChartPath, clientset)
+	assert.NilError(t, err)
+	assert.Assert(t, strings.Contains(values, "image: ubuntu"), values)
+
+	_, err = GetJobChartValues("my-job", namespaceInfo, "../../charts/mpijob", clientset)
+	assert.ErrorContains(t, err, "the job my-job is not a mpijob job")
+
+	_, err = GetJobChartValues("other-job", namespaceInfo, runaiChartPath, clientset)
+	assert.Assert(t, err != nil)
+}
+
+type testValuesWithSecret struct {
+	Image      string                 `yaml:"image"`
+	IsRunaiJob bool                   `yaml:"isRunaiJob"`
+	GitSync    map[string]interface{} `yaml:"gitSync"`
+}
+
+func (values *testValuesWithSecret) OwnedSecrets(jobName string) []*corev1.Secret {
+	return []*corev1.Secret{{
+		ObjectMeta: metav1.ObjectMeta{Name: jobName + "-git-sync"},
+		Data:       map[string][]byte{"password": []byte("hunter2")},
+	}}
+}
+
+func TestSubmitJobWithOwnedSecrets(t *testing.T) {
+	clientset := fake.NewSimpleClientset()
+	appsClient, _ := newFakeAppsClient()
+	values := &testValuesWithSecret{Image: "ubuntu", IsRunaiJob: true, GitSync: map[string]interface{}{}}
+
+	jobName, err := submitJobInternal("my-job", testNamespace, false, values, runaiChartPath, clientset, appsClient)
+	assert.NilError(t, err)
+
+	configMap, err := clientset.CoreV1().ConfigMaps(testNamespace).Get(jobName, metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Assert(t, !strings.Contains(configMap.Data["values"], "hunter2"))
+
+	secret, err := clientset.CoreV1().Secrets(testNamespace).Get("my-job-git-sync", metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, string(secret.Data["password"]), "hunter2")
+	assert.Equal(t, len(secret.OwnerReferences), 1)
+	assert.Equal(t, secret.OwnerReferences[0].Kind, "ConfigMap")
+	assert.Equal(t, secret.OwnerReferences[0].Name, configMap.Name)
+}
+
+func TestSubmitJobKeepsSecretsNotOwnedByTheJob(t *testing.T) {
+	clientset := fake.NewSimpleClientset(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-job-git-sync", Namespace: testNamespace},
+		Data:       map[string][]byte{"password": []byte("user-password")},
+	})
+	appsClient, _ := newFakeAppsClient()
+	values := &testValuesWithSecret{Image: "ubuntu", IsRunaiJob: true, GitSync: map[string]interface{}{}}
+
+	_, err := submitJobInternal("my-job", testNamespace, false, values, runaiChartPath, clientset, appsClient)
+	assert.ErrorContains(t, err, "failed to create the secret my-job-git-sync of the job")
+
+	secret, err := clientset.CoreV1().Secrets(testNamespace).Get("my-job-git-sync", metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, string(secret.Data["password"]), "user-password")
+	assert.Equal(t, len(secret.OwnerReferences), 0)
+}
+
+func TestCreateOwnedSecretsUpdatesTheSecretsOfTheJob(t *testing.T) {
+	configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "my-job", Namespace: testNamespace, UID: "my-job-uid"}}
+	clientset := fake.NewSimpleClientset(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-job-git-sync", Namespace: testNamespace, OwnerReferences: []metav1.OwnerReference{
+			{APIVersion: "v1", Kind: "ConfigMap", Name: "my-job", UID: "my-job-uid"},
+		}},
+		Data: map[string][]byte{"password": []byte("previous-password")},
+	})
+	values := &testValuesWithSecret{Image: "ubuntu", IsRunaiJob: true, GitSync: map[string]interface{}{}}
+
+	err := createOwnedSecrets(values, configMap, testNamespace, clientset)
+	assert.NilError(t, err)
+
+	secret, err := clientset.CoreV1().Secrets(testNamespace).Get("my-job-git-sync", metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, string(secret.Data["password"]), "hunter2")
+}